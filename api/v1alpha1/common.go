@@ -22,6 +22,7 @@ import (
 	"strconv"
 
 	"fmt"
+	"regexp"
 	"strings"
 
 	autov2beta2 "k8s.io/api/autoscaling/v2beta2"
@@ -60,6 +61,33 @@ type PulsarMessaging struct {
 
 	// To replace the TLSSecret
 	TLSConfig *PulsarTLSConfig `json:"tlsConfig,omitempty"`
+
+	// ConnectionRef refers to a PulsarConnection or a ClusterPulsarConnection bundling the service URLs,
+	// the authentication and the TLS settings, it replaces the other fields
+	// +optional
+	ConnectionRef *PulsarConnectionRef `json:"connectionRef,omitempty"`
+}
+
+// PulsarConnectionKind is the kind of the connection referred by a component
+// +kubebuilder:validation:Enum=PulsarConnection;ClusterPulsarConnection
+type PulsarConnectionKind string
+
+const (
+	PulsarConnectionKindNamespaced PulsarConnectionKind = "PulsarConnection"
+	PulsarConnectionKindCluster    PulsarConnectionKind = "ClusterPulsarConnection"
+)
+
+type PulsarConnectionRef struct {
+	// Kind is PulsarConnection for a connection in the namespace of the component, or ClusterPulsarConnection
+	// +kubebuilder:default=PulsarConnection
+	Kind PulsarConnectionKind `json:"kind,omitempty"`
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+}
+
+// IsClusterScoped returns true if the reference refers to a ClusterPulsarConnection
+func (r *PulsarConnectionRef) IsClusterScoped() bool {
+	return r.Kind == PulsarConnectionKindCluster
 }
 
 type TLSConfig struct {
@@ -189,20 +217,46 @@ type PodPolicy struct {
 
 	// Env Environment variables to expose on the pulsar-function containers
 	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Monitor configures the PodMonitor or ServiceMonitor of the Prometheus Operator
+	// created to scrape the metrics of the pods
+	// +optional
+	Monitor *MonitorPolicy `json:"monitor,omitempty"`
 }
 
 type Runtime struct {
 	Java   *JavaRuntime   `json:"java,omitempty"`
 	Python *PythonRuntime `json:"python,omitempty"`
 	Golang *GoRuntime     `json:"golang,omitempty"`
+	Custom *CustomRuntime `json:"custom,omitempty"`
+}
+
+// CustomRuntime runs the instances of a function with the image of the function and a command provided by the user.
+// The command is started without a shell, with the arguments of the Java and Python runners appended: the path of
+// the function details file, the instance id, the broker service url, the auth and TLS settings and the gRPC and
+// metrics ports. The variables of the Pulsar config, auth and TLS secrets are in the environment, and so is SHARD_ID,
+// which is set from the pod index label of Kubernetes 1.28 and later, the POD_NAME variable ends with the index too.
+// +kubebuilder:validation:Optional
+type CustomRuntime struct {
+	// Command is the entrypoint of the instance
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+
+	// Args are the arguments of the command, passed before the arguments of the runners
+	Args []string `json:"args,omitempty"`
 }
 
 // JavaRuntime contains the java runtime configs
 // +kubebuilder:validation:Optional
 type JavaRuntime struct {
 	// +kubebuilder:validation:Required
-	Jar                  string            `json:"jar"`
-	JarLocation          string            `json:"jarLocation,omitempty"`
+	Jar         string `json:"jar"`
+	JarLocation string `json:"jarLocation,omitempty"`
+	// Checksum is the sha256 checksum of the downloaded package, in the form sha256:<hex>,
+	// the package is verified before the instance starts
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	Checksum             string            `json:"checksum,omitempty"`
 	ExtraDependenciesDir string            `json:"extraDependenciesDir,omitempty"`
 	Log                  *RuntimeLogConfig `json:"log,omitempty"`
 }
@@ -211,18 +265,196 @@ type JavaRuntime struct {
 // +kubebuilder:validation:Optional
 type PythonRuntime struct {
 	// +kubebuilder:validation:Required
-	Py         string            `json:"py"`
-	PyLocation string            `json:"pyLocation,omitempty"`
-	Log        *RuntimeLogConfig `json:"log,omitempty"`
+	Py         string `json:"py"`
+	PyLocation string `json:"pyLocation,omitempty"`
+	// Checksum is the sha256 checksum of the downloaded package, in the form sha256:<hex>,
+	// the package is verified before the instance starts
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	Checksum string            `json:"checksum,omitempty"`
+	Log      *RuntimeLogConfig `json:"log,omitempty"`
+	// Dependencies are the dependencies of the package installed with pip
+	Dependencies *PythonDependencies `json:"dependencies,omitempty"`
+}
+
+type PythonDependencyInstallStrategy string
+
+const (
+	// InstallOnStart lets the instance install the requirements bundled in a zip package each time it starts
+	InstallOnStart PythonDependencyInstallStrategy = "OnStart"
+	// InstallInitContainer installs the dependencies once per pod with an init container,
+	// into a volume shared with the instance and kept across the restarts of the instance
+	InstallInitContainer PythonDependencyInstallStrategy = "InitContainer"
+)
+
+const (
+	// PythonIndexUsernameKey and PythonIndexPasswordKey are the keys of the Secret with the credentials of the index
+	PythonIndexUsernameKey = "username"
+	PythonIndexPasswordKey = "password"
+)
+
+// PythonDependencies are the dependencies of a Python package, at most one source of requirements is set,
+// the dependencies of a wheel package are installed with the wheel when there is none
+// +kubebuilder:validation:Optional
+type PythonDependencies struct {
+	// Requirements are the requirement specifiers installed with pip, such as requests==2.28.1
+	Requirements []string `json:"requirements,omitempty"`
+
+	// RequirementsConfigMap refers to the key of a ConfigMap holding a requirements file
+	RequirementsConfigMap *corev1.ConfigMapKeySelector `json:"requirementsConfigMap,omitempty"`
+
+	// RequirementsFile is the path of a requirements file inside the zip package
+	RequirementsFile string `json:"requirementsFile,omitempty"`
+
+	// IndexURL is the base url of the package index used instead of the Python Package Index
+	IndexURL string `json:"indexURL,omitempty"`
+
+	// IndexSecret is the name of a Secret with the username and the password keys to authenticate to the index
+	IndexSecret string `json:"indexSecret,omitempty"`
+
+	// InstallStrategy is how the dependencies are installed, default to InitContainer
+	// +kubebuilder:validation:Enum=OnStart;InitContainer
+	InstallStrategy PythonDependencyInstallStrategy `json:"installStrategy,omitempty"`
 }
 
 // GoRuntime contains the golang runtime configs
 // +kubebuilder:validation:Optional
 type GoRuntime struct {
 	// +kubebuilder:validation:Required
-	Go         string            `json:"go"`
-	GoLocation string            `json:"goLocation,omitempty"`
-	Log        *RuntimeLogConfig `json:"log,omitempty"`
+	Go         string `json:"go"`
+	GoLocation string `json:"goLocation,omitempty"`
+	// Checksum is the sha256 checksum of the downloaded package, in the form sha256:<hex>,
+	// the package is verified before the instance starts
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	Checksum string            `json:"checksum,omitempty"`
+	Log      *RuntimeLogConfig `json:"log,omitempty"`
+}
+
+// IdlePolicy scales a component to zero replicas once the backlog of its input subscription
+// has been empty for IdleTimeoutSeconds, the component is scaled up again when messages arrive.
+// +kubebuilder:validation:Optional
+type IdlePolicy struct {
+	// IdleTimeoutSeconds is how long the subscription must have no backlog before scaling to zero
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	IdleTimeoutSeconds int32 `json:"idleTimeoutSeconds"`
+
+	// CheckIntervalSeconds is the interval of checking the backlog, default to 30 seconds
+	// +kubebuilder:validation:Minimum=1
+	CheckIntervalSeconds *int32 `json:"checkIntervalSeconds,omitempty"`
+}
+
+// RuntimeTuning tunes the instances of a component, the unset fields fall back to the runtime tuning
+// configs of the controller
+// +kubebuilder:validation:Optional
+type RuntimeTuning struct {
+	// MaxBufferedTuples is the number of messages buffered by an instance before they are processed
+	// +kubebuilder:validation:Minimum=1
+	MaxBufferedTuples *int32 `json:"maxBufferedTuples,omitempty"`
+
+	// ExpectedHealthCheckIntervalSeconds is the interval of the health checks expected by an instance,
+	// the instance exits when no health check is received in three intervals, -1 turns the health checks off
+	// +kubebuilder:validation:Minimum=-1
+	ExpectedHealthCheckIntervalSeconds *int32 `json:"expectedHealthCheckIntervalSeconds,omitempty"`
+
+	// JVM tunes the Java instances
+	JVM *JVMTuning `json:"jvm,omitempty"`
+
+	// Python tunes the Python instances
+	Python *PythonTuning `json:"python,omitempty"`
+}
+
+// JVMTuning tunes the JVM running a Java instance
+// +kubebuilder:validation:Optional
+type JVMTuning struct {
+	// HeapPercentage is the maximum heap as a percentage of the memory limit of the container,
+	// the memory request is used when there is no limit
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	HeapPercentage *int32 `json:"heapPercentage,omitempty"`
+
+	// Options are the extra options of the JVM, such as the GC options
+	Options []string `json:"options,omitempty"`
+}
+
+// PythonTuning tunes the interpreter running a Python instance
+// +kubebuilder:validation:Optional
+type PythonTuning struct {
+	// InterpreterFlags are the flags of the Python interpreter, such as -O or -X options
+	InterpreterFlags []string `json:"interpreterFlags,omitempty"`
+}
+
+type MonitorKind string
+
+const (
+	PodMonitor     MonitorKind = "PodMonitor"
+	ServiceMonitor MonitorKind = "ServiceMonitor"
+)
+
+// MonitorPolicy configures the monitor of the Prometheus Operator created for a component,
+// the unset fields fall back to the monitor configs of the controller
+// +kubebuilder:validation:Optional
+type MonitorPolicy struct {
+	// Enabled creates the monitor, default to true when the monitor policy is set
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Kind is the kind of the monitor, PodMonitor targets the pods directly
+	// and ServiceMonitor targets the headless service of the component
+	// +kubebuilder:validation:Enum=PodMonitor;ServiceMonitor
+	Kind MonitorKind `json:"kind,omitempty"`
+
+	// Interval at which the metrics are scraped, such as 30s
+	// +kubebuilder:validation:Pattern=`^([0-9]+(ms|s|m|h))+$`
+	Interval string `json:"interval,omitempty"`
+
+	// Labels are added to the monitor, so that it can be selected by the Prometheus instance
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Relabelings are applied to the samples before ingestion
+	Relabelings []RelabelConfig `json:"relabelings,omitempty"`
+}
+
+// RelabelConfig mirrors the relabeling config of the Prometheus Operator,
+// see https://prometheus.io/docs/prometheus/latest/configuration/configuration/#relabel_config
+type RelabelConfig struct {
+	// SourceLabels select the values from the existing labels
+	SourceLabels []string `json:"sourceLabels,omitempty"`
+	// Separator placed between the concatenated source label values
+	Separator string `json:"separator,omitempty"`
+	// TargetLabel is the label to which the resulting value is written in a replace action
+	TargetLabel string `json:"targetLabel,omitempty"`
+	// Regex against which the extracted value is matched
+	Regex string `json:"regex,omitempty"`
+	// Modulus to take of the hash of the source label values
+	Modulus uint64 `json:"modulus,omitempty"`
+	// Replacement value against which a regex replace is performed
+	Replacement string `json:"replacement,omitempty"`
+	// Action to perform based on regex matching
+	// +kubebuilder:validation:Enum=replace;keep;drop;hashmod;labelmap;labeldrop;labelkeep
+	Action string `json:"action,omitempty"`
+}
+
+// WindowConfig contains the windowing configs of a function
+// +kubebuilder:validation:Optional
+type WindowConfig struct {
+	// WindowLengthCount is the number of messages per window
+	WindowLengthCount *int32 `json:"windowLengthCount,omitempty"`
+	// WindowLengthDurationMs is the time duration of each window in milliseconds
+	WindowLengthDurationMs *int64 `json:"windowLengthDurationMs,omitempty"`
+	// SlidingIntervalCount is the number of messages after which the window slides,
+	// the window is a tumbling window if no sliding interval is provided
+	SlidingIntervalCount *int32 `json:"slidingIntervalCount,omitempty"`
+	// SlidingIntervalDurationMs is the time duration after which the window slides in milliseconds,
+	// the window is a tumbling window if no sliding interval is provided
+	SlidingIntervalDurationMs *int64 `json:"slidingIntervalDurationMs,omitempty"`
+	// LateDataTopic is the topic which receives the messages arriving later than the allowed lateness
+	LateDataTopic string `json:"lateDataTopic,omitempty"`
+	// MaxLagMs is the maximum lateness of a message in milliseconds
+	MaxLagMs *int64 `json:"maxLagMs,omitempty"`
+	// WatermarkEmitIntervalMs is the interval in milliseconds at which watermarks are emitted
+	WatermarkEmitIntervalMs *int64 `json:"watermarkEmitIntervalMs,omitempty"`
+	// TimestampExtractorClassName is the class used to extract the event time from messages,
+	// the messages are windowed by processing time if it is not provided, which the Python runtime always does
+	TimestampExtractorClassName *string `json:"timestampExtractorClassName,omitempty"`
 }
 
 type SecretRef struct {
@@ -345,6 +577,9 @@ const (
 
 	DefaultResourceCPU    int64 = 1
 	DefaultResourceMemory int64 = 1073741824
+
+	DefaultWindowMaxLagMs                int64 = 0
+	DefaultWindowWatermarkEmitIntervalMs int64 = 1000
 )
 
 func validResourceRequirement(requirements corev1.ResourceRequirements) bool {
@@ -377,6 +612,12 @@ const (
 	PackageURLFunction string = "function://"
 	PackageURLSource   string = "source://"
 	PackageURLSink     string = "sink://"
+	// PackageURLFile refers to a package baked into the runner image or mounted from a volume,
+	// the package is read in place instead of being downloaded
+	PackageURLFile string = "file://"
+
+	// PackageChecksumSHA256 is the prefix of the sha256 checksum of a package
+	PackageChecksumSHA256 string = "sha256:"
 )
 
 // Config represents untyped YAML configuration.
@@ -419,9 +660,14 @@ func validPackageLocation(packageLocation string) error {
 		if err != nil {
 			return err
 		}
+	} else if IsFilePackageLocation(packageLocation) {
+		err := isValidFilePackageURL(packageLocation)
+		if err != nil {
+			return err
+		}
 	} else {
 		if !isFunctionPackageURLSupported(packageLocation) {
-			return fmt.Errorf("invalid function package url %s, supported url (http/https)", packageLocation)
+			return fmt.Errorf("invalid function package url %s, supported url (http/https/file)", packageLocation)
 		}
 	}
 
@@ -458,8 +704,42 @@ func isValidPulsarPackageURL(packageLocation string) error {
 	return nil
 }
 
+// IsFilePackageLocation returns whether the package is read in place from a file:// location
+func IsFilePackageLocation(packageLocation string) bool {
+	return strings.HasPrefix(strings.ToLower(packageLocation), PackageURLFile)
+}
+
+// FilePackagePath returns the path of the package in a file:// location, an empty path means
+// that the package is the file named in the runtime, e.g. the jar of the java runtime
+func FilePackagePath(packageLocation string) string {
+	return packageLocation[len(PackageURLFile):]
+}
+
+var filePackagePathPattern = regexp.MustCompile(`^/[A-Za-z0-9._+@=/-]*$`)
+
+// isValidFilePackageURL checks that the path of a file:// location is an absolute path of a file,
+// the path is passed to the shell starting the instance, so only the plain path characters are allowed
+func isValidFilePackageURL(packageLocation string) error {
+	path := FilePackagePath(packageLocation)
+	if path == "" {
+		return nil
+	}
+	if !filePackagePathPattern.MatchString(path) {
+		return fmt.Errorf("invalid package file %s, the path must be absolute and contain only "+
+			"letters, digits and the characters ._-+@=/", packageLocation)
+	}
+	if strings.HasSuffix(path, "/") {
+		return fmt.Errorf("invalid package file %s, the path must not be a directory", packageLocation)
+	}
+	for _, segment := range strings.Split(path, "/") {
+		if segment == ".." {
+			return fmt.Errorf("invalid package file %s, the path must not contain '..'", packageLocation)
+		}
+	}
+	return nil
+}
+
 func isFunctionPackageURLSupported(packageLocation string) bool {
-	// TODO: support file:// schema
 	lowerCase := strings.ToLower(packageLocation)
 	return strings.HasPrefix(lowerCase, PackageURLHTTP) ||
 		strings.HasPrefix(lowerCase, PackageURLHTTPS)
@@ -491,6 +771,33 @@ func collectAllInputTopics(inputs InputConf) []string {
 	return ret
 }
 
+// inferMissingWindowConfigs fills the window configs which can be derived from the others,
+// it follows the behavior of `WindowConfigUtils.inferMissingArguments` in Pulsar
+func inferMissingWindowConfigs(windowConfig *WindowConfig) {
+	if windowConfig.SlidingIntervalCount == nil && windowConfig.SlidingIntervalDurationMs == nil {
+		// use a tumbling window by default
+		if windowConfig.WindowLengthDurationMs != nil {
+			windowConfig.SlidingIntervalDurationMs = new(int64)
+			*windowConfig.SlidingIntervalDurationMs = *windowConfig.WindowLengthDurationMs
+		}
+		if windowConfig.WindowLengthCount != nil {
+			windowConfig.SlidingIntervalCount = new(int32)
+			*windowConfig.SlidingIntervalCount = *windowConfig.WindowLengthCount
+		}
+	}
+
+	if windowConfig.TimestampExtractorClassName != nil {
+		if windowConfig.MaxLagMs == nil {
+			windowConfig.MaxLagMs = new(int64)
+			*windowConfig.MaxLagMs = DefaultWindowMaxLagMs
+		}
+		if windowConfig.WatermarkEmitIntervalMs == nil {
+			windowConfig.WatermarkEmitIntervalMs = new(int64)
+			*windowConfig.WatermarkEmitIntervalMs = DefaultWindowWatermarkEmitIntervalMs
+		}
+	}
+}
+
 func isValidTopicName(topicName string) error {
 	_, err := pctlutil.GetTopicName(topicName)
 	return err
@@ -509,6 +816,12 @@ const (
 	maxNameLength = 43
 )
 
+// AnnotationAcknowledgeIdentityChange acknowledges the changes of the tenant, namespace, name or subscriptionName
+// of a component, which start new Pulsar resources and abandon the previous ones. The value lists the acknowledged
+// changes as comma separated field=value pairs such as "tenant=public,name=fn", so that a later change of the field
+// to another value is rejected again
+const AnnotationAcknowledgeIdentityChange = "compute.functionmesh.io/acknowledge-identity-change"
+
 const (
 	// LogLevelOff indicates no logging and is only available for the Java runtime
 	LogLevelOff LogLevel = "off"
@@ -42,10 +42,15 @@ type FunctionSpec struct {
 	// MaxReplicas indicates the maximum number of replicas and enables the HorizontalPodAutoscaler
 	// If provided, a default HPA with CPU at average of 80% will be used.
 	// For complex HPA strategies, please refer to Pod.HPAutoscaler.
-	MaxReplicas *int32     `json:"maxReplicas,omitempty"` // if provided, turn on autoscaling
-	Input       InputConf  `json:"input,omitempty"`
-	Output      OutputConf `json:"output,omitempty"`
-	LogTopic    string     `json:"logTopic,omitempty"`
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"` // if provided, turn on autoscaling
+
+	// Suspend scales the function to zero and removes its HorizontalPodAutoscaler,
+	// the service and the Pulsar subscription are kept so that it can be resumed later
+	Suspend bool `json:"suspend,omitempty"`
+
+	Input    InputConf  `json:"input,omitempty"`
+	Output   OutputConf `json:"output,omitempty"`
+	LogTopic string     `json:"logTopic,omitempty"`
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:pruning:PreserveUnknownFields
 	FuncConfig   *Config                     `json:"funcConfig,omitempty"`
@@ -70,7 +75,20 @@ type FunctionSpec struct {
 
 	Pod PodPolicy `json:"pod,omitempty"`
 
-	// TODO: windowconfig, customRuntimeOptions?
+	// WindowConfig turns the function into a windowed function, the function
+	// will receive windows of messages instead of a single message
+	// +kubebuilder:validation:Optional
+	WindowConfig *WindowConfig `json:"windowConfig,omitempty"`
+
+	// IdlePolicy scales the function to zero when its input subscription stays empty
+	// +kubebuilder:validation:Optional
+	IdlePolicy *IdlePolicy `json:"idlePolicy,omitempty"`
+
+	// Tuning tunes the instances of the function
+	// +kubebuilder:validation:Optional
+	Tuning *RuntimeTuning `json:"tuning,omitempty"`
+
+	// TODO: customRuntimeOptions?
 
 	// +kubebuilder:validation:Required
 	Messaging `json:",inline"`
@@ -19,6 +19,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"reflect"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -109,6 +110,10 @@ func (r *Function) Default() {
 	if r.Spec.Output.TypeClassName == "" {
 		r.Spec.Output.TypeClassName = "[B"
 	}
+
+	if r.Spec.WindowConfig != nil {
+		inferMissingWindowConfigs(r.Spec.WindowConfig)
+	}
 }
 
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
@@ -119,6 +124,11 @@ var _ webhook.Validator = &Function{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *Function) ValidateCreate() error {
 	functionlog.Info("validate create function", "name", r.Name)
+	return r.toInvalidError(r.validateSpec())
+}
+
+// validateSpec runs the validations shared by the creation and the update of the function
+func (r *Function) validateSpec() field.ErrorList {
 	var allErrs field.ErrorList
 	var fieldErr *field.Error
 	var fieldErrs []*field.Error
@@ -131,19 +141,18 @@ func (r *Function) ValidateCreate() error {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("name"), r.Name, "function name is not provided"))
 	}
 
-	if r.Spec.Runtime.Java == nil && r.Spec.Runtime.Python == nil && r.Spec.Runtime.Golang == nil {
+	if countRuntimes(r.Spec.Runtime) == 0 {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("runtime", "java"), r.Spec.Runtime.Java,
 			"runtime cannot be empty"))
 		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("runtime", "python"), r.Spec.Runtime.Python,
 			"runtime cannot be empty"))
 		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("runtime", "golang"), r.Spec.Runtime.Golang,
 			"runtime cannot be empty"))
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("runtime", "custom"), r.Spec.Runtime.Custom,
+			"runtime cannot be empty"))
 	}
 
-	if (r.Spec.Runtime.Java != nil && r.Spec.Runtime.Python != nil) ||
-		(r.Spec.Runtime.Java != nil && r.Spec.Runtime.Golang != nil) ||
-		(r.Spec.Runtime.Python != nil && r.Spec.Runtime.Golang != nil) ||
-		(r.Spec.Runtime.Java != nil && r.Spec.Runtime.Python != nil && r.Spec.Runtime.Golang != nil) {
+	if countRuntimes(r.Spec.Runtime) > 1 {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec").Child("runtime"), r.Spec.Runtime, "you can only specify one runtime"))
 	}
@@ -163,6 +172,16 @@ func (r *Function) ValidateCreate() error {
 		allErrs = append(allErrs, fieldErrs...)
 	}
 
+	fieldErrs = validateCustomRuntime(&r.Spec)
+	if len(fieldErrs) > 0 {
+		allErrs = append(allErrs, fieldErrs...)
+	}
+
+	fieldErrs = validateGolangFunction(&r.Spec)
+	if len(fieldErrs) > 0 {
+		allErrs = append(allErrs, fieldErrs...)
+	}
+
 	fieldErrs = validateReplicasAndMaxReplicas(r.Spec.Replicas, r.Spec.MaxReplicas)
 	if len(fieldErrs) > 0 {
 		allErrs = append(allErrs, fieldErrs...)
@@ -173,6 +192,21 @@ func (r *Function) ValidateCreate() error {
 		allErrs = append(allErrs, fieldErr)
 	}
 
+	fieldErrs = validatePulsarMessaging(r.Spec.Pulsar)
+	if len(fieldErrs) > 0 {
+		allErrs = append(allErrs, fieldErrs...)
+	}
+
+	fieldErrs = validateRuntimeTuning(r.Spec.Tuning, r.Spec.Runtime)
+	if len(fieldErrs) > 0 {
+		allErrs = append(allErrs, fieldErrs...)
+	}
+
+	fieldErr = validateIdlePolicy(r.Spec.IdlePolicy, r.Spec.Input)
+	if fieldErr != nil {
+		allErrs = append(allErrs, fieldErr)
+	}
+
 	fieldErr = validateAutoAck(r.Spec.AutoAck)
 	if fieldErr != nil {
 		allErrs = append(allErrs, fieldErr)
@@ -228,19 +262,76 @@ func (r *Function) ValidateCreate() error {
 		allErrs = append(allErrs, fieldErr)
 	}
 
+	fieldErrs = validateWindowConfigs(r.Spec.WindowConfig, r.Spec.Runtime)
+	if len(fieldErrs) > 0 {
+		allErrs = append(allErrs, fieldErrs...)
+	}
+
+	return allErrs
+}
+
+func (r *Function) toInvalidError(allErrs field.ErrorList) error {
 	if len(allErrs) == 0 {
 		return nil
 	}
 
+	recordRejections("Function", allErrs)
 	return apierrors.NewInvalid(schema.GroupKind{Group: "compute.functionmesh.io", Kind: "Function"}, r.Name, allErrs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *Function) ValidateUpdate(old runtime.Object) error {
 	functionlog.Info("validate update", "name", r.Name)
+	oldFunction, ok := old.(*Function)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a Function but got a %T", old))
+	}
 
-	// TODO(user): fill in your validation logic upon object update.
-	return nil
+	// the finalizers must be removable even if the validation rules changed since the creation
+	if !r.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	if !reflect.DeepEqual(r.Spec, oldFunction.Spec) {
+		allErrs = append(allErrs, r.validateSpec()...)
+	}
+
+	allErrs = append(allErrs, r.validateIdentityChanges(oldFunction)...)
+	return r.toInvalidError(allErrs)
+}
+
+// validateIdentityChanges rejects the changes of the fields identifying the Pulsar resources of the function,
+// unless the new values are acknowledged by the annotation
+func (r *Function) validateIdentityChanges(oldFunction *Function) field.ErrorList {
+	var allErrs field.ErrorList
+	var fieldErr *field.Error
+
+	fieldErr = validateIdentityChange("tenant", r.Spec.Tenant, oldFunction.Spec.Tenant, r.Annotations)
+	if fieldErr != nil {
+		allErrs = append(allErrs, fieldErr)
+	}
+
+	fieldErr = validateIdentityChange("namespace", r.Spec.Namespace, oldFunction.Spec.Namespace, r.Annotations)
+	if fieldErr != nil {
+		allErrs = append(allErrs, fieldErr)
+	}
+
+	fieldErr = validateIdentityChange("name", r.Spec.Name, oldFunction.Spec.Name, r.Annotations)
+	if fieldErr != nil {
+		allErrs = append(allErrs, fieldErr)
+	}
+
+	// the subscription name defaults to the fully qualified name, only the changes of the effective name matter
+	fieldErr = validateIdentityChange("subscriptionName",
+		makeSubscriptionName(r.Spec.SubscriptionName, r.Spec.Tenant, r.Spec.Namespace, r.Spec.Name),
+		makeSubscriptionName(oldFunction.Spec.SubscriptionName, oldFunction.Spec.Tenant, oldFunction.Spec.Namespace,
+			oldFunction.Spec.Name), r.Annotations)
+	if fieldErr != nil {
+		allErrs = append(allErrs, fieldErr)
+	}
+
+	return allErrs
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -0,0 +1,261 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// componentFields are the fields of the functions, sources and sinks covered by the mesh defaults
+type componentFields struct {
+	tenant          *string
+	namespace       *string
+	clusterName     *string
+	messaging       *Messaging
+	image           *string
+	imagePullPolicy *corev1.PullPolicy
+	resources       *corev1.ResourceRequirements
+	pod             *PodPolicy
+}
+
+func functionFields(spec *FunctionSpec) componentFields {
+	return componentFields{&spec.Tenant, &spec.Namespace, &spec.ClusterName, &spec.Messaging,
+		&spec.Image, &spec.ImagePullPolicy, &spec.Resources, &spec.Pod}
+}
+
+func sourceFields(spec *SourceSpec) componentFields {
+	return componentFields{&spec.Tenant, &spec.Namespace, &spec.ClusterName, &spec.Messaging,
+		&spec.Image, &spec.ImagePullPolicy, &spec.Resources, &spec.Pod}
+}
+
+func sinkFields(spec *SinkSpec) componentFields {
+	return componentFields{&spec.Tenant, &spec.Namespace, &spec.ClusterName, &spec.Messaging,
+		&spec.Image, &spec.ImagePullPolicy, &spec.Resources, &spec.Pod}
+}
+
+// ApplyToFunction merges the defaults into the spec of the function
+func (d *MeshComponentDefaults) ApplyToFunction(spec *FunctionSpec) {
+	d.apply(functionFields(spec))
+}
+
+// ApplyToSource merges the defaults into the spec of the source
+func (d *MeshComponentDefaults) ApplyToSource(spec *SourceSpec) {
+	d.apply(sourceFields(spec))
+}
+
+// ApplyToSink merges the defaults into the spec of the sink
+func (d *MeshComponentDefaults) ApplyToSink(spec *SinkSpec) {
+	d.apply(sinkFields(spec))
+}
+
+func (d *MeshComponentDefaults) apply(fields componentFields) {
+	if d == nil {
+		return
+	}
+	mergeString(fields.tenant, d.Tenant)
+	mergeString(fields.namespace, d.Namespace)
+	mergeString(fields.clusterName, d.ClusterName)
+	mergeString(fields.image, d.Image)
+	if *fields.imagePullPolicy == "" {
+		*fields.imagePullPolicy = d.ImagePullPolicy
+	}
+	mergeMessaging(fields.messaging, &d.Messaging)
+	if d.Resources != nil {
+		fields.resources.Requests = mergeResourceList(fields.resources.Requests, d.Resources.Requests)
+		fields.resources.Limits = mergeResourceList(fields.resources.Limits, d.Resources.Limits)
+	}
+	if d.Pod != nil {
+		mergePodPolicy(fields.pod, d.Pod)
+	}
+}
+
+// restore resets the fields covered by the defaults to their values in the original spec,
+// so that the values inherited from the defaults are not stored in the components of the mesh.
+// The components are defaulted again by their own webhooks once the controller has merged the defaults,
+// which derives values from the merged fields, e.g. the resource limits padded from the requests. The controller
+// therefore applies the same defaulting to the components it generates before comparing them with the stored ones.
+func (d *MeshComponentDefaults) restore(fields, original componentFields) {
+	if d == nil {
+		return
+	}
+	if d.Tenant != "" {
+		*fields.tenant = *original.tenant
+	}
+	if d.Namespace != "" {
+		*fields.namespace = *original.namespace
+	}
+	if d.ClusterName != "" {
+		*fields.clusterName = *original.clusterName
+	}
+	if d.Image != "" {
+		*fields.image = *original.image
+	}
+	if d.ImagePullPolicy != "" {
+		*fields.imagePullPolicy = *original.imagePullPolicy
+	}
+	if d.Pulsar != nil {
+		*fields.messaging = *original.messaging
+	}
+	if d.Resources != nil {
+		*fields.resources = *original.resources
+	}
+	if d.Pod != nil {
+		*fields.pod = *original.pod
+	}
+}
+
+func mergeString(value *string, defaultValue string) {
+	if *value == "" {
+		*value = defaultValue
+	}
+}
+
+func mergeMessaging(messaging *Messaging, defaults *Messaging) {
+	if defaults.Pulsar == nil {
+		return
+	}
+	if messaging.Pulsar == nil {
+		messaging.Pulsar = defaults.Pulsar.DeepCopy()
+		return
+	}
+	// a connection is not mixed with the settings it replaces
+	if messaging.Pulsar.ConnectionRef != nil || defaults.Pulsar.ConnectionRef != nil {
+		return
+	}
+	pulsar := messaging.Pulsar.DeepCopy()
+	mergeString(&pulsar.PulsarConfig, defaults.Pulsar.PulsarConfig)
+	mergeString(&pulsar.AuthSecret, defaults.Pulsar.AuthSecret)
+	mergeString(&pulsar.TLSSecret, defaults.Pulsar.TLSSecret)
+	if pulsar.TLSConfig == nil && defaults.Pulsar.TLSConfig != nil {
+		pulsar.TLSConfig = defaults.Pulsar.TLSConfig.DeepCopy()
+	}
+	messaging.Pulsar = pulsar
+}
+
+func mergeResourceList(resources, defaults corev1.ResourceList) corev1.ResourceList {
+	if len(defaults) == 0 {
+		return resources
+	}
+	merged := make(corev1.ResourceList, len(resources)+len(defaults))
+	for name, quantity := range defaults {
+		merged[name] = quantity.DeepCopy()
+	}
+	for name, quantity := range resources {
+		merged[name] = quantity
+	}
+	return merged
+}
+
+func mergePodPolicy(pod *PodPolicy, defaults *PodPolicy) {
+	defaults = defaults.DeepCopy()
+	pod.Labels = mergeStringMap(pod.Labels, defaults.Labels)
+	pod.NodeSelector = mergeStringMap(pod.NodeSelector, defaults.NodeSelector)
+	pod.Annotations = mergeStringMap(pod.Annotations, defaults.Annotations)
+	if pod.Affinity == nil {
+		pod.Affinity = defaults.Affinity
+	}
+	pod.Tolerations = mergeTolerations(pod.Tolerations, defaults.Tolerations)
+	if pod.SecurityContext == nil {
+		pod.SecurityContext = defaults.SecurityContext
+	}
+	if pod.TerminationGracePeriodSeconds == 0 {
+		pod.TerminationGracePeriodSeconds = defaults.TerminationGracePeriodSeconds
+	}
+	pod.Volumes = mergeByName(pod.Volumes, defaults.Volumes).([]corev1.Volume)
+	pod.ImagePullSecrets = mergeByName(pod.ImagePullSecrets, defaults.ImagePullSecrets).([]corev1.LocalObjectReference)
+	pod.InitContainers = mergeByName(pod.InitContainers, defaults.InitContainers).([]corev1.Container)
+	pod.Sidecars = mergeByName(pod.Sidecars, defaults.Sidecars).([]corev1.Container)
+	mergeString(&pod.ServiceAccountName, defaults.ServiceAccountName)
+	if len(pod.BuiltinAutoscaler) == 0 {
+		pod.BuiltinAutoscaler = defaults.BuiltinAutoscaler
+	}
+	if len(pod.AutoScalingMetrics) == 0 {
+		pod.AutoScalingMetrics = defaults.AutoScalingMetrics
+	}
+	if pod.AutoScalingBehavior == nil {
+		pod.AutoScalingBehavior = defaults.AutoScalingBehavior
+	}
+	pod.Env = mergeByName(pod.Env, defaults.Env).([]corev1.EnvVar)
+	if pod.Monitor == nil {
+		pod.Monitor = defaults.Monitor
+	}
+}
+
+// mergeStringMap returns the entries of both maps, the values of the map take precedence over the defaults
+func mergeStringMap(values, defaults map[string]string) map[string]string {
+	if len(defaults) == 0 {
+		return values
+	}
+	merged := make(map[string]string, len(values)+len(defaults))
+	for key, value := range defaults {
+		merged[key] = value
+	}
+	for key, value := range values {
+		merged[key] = value
+	}
+	return merged
+}
+
+// mergeTolerations returns the default tolerations followed by the tolerations which are not in the defaults
+func mergeTolerations(tolerations, defaults []corev1.Toleration) []corev1.Toleration {
+	if len(defaults) == 0 {
+		return tolerations
+	}
+	merged := append([]corev1.Toleration{}, defaults...)
+	for _, toleration := range tolerations {
+		found := false
+		for _, defaultToleration := range defaults {
+			if equality.Semantic.DeepEqual(toleration, defaultToleration) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, toleration)
+		}
+	}
+	return merged
+}
+
+// mergeByName returns the default items followed by the items which are not in the defaults, an item replaces
+// the default item with the same name. Both arguments are slices of the same struct type with a Name field.
+func mergeByName(items, defaults interface{}) interface{} {
+	defaultValues := reflect.ValueOf(defaults)
+	if defaultValues.Len() == 0 {
+		return items
+	}
+	values := reflect.ValueOf(items)
+	merged := reflect.MakeSlice(defaultValues.Type(), defaultValues.Len(), defaultValues.Len()+values.Len())
+	reflect.Copy(merged, defaultValues)
+	indexes := make(map[string]int, merged.Len())
+	for i := 0; i < merged.Len(); i++ {
+		indexes[merged.Index(i).FieldByName("Name").String()] = i
+	}
+	for i := 0; i < values.Len(); i++ {
+		item := values.Index(i)
+		if j, ok := indexes[item.FieldByName("Name").String()]; ok {
+			merged.Index(j).Set(item)
+		} else {
+			merged = reflect.Append(merged, item)
+		}
+	}
+	return merged.Interface()
+}
@@ -0,0 +1,59 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestMergeByName(t *testing.T) {
+	defaults := []corev1.EnvVar{{Name: "A", Value: "default"}, {Name: "B", Value: "default"}}
+	env := []corev1.EnvVar{{Name: "C", Value: "own"}, {Name: "A", Value: "own"}}
+
+	assert.DeepEqual(t, mergeByName(env, defaults), []corev1.EnvVar{
+		{Name: "A", Value: "own"}, {Name: "B", Value: "default"}, {Name: "C", Value: "own"}})
+	assert.DeepEqual(t, mergeByName([]corev1.EnvVar(nil), defaults), defaults)
+	assert.DeepEqual(t, mergeByName(env, []corev1.EnvVar(nil)), env)
+	// the defaults are not modified
+	assert.DeepEqual(t, defaults, []corev1.EnvVar{{Name: "A", Value: "default"}, {Name: "B", Value: "default"}})
+}
+
+func TestMergePodPolicy(t *testing.T) {
+	pod := &PodPolicy{
+		Labels:           map[string]string{"app": "own"},
+		Volumes:          []corev1.Volume{{Name: "data"}},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+		Sidecars:         []corev1.Container{{Name: "proxy", Image: "proxy:2"}},
+	}
+	mergePodPolicy(pod, &PodPolicy{
+		Labels:           map[string]string{"app": "default", "team": "default"},
+		Volumes:          []corev1.Volume{{Name: "config"}, {Name: "data"}},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+		Sidecars:         []corev1.Container{{Name: "proxy", Image: "proxy:1"}},
+		InitContainers:   []corev1.Container{{Name: "init"}},
+	})
+
+	assert.DeepEqual(t, pod.Labels, map[string]string{"app": "own", "team": "default"})
+	assert.DeepEqual(t, pod.Volumes, []corev1.Volume{{Name: "config"}, {Name: "data"}})
+	assert.DeepEqual(t, pod.ImagePullSecrets, []corev1.LocalObjectReference{{Name: "registry"}})
+	assert.DeepEqual(t, pod.Sidecars, []corev1.Container{{Name: "proxy", Image: "proxy:2"}})
+	assert.DeepEqual(t, pod.InitContainers, []corev1.Container{{Name: "init"}})
+}
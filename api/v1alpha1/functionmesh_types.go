@@ -18,6 +18,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -32,6 +33,55 @@ type FunctionMeshSpec struct {
 	Sources   []SourceSpec   `json:"sources,omitempty"`
 	Sinks     []SinkSpec     `json:"sinks,omitempty"`
 	Functions []FunctionSpec `json:"functions,omitempty"`
+
+	// Suspend suspends all the functions, sources and sinks of the mesh,
+	// the components keep their own suspend setting when the mesh is not suspended
+	Suspend bool `json:"suspend,omitempty"`
+
+	// RolloutStrategy controls the order in which the components are created, updated and deleted,
+	// the components are rolled out in parallel by default
+	RolloutStrategy *MeshRolloutStrategy `json:"rolloutStrategy,omitempty"`
+
+	// Defaults are the settings shared by the functions, sources and sinks of the mesh,
+	// they are merged into the components which leave them unset
+	Defaults *MeshComponentDefaults `json:"defaults,omitempty"`
+}
+
+// MeshComponentDefaults are the settings shared by the components of a mesh. The scalar settings are used
+// when the component leaves them unset. The maps are merged by key and the lists of named items, such as env,
+// volumes and containers, are merged by name, the values of the component take precedence. The tolerations
+// are added to the ones of the component.
+type MeshComponentDefaults struct {
+	Tenant      string `json:"tenant,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	ClusterName string `json:"clusterName,omitempty"`
+
+	Messaging `json:",inline"`
+
+	Image           string            `json:"image,omitempty"`
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	Pod       *PodPolicy                   `json:"pod,omitempty"`
+}
+
+// MeshRolloutStrategyType is the way the components of a mesh are rolled out
+// +kubebuilder:validation:Enum=Parallel;Ordered
+type MeshRolloutStrategyType string
+
+const (
+	// ParallelMeshRollout creates, updates and deletes all the components at once
+	ParallelMeshRollout MeshRolloutStrategyType = "Parallel"
+	// OrderedMeshRollout creates and updates the consumers of a topic before its producers,
+	// waiting for each step to be ready, and deletes the producers before the consumers.
+	// The components removed from the mesh are no longer in its topic graph, they are deleted
+	// by kind instead: the sources, then the functions, then the sinks.
+	OrderedMeshRollout MeshRolloutStrategyType = "Ordered"
+)
+
+type MeshRolloutStrategy struct {
+	// +kubebuilder:default=Parallel
+	Type MeshRolloutStrategyType `json:"type,omitempty"`
 }
 
 // FunctionMeshStatus defines the observed state of FunctionMesh
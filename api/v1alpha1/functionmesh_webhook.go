@@ -0,0 +1,308 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha1
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var functionmeshlog = logf.Log.WithName("functionmesh-resource")
+
+func (r *FunctionMesh) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-compute-functionmesh-io-v1alpha1-functionmesh,mutating=true,failurePolicy=fail,groups=compute.functionmesh.io,resources=functionmeshes,verbs=create;update,versions=v1alpha1,name=mfunctionmesh.kb.io,sideEffects=none,admissionReviewVersions={v1beta1,v1}
+
+var _ webhook.Defaulter = &FunctionMesh{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type,
+// the components are defaulted the same way as the standalone functions, sources and sinks,
+// except for the names which identify the components in the mesh, and the fields covered by the
+// defaults of the mesh which are left unset to follow the changes of the defaults
+func (r *FunctionMesh) Default() {
+	functionmeshlog.Info("default", "name", r.Name)
+
+	for i := range r.Spec.Functions {
+		original := r.Spec.Functions[i].DeepCopy()
+		function := r.makeFunction(*original)
+		function.Default()
+		function.Spec.Name = original.Name
+		r.Spec.Defaults.restore(functionFields(&function.Spec), functionFields(original))
+		r.Spec.Functions[i] = function.Spec
+	}
+
+	for i := range r.Spec.Sources {
+		original := r.Spec.Sources[i].DeepCopy()
+		source := r.makeSource(*original)
+		source.Default()
+		source.Spec.Name = original.Name
+		r.Spec.Defaults.restore(sourceFields(&source.Spec), sourceFields(original))
+		r.Spec.Sources[i] = source.Spec
+	}
+
+	for i := range r.Spec.Sinks {
+		original := r.Spec.Sinks[i].DeepCopy()
+		sink := r.makeSink(*original)
+		sink.Default()
+		sink.Spec.Name = original.Name
+		r.Spec.Defaults.restore(sinkFields(&sink.Spec), sinkFields(original))
+		r.Spec.Sinks[i] = sink.Spec
+	}
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-compute-functionmesh-io-v1alpha1-functionmesh,mutating=false,failurePolicy=fail,groups=compute.functionmesh.io,resources=functionmeshes,versions=v1alpha1,name=vfunctionmesh.kb.io,sideEffects=none,admissionReviewVersions={v1beta1,v1}
+
+var _ webhook.Validator = &FunctionMesh{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *FunctionMesh) ValidateCreate() error {
+	functionmeshlog.Info("validate create function mesh", "name", r.Name)
+	return r.toInvalidError(r.validateSpec())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *FunctionMesh) ValidateUpdate(old runtime.Object) error {
+	functionmeshlog.Info("validate update", "name", r.Name)
+	oldMesh, ok := old.(*FunctionMesh)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a FunctionMesh but got a %T", old))
+	}
+
+	if !r.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	if !reflect.DeepEqual(r.Spec, oldMesh.Spec) {
+		allErrs = append(allErrs, r.validateSpec()...)
+	}
+
+	// the components are matched by name, the identity changes are rejected here
+	// instead of failing the update of the components after a part of the mesh is applied
+	path := field.NewPath("spec")
+	for i, spec := range r.Spec.Functions {
+		for _, oldSpec := range oldMesh.Spec.Functions {
+			if spec.Name == oldSpec.Name {
+				errs := r.makeFunction(spec).validateIdentityChanges(oldMesh.makeFunction(oldSpec))
+				allErrs = append(allErrs, rebaseFieldErrors(path.Child("functions").Index(i), errs)...)
+			}
+		}
+	}
+	for i, spec := range r.Spec.Sources {
+		for _, oldSpec := range oldMesh.Spec.Sources {
+			if spec.Name == oldSpec.Name {
+				errs := r.makeSource(spec).validateIdentityChanges(oldMesh.makeSource(oldSpec))
+				allErrs = append(allErrs, rebaseFieldErrors(path.Child("sources").Index(i), errs)...)
+			}
+		}
+	}
+	for i, spec := range r.Spec.Sinks {
+		for _, oldSpec := range oldMesh.Spec.Sinks {
+			if spec.Name == oldSpec.Name {
+				errs := r.makeSink(spec).validateIdentityChanges(oldMesh.makeSink(oldSpec))
+				allErrs = append(allErrs, rebaseFieldErrors(path.Child("sinks").Index(i), errs)...)
+			}
+		}
+	}
+
+	return r.toInvalidError(allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *FunctionMesh) ValidateDelete() error {
+	functionmeshlog.Info("validate delete", "name", r.Name)
+	return nil
+}
+
+// validateSpec validates every component with the validations of the standalone resources,
+// and checks that the names of the components are unique and fit in the name of the generated resources
+func (r *FunctionMesh) validateSpec() field.ErrorList {
+	allErrs := ValidateComponentNames(&r.Spec)
+	path := field.NewPath("spec")
+	for i, spec := range r.Spec.Functions {
+		allErrs = append(allErrs, rebaseFieldErrors(path.Child("functions").Index(i), r.makeFunction(spec).validateSpec())...)
+	}
+	for i, spec := range r.Spec.Sources {
+		allErrs = append(allErrs, rebaseFieldErrors(path.Child("sources").Index(i), r.makeSource(spec).validateSpec())...)
+	}
+	for i, spec := range r.Spec.Sinks {
+		allErrs = append(allErrs, rebaseFieldErrors(path.Child("sinks").Index(i), r.makeSink(spec).validateSpec())...)
+	}
+
+	allErrs = append(allErrs, r.validateTopicGraph()...)
+	return allErrs
+}
+
+// ValidateComponentNames checks that the names of the components are provided and unique for each kind,
+// it is also called by the mesh controller since the components would overwrite each other
+// when the mesh is created while the webhook is disabled
+func ValidateComponentNames(spec *FunctionMeshSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	path := field.NewPath("spec")
+
+	names := map[string]bool{}
+	for i, spec := range spec.Functions {
+		allErrs = append(allErrs, validateComponentName(path.Child("functions").Index(i), spec.Name, names)...)
+	}
+
+	names = map[string]bool{}
+	for i, spec := range spec.Sources {
+		allErrs = append(allErrs, validateComponentName(path.Child("sources").Index(i), spec.Name, names)...)
+	}
+
+	names = map[string]bool{}
+	for i, spec := range spec.Sinks {
+		allErrs = append(allErrs, validateComponentName(path.Child("sinks").Index(i), spec.Name, names)...)
+	}
+	return allErrs
+}
+
+func (r *FunctionMesh) validateTopicGraph() field.ErrorList {
+	var allErrs field.ErrorList
+	graph := NewTopicGraph(&r.Spec)
+
+	for _, cycle := range graph.Cycles() {
+		allErrs = append(allErrs, field.Invalid(r.componentPath(cycle[0]).Child("output", "topic"),
+			strings.Join(cycle, " -> "), "components are connected in a cycle through their topics"))
+	}
+
+	for _, mismatch := range graph.SchemaMismatches() {
+		allErrs = append(allErrs, field.Invalid(r.componentPath(mismatch.Consumer).Child("input", "sourceSpecs"),
+			mismatch.ConsumerSchemaType,
+			fmt.Sprintf("schema type of topic %s does not match the schema type %s of its producer %s",
+				mismatch.Topic, mismatch.ProducerSchemaType, mismatch.Producer)))
+	}
+	return allErrs
+}
+
+// componentPath returns the path of the component of the mesh identified by the ID of its topic graph node
+func (r *FunctionMesh) componentPath(id string) *field.Path {
+	path := field.NewPath("spec")
+	kind, name := id, ""
+	if i := strings.Index(id, "/"); i >= 0 {
+		kind, name = id[:i], id[i+1:]
+	}
+	switch kind {
+	case FunctionComponent:
+		for i := range r.Spec.Functions {
+			if r.Spec.Functions[i].Name == name {
+				return path.Child("functions").Index(i)
+			}
+		}
+	case SourceComponent:
+		for i := range r.Spec.Sources {
+			if r.Spec.Sources[i].Name == name {
+				return path.Child("sources").Index(i)
+			}
+		}
+	case SinkComponent:
+		for i := range r.Spec.Sinks {
+			if r.Spec.Sinks[i].Name == name {
+				return path.Child("sinks").Index(i)
+			}
+		}
+	}
+	return path
+}
+
+func (r *FunctionMesh) toInvalidError(allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	recordRejections("FunctionMesh", allErrs)
+	return apierrors.NewInvalid(schema.GroupKind{Group: "compute.functionmesh.io", Kind: "FunctionMesh"}, r.Name, allErrs)
+}
+
+// makeComponentObjectMeta returns the metadata of a component generated by the mesh controller,
+// the annotations of the mesh are kept to check the acknowledgement of the identity changes
+func (r *FunctionMesh) makeComponentObjectMeta(name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        makeComponentName(r.Name, name),
+		Namespace:   r.Namespace,
+		Annotations: r.Annotations,
+	}
+}
+
+func (r *FunctionMesh) makeFunction(spec FunctionSpec) *Function {
+	function := &Function{ObjectMeta: r.makeComponentObjectMeta(spec.Name), Spec: *spec.DeepCopy()}
+	r.Spec.Defaults.ApplyToFunction(&function.Spec)
+	return function
+}
+
+func (r *FunctionMesh) makeSource(spec SourceSpec) *Source {
+	source := &Source{ObjectMeta: r.makeComponentObjectMeta(spec.Name), Spec: *spec.DeepCopy()}
+	r.Spec.Defaults.ApplyToSource(&source.Spec)
+	return source
+}
+
+func (r *FunctionMesh) makeSink(spec SinkSpec) *Sink {
+	sink := &Sink{ObjectMeta: r.makeComponentObjectMeta(spec.Name), Spec: *spec.DeepCopy()}
+	r.Spec.Defaults.ApplyToSink(&sink.Spec)
+	return sink
+}
+
+// makeComponentName returns the name of the resource generated for a component of the mesh,
+// it must be kept in sync with the mesh controller
+func makeComponentName(meshName, name string) string {
+	return meshName + "-" + name
+}
+
+func validateComponentName(path *field.Path, name string, names map[string]bool) field.ErrorList {
+	var allErrs field.ErrorList
+	if name == "" {
+		allErrs = append(allErrs, field.Required(path.Child("name"), "component name is not provided"))
+	} else if names[name] {
+		allErrs = append(allErrs, field.Duplicate(path.Child("name"), name))
+	}
+	names[name] = true
+	return allErrs
+}
+
+// rebaseFieldErrors moves the field errors of a generated component under the path of the component in the mesh,
+// the errors of the name refer to the generated name of the component
+func rebaseFieldErrors(path *field.Path, errs field.ErrorList) field.ErrorList {
+	rebased := make(field.ErrorList, 0, len(errs))
+	for _, err := range errs {
+		e := *err
+		switch {
+		case e.Field == "name":
+			e.Field = path.Child("name").String()
+			e.Detail = fmt.Sprintf("generated name %v is invalid: %s", e.BadValue, e.Detail)
+		case strings.HasPrefix(e.Field, "spec."):
+			e.Field = path.String() + strings.TrimPrefix(e.Field, "spec")
+		}
+		rebased = append(rebased, &e)
+	}
+	return rebased
+}
@@ -0,0 +1,153 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func makeTestFunctionMesh(name string, functions ...FunctionSpec) *FunctionMesh {
+	return &FunctionMesh{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       FunctionMeshSpec{Functions: functions},
+	}
+}
+
+func TestFunctionMeshDefault(t *testing.T) {
+	spec := makeTestFunctionSpec()
+	spec.Tenant = ""
+	mesh := makeTestFunctionMesh("mesh", spec)
+	mesh.Default()
+
+	function := mesh.Spec.Functions[0]
+	assert.Equal(t, function.Name, "fn")
+	assert.Equal(t, function.Tenant, DefaultTenant)
+	assert.Equal(t, function.ClusterName, DefaultCluster)
+	assert.Equal(t, function.ProcessingGuarantee, AtleastOnce)
+	assert.Assert(t, function.AutoAck != nil && *function.AutoAck)
+}
+
+func TestFunctionMeshDefaultWithMeshDefaults(t *testing.T) {
+	spec := makeTestFunctionSpec()
+	spec.Tenant = ""
+	spec.Pulsar = nil
+	mesh := makeTestFunctionMesh("mesh", spec)
+	mesh.Spec.Defaults = &MeshComponentDefaults{
+		Tenant:    "team-a",
+		Messaging: Messaging{Pulsar: &PulsarMessaging{PulsarConfig: "mesh-pulsar"}},
+	}
+	mesh.Default()
+
+	// the fields covered by the mesh defaults are left unset to follow the changes of the defaults
+	function := mesh.Spec.Functions[0]
+	assert.Equal(t, function.Name, "fn")
+	assert.Equal(t, function.Tenant, "")
+	assert.Assert(t, function.Pulsar == nil)
+	assert.Equal(t, function.Namespace, "default")
+	assert.Equal(t, function.ClusterName, DefaultCluster)
+	assert.Assert(t, function.AutoAck != nil && *function.AutoAck)
+
+	generated := mesh.makeFunction(function)
+	assert.Equal(t, generated.Name, "mesh-fn")
+	assert.Equal(t, generated.Spec.Tenant, "team-a")
+	assert.Equal(t, generated.Spec.Pulsar.PulsarConfig, "mesh-pulsar")
+	assert.NilError(t, mesh.ValidateCreate())
+}
+
+func TestFunctionMeshValidateCreate(t *testing.T) {
+	invalidJar := makeTestFunctionSpec()
+	invalidJar.Java.JarLocation = "public/default/fn"
+	other := makeTestFunctionSpec()
+	other.Name = "other"
+	other.Input.Topics = []string{"persistent://public/default/other-input"}
+	other.Output.Topic = "persistent://public/default/other-output"
+
+	testCases := []struct {
+		name        string
+		mesh        *FunctionMesh
+		expectPaths []string
+	}{
+		{
+			name: "valid",
+			mesh: makeTestFunctionMesh("mesh", makeTestFunctionSpec(), other),
+		},
+		{
+			name:        "duplicate names",
+			mesh:        makeTestFunctionMesh("mesh", makeTestFunctionSpec(), makeTestFunctionSpec()),
+			expectPaths: []string{"spec.functions[1].name"},
+		},
+		{
+			name:        "over-long generated name",
+			mesh:        makeTestFunctionMesh(strings.Repeat("m", maxNameLength), makeTestFunctionSpec()),
+			expectPaths: []string{"spec.functions[0].name"},
+		},
+		{
+			name:        "invalid component",
+			mesh:        makeTestFunctionMesh("mesh", other, invalidJar),
+			expectPaths: []string{"spec.functions[1].java.jarLocation"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mesh.Default()
+			err := tc.mesh.ValidateCreate()
+			if len(tc.expectPaths) == 0 {
+				assert.NilError(t, err)
+				return
+			}
+			assert.Assert(t, apierrors.IsInvalid(err), err)
+			assert.DeepEqual(t, fieldErrorPaths(tc.mesh.validateSpec()), tc.expectPaths)
+		})
+	}
+}
+
+func TestFunctionMeshValidateUpdate(t *testing.T) {
+	oldMesh := makeTestFunctionMesh("mesh", makeTestFunctionSpec())
+	oldMesh.Default()
+
+	mesh := oldMesh.DeepCopy()
+	mesh.Spec.Functions[0].Tenant = "team-a"
+	err := mesh.ValidateUpdate(oldMesh)
+	assert.Assert(t, apierrors.IsInvalid(err), err)
+	assert.ErrorContains(t, err, "spec.functions[0].tenant")
+
+	mesh.Annotations = map[string]string{
+		AnnotationAcknowledgeIdentityChange: "tenant=team-a,subscriptionName=team-a/default/fn"}
+	assert.NilError(t, mesh.ValidateUpdate(oldMesh))
+}
+
+func TestRebaseFieldErrors(t *testing.T) {
+	path := field.NewPath("spec").Child("sinks").Index(2)
+	errs := rebaseFieldErrors(path, field.ErrorList{
+		field.Invalid(field.NewPath("name"), "mesh-sink", "sink name must be no more than 43 characters"),
+		field.Invalid(field.NewPath("spec").Child("input", "topics"), nil, "invalid topics"),
+		field.Forbidden(field.NewPath("metadata").Child("annotations"), "forbidden"),
+	})
+
+	assert.DeepEqual(t, fieldErrorPaths(errs),
+		[]string{"spec.sinks[2].name", "spec.sinks[2].input.topics", "metadata.annotations"})
+	assert.Equal(t, errs[0].Detail, "generated name mesh-sink is invalid: sink name must be no more than 43 characters")
+	assert.Equal(t, errs[1].Detail, "invalid topics")
+}
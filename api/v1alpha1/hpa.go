@@ -30,4 +30,19 @@ const (
 	AverageUtilizationMemoryPercent80 BuiltinHPARule = "AverageUtilizationMemoryPercent80"
 	AverageUtilizationMemoryPercent50 BuiltinHPARule = "AverageUtilizationMemoryPercent50"
 	AverageUtilizationMemoryPercent20 BuiltinHPARule = "AverageUtilizationMemoryPercent20"
+
+	// the backlog rules require the external metrics API served by the controller manager,
+	// they are only available for the components that consume from Pulsar topics
+	AverageBacklogPerReplica100   BuiltinHPARule = "AverageBacklogPerReplica100"
+	AverageBacklogPerReplica1000  BuiltinHPARule = "AverageBacklogPerReplica1000"
+	AverageBacklogPerReplica10000 BuiltinHPARule = "AverageBacklogPerReplica10000"
 )
+
+func (r BuiltinHPARule) IsBacklogRule() bool {
+	switch r {
+	case AverageBacklogPerReplica100, AverageBacklogPerReplica1000, AverageBacklogPerReplica10000:
+		return true
+	default:
+		return false
+	}
+}
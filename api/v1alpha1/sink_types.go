@@ -43,8 +43,13 @@ type SinkSpec struct {
 	// MaxReplicas indicates the maximum number of replicas and enables the HorizontalPodAutoscaler
 	// If provided, a default HPA with CPU at average of 80% will be used.
 	// For complex HPA strategies, please refer to Pod.HPAutoscaler.
-	MaxReplicas *int32    `json:"maxReplicas,omitempty"` // if provided, turn on autoscaling
-	Input       InputConf `json:"input,omitempty"`
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"` // if provided, turn on autoscaling
+
+	// Suspend scales the sink to zero and removes its HorizontalPodAutoscaler,
+	// the service and the Pulsar subscription are kept so that it can be resumed later
+	Suspend bool `json:"suspend,omitempty"`
+
+	Input InputConf `json:"input,omitempty"`
 
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:pruning:PreserveUnknownFields
@@ -68,6 +73,14 @@ type SinkSpec struct {
 
 	Pod PodPolicy `json:"pod,omitempty"`
 
+	// IdlePolicy scales the sink to zero when its input subscription stays empty
+	// +kubebuilder:validation:Optional
+	IdlePolicy *IdlePolicy `json:"idlePolicy,omitempty"`
+
+	// Tuning tunes the instances of the sink
+	// +kubebuilder:validation:Optional
+	Tuning *RuntimeTuning `json:"tuning,omitempty"`
+
 	// +kubebuilder:validation:Required
 	Messaging `json:",inline"`
 	// +kubebuilder:validation:Required
@@ -19,6 +19,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"reflect"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -106,6 +107,11 @@ var _ webhook.Validator = &Sink{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *Sink) ValidateCreate() error {
 	sinklog.Info("validate create sink", "name", r.Name)
+	return r.toInvalidError(r.validateSpec())
+}
+
+// validateSpec runs the validations shared by the creation and the update of the sink
+func (r *Sink) validateSpec() field.ErrorList {
 	var allErrs field.ErrorList
 	var fieldErr *field.Error
 	var fieldErrs []*field.Error
@@ -124,6 +130,11 @@ func (r *Sink) ValidateCreate() error {
 			"sink must have java runtime specified"))
 	}
 
+	if r.Spec.Runtime.Custom != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("runtime", "custom"),
+			"custom runtime is only available for functions"))
+	}
+
 	fieldErrs = validateJavaRuntime(r.Spec.Java, r.Spec.ClassName)
 	if len(fieldErrs) > 0 {
 		allErrs = append(allErrs, fieldErrs...)
@@ -139,6 +150,21 @@ func (r *Sink) ValidateCreate() error {
 		allErrs = append(allErrs, fieldErr)
 	}
 
+	fieldErrs = validatePulsarMessaging(r.Spec.Pulsar)
+	if len(fieldErrs) > 0 {
+		allErrs = append(allErrs, fieldErrs...)
+	}
+
+	fieldErrs = validateRuntimeTuning(r.Spec.Tuning, r.Spec.Runtime)
+	if len(fieldErrs) > 0 {
+		allErrs = append(allErrs, fieldErrs...)
+	}
+
+	fieldErr = validateIdlePolicy(r.Spec.IdlePolicy, r.Spec.Input)
+	if fieldErr != nil {
+		allErrs = append(allErrs, fieldErr)
+	}
+
 	fieldErr = validateAutoAck(r.Spec.AutoAck)
 	if fieldErr != nil {
 		allErrs = append(allErrs, fieldErr)
@@ -174,19 +200,71 @@ func (r *Sink) ValidateCreate() error {
 		allErrs = append(allErrs, fieldErr)
 	}
 
+	return allErrs
+}
+
+func (r *Sink) toInvalidError(allErrs field.ErrorList) error {
 	if len(allErrs) == 0 {
 		return nil
 	}
 
+	recordRejections("Sink", allErrs)
 	return apierrors.NewInvalid(schema.GroupKind{Group: "compute.functionmesh.io", Kind: "Sink"}, r.Name, allErrs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *Sink) ValidateUpdate(old runtime.Object) error {
 	sinklog.Info("validate update", "name", r.Name)
+	oldSink, ok := old.(*Sink)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a Sink but got a %T", old))
+	}
 
-	// TODO(user): fill in your validation logic upon object update.
-	return nil
+	// the finalizers must be removable even if the validation rules changed since the creation
+	if !r.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	if !reflect.DeepEqual(r.Spec, oldSink.Spec) {
+		allErrs = append(allErrs, r.validateSpec()...)
+	}
+
+	allErrs = append(allErrs, r.validateIdentityChanges(oldSink)...)
+	return r.toInvalidError(allErrs)
+}
+
+// validateIdentityChanges rejects the changes of the fields identifying the Pulsar resources of the sink,
+// unless the new values are acknowledged by the annotation
+func (r *Sink) validateIdentityChanges(oldSink *Sink) field.ErrorList {
+	var allErrs field.ErrorList
+	var fieldErr *field.Error
+
+	fieldErr = validateIdentityChange("tenant", r.Spec.Tenant, oldSink.Spec.Tenant, r.Annotations)
+	if fieldErr != nil {
+		allErrs = append(allErrs, fieldErr)
+	}
+
+	fieldErr = validateIdentityChange("namespace", r.Spec.Namespace, oldSink.Spec.Namespace, r.Annotations)
+	if fieldErr != nil {
+		allErrs = append(allErrs, fieldErr)
+	}
+
+	fieldErr = validateIdentityChange("name", r.Spec.Name, oldSink.Spec.Name, r.Annotations)
+	if fieldErr != nil {
+		allErrs = append(allErrs, fieldErr)
+	}
+
+	// the subscription name defaults to the fully qualified name, only the changes of the effective name matter
+	fieldErr = validateIdentityChange("subscriptionName",
+		makeSubscriptionName(r.Spec.SubscriptionName, r.Spec.Tenant, r.Spec.Namespace, r.Spec.Name),
+		makeSubscriptionName(oldSink.Spec.SubscriptionName, oldSink.Spec.Tenant, oldSink.Spec.Namespace,
+			oldSink.Spec.Name), r.Annotations)
+	if fieldErr != nil {
+		allErrs = append(allErrs, fieldErr)
+	}
+
+	return allErrs
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
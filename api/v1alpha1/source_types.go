@@ -42,8 +42,13 @@ type SourceSpec struct {
 	// MaxReplicas indicates the maximum number of replicas and enables the HorizontalPodAutoscaler
 	// If provided, a default HPA with CPU at average of 80% will be used.
 	// For complex HPA strategies, please refer to Pod.HPAutoscaler.
-	MaxReplicas *int32     `json:"maxReplicas,omitempty"` // if provided, turn on autoscaling
-	Output      OutputConf `json:"output,omitempty"`
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"` // if provided, turn on autoscaling
+
+	// Suspend scales the source to zero and removes its HorizontalPodAutoscaler,
+	// the service is kept so that it can be resumed later
+	Suspend bool `json:"suspend,omitempty"`
+
+	Output OutputConf `json:"output,omitempty"`
 
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:pruning:PreserveUnknownFields
@@ -56,6 +61,10 @@ type SourceSpec struct {
 	ForwardSourceMessageProperty *bool                       `json:"forwardSourceMessageProperty,omitempty"`
 	Pod                          PodPolicy                   `json:"pod,omitempty"`
 
+	// Tuning tunes the instances of the source
+	// +kubebuilder:validation:Optional
+	Tuning *RuntimeTuning `json:"tuning,omitempty"`
+
 	// +kubebuilder:validation:Required
 	Messaging `json:",inline"`
 
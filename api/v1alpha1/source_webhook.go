@@ -19,6 +19,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"reflect"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -116,6 +117,11 @@ var _ webhook.Validator = &Source{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *Source) ValidateCreate() error {
 	sourcelog.Info("validate create source", "name", r.Name)
+	return r.toInvalidError(r.validateSpec())
+}
+
+// validateSpec runs the validations shared by the creation and the update of the source
+func (r *Source) validateSpec() field.ErrorList {
 	var allErrs field.ErrorList
 	var fieldErr *field.Error
 	var fieldErrs []*field.Error
@@ -134,6 +140,11 @@ func (r *Source) ValidateCreate() error {
 			"source must have java runtime specified"))
 	}
 
+	if r.Spec.Runtime.Custom != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("runtime", "custom"),
+			"custom runtime is only available for functions"))
+	}
+
 	fieldErrs = validateJavaRuntime(r.Spec.Java, r.Spec.ClassName)
 	if len(fieldErrs) > 0 {
 		allErrs = append(allErrs, fieldErrs...)
@@ -144,11 +155,26 @@ func (r *Source) ValidateCreate() error {
 		allErrs = append(allErrs, fieldErrs...)
 	}
 
+	fieldErrs = validateSourceBuiltinHPARules(r.Spec.Pod.BuiltinAutoscaler)
+	if len(fieldErrs) > 0 {
+		allErrs = append(allErrs, fieldErrs...)
+	}
+
 	fieldErr = validateResourceRequirement(r.Spec.Resources)
 	if fieldErr != nil {
 		allErrs = append(allErrs, fieldErr)
 	}
 
+	fieldErrs = validatePulsarMessaging(r.Spec.Pulsar)
+	if len(fieldErrs) > 0 {
+		allErrs = append(allErrs, fieldErrs...)
+	}
+
+	fieldErrs = validateRuntimeTuning(r.Spec.Tuning, r.Spec.Runtime)
+	if len(fieldErrs) > 0 {
+		allErrs = append(allErrs, fieldErrs...)
+	}
+
 	fieldErr = validateSourceConfig(r.Spec.SourceConfig)
 	if fieldErr != nil {
 		allErrs = append(allErrs, fieldErr)
@@ -164,19 +190,62 @@ func (r *Source) ValidateCreate() error {
 		allErrs = append(allErrs, fieldErrs...)
 	}
 
+	return allErrs
+}
+
+func (r *Source) toInvalidError(allErrs field.ErrorList) error {
 	if len(allErrs) == 0 {
 		return nil
 	}
 
+	recordRejections("Source", allErrs)
 	return apierrors.NewInvalid(schema.GroupKind{Group: "compute.functionmesh.io", Kind: "Source"}, r.Name, allErrs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *Source) ValidateUpdate(old runtime.Object) error {
 	sourcelog.Info("validate update", "name", r.Name)
+	oldSource, ok := old.(*Source)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a Source but got a %T", old))
+	}
 
-	// TODO(user): fill in your validation logic upon object update.
-	return nil
+	// the finalizers must be removable even if the validation rules changed since the creation
+	if !r.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	if !reflect.DeepEqual(r.Spec, oldSource.Spec) {
+		allErrs = append(allErrs, r.validateSpec()...)
+	}
+
+	allErrs = append(allErrs, r.validateIdentityChanges(oldSource)...)
+	return r.toInvalidError(allErrs)
+}
+
+// validateIdentityChanges rejects the changes of the fields identifying the Pulsar resources of the source,
+// unless the new values are acknowledged by the annotation
+func (r *Source) validateIdentityChanges(oldSource *Source) field.ErrorList {
+	var allErrs field.ErrorList
+	var fieldErr *field.Error
+
+	fieldErr = validateIdentityChange("tenant", r.Spec.Tenant, oldSource.Spec.Tenant, r.Annotations)
+	if fieldErr != nil {
+		allErrs = append(allErrs, fieldErr)
+	}
+
+	fieldErr = validateIdentityChange("namespace", r.Spec.Namespace, oldSource.Spec.Namespace, r.Annotations)
+	if fieldErr != nil {
+		allErrs = append(allErrs, fieldErr)
+	}
+
+	fieldErr = validateIdentityChange("name", r.Spec.Name, oldSource.Spec.Name, r.Annotations)
+	if fieldErr != nil {
+		allErrs = append(allErrs, fieldErr)
+	}
+
+	return allErrs
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
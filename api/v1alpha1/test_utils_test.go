@@ -0,0 +1,73 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func makeTestFunctionSpec() FunctionSpec {
+	replicas := int32(1)
+	maxReplicas := int32(5)
+	return FunctionSpec{
+		Name:        "fn",
+		ClassName:   "org.apache.pulsar.functions.api.examples.ExclamationFunction",
+		Tenant:      "public",
+		Namespace:   "default",
+		Replicas:    &replicas,
+		MaxReplicas: &maxReplicas,
+		Input: InputConf{
+			Topics:        []string{"persistent://public/default/fn-input"},
+			TypeClassName: "java.lang.String",
+		},
+		Output: OutputConf{
+			Topic:         "persistent://public/default/fn-output",
+			TypeClassName: "java.lang.String",
+		},
+		Messaging: Messaging{
+			Pulsar: &PulsarMessaging{PulsarConfig: "test-pulsar"},
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+		},
+		Runtime: Runtime{
+			Java: &JavaRuntime{
+				Jar:         "pulsar-functions-api-examples.jar",
+				JarLocation: "function://public/default/fn@1.0",
+			},
+		},
+	}
+}
+
+func makeTestFunction(name string) *Function {
+	function := &Function{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       makeTestFunctionSpec(),
+	}
+	function.Default()
+	return function
+}
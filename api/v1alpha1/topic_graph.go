@@ -0,0 +1,293 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha1
+
+import (
+	"regexp"
+	"sort"
+
+	pctlutil "github.com/streamnative/pulsarctl/pkg/pulsar/utils"
+)
+
+// TopicSchemaMismatch is a topic whose producer and consumer in a mesh declare different schema types
+type TopicSchemaMismatch struct {
+	Topic              string `json:"topic"`
+	Producer           string `json:"producer"`
+	ProducerSchemaType string `json:"producerSchemaType"`
+	Consumer           string `json:"consumer"`
+	ConsumerSchemaType string `json:"consumerSchemaType"`
+}
+
+// TopicGraphNode is a source, function or sink of a mesh with the topics it consumes and produces,
+// the topic names are fully qualified
+// +kubebuilder:object:generate=false
+type TopicGraphNode struct {
+	// ID is the kind and the name of the component, such as function/ex1
+	ID     string
+	Kind   string
+	Name   string
+	Inputs []string
+	// InputPatterns are the regex patterns of the input topics
+	InputPatterns []*regexp.Regexp
+	// InputSchemaTypes are the schema types declared for the input topics
+	InputSchemaTypes map[string]string
+	Output           string
+	OutputSchemaType string
+}
+
+// TopicGraph is the graph of the components of a mesh connected by the topics they produce and consume
+// +kubebuilder:object:generate=false
+type TopicGraph struct {
+	// Nodes are in the order of the sources, functions and sinks of the mesh spec
+	Nodes []*TopicGraphNode
+}
+
+// NewTopicGraph builds the topic graph of the mesh
+func NewTopicGraph(spec *FunctionMeshSpec) *TopicGraph {
+	graph := &TopicGraph{}
+	for i := range spec.Sources {
+		source := &spec.Sources[i]
+		graph.Nodes = append(graph.Nodes, newTopicGraphNode(SourceComponent, source.Name, nil, &source.Output))
+	}
+	for i := range spec.Functions {
+		function := &spec.Functions[i]
+		graph.Nodes = append(graph.Nodes,
+			newTopicGraphNode(FunctionComponent, function.Name, &function.Input, &function.Output))
+	}
+	for i := range spec.Sinks {
+		sink := &spec.Sinks[i]
+		graph.Nodes = append(graph.Nodes, newTopicGraphNode(SinkComponent, sink.Name, &sink.Input, nil))
+	}
+	return graph
+}
+
+func newTopicGraphNode(kind, name string, input *InputConf, output *OutputConf) *TopicGraphNode {
+	node := &TopicGraphNode{
+		ID:               kind + "/" + name,
+		Kind:             kind,
+		Name:             name,
+		InputSchemaTypes: map[string]string{},
+	}
+	if input != nil {
+		seen := map[string]bool{}
+		for _, topic := range collectAllInputTopics(*input) {
+			conf, isSourceSpec := input.SourceSpecs[topic]
+			if topic == input.TopicPattern || (isSourceSpec && conf.IsRegexPattern) {
+				if pattern, err := regexp.Compile(NormalizeTopicName(topic)); err == nil {
+					node.InputPatterns = append(node.InputPatterns, pattern)
+				}
+				continue
+			}
+			normalized := NormalizeTopicName(topic)
+			if !seen[normalized] {
+				seen[normalized] = true
+				node.Inputs = append(node.Inputs, normalized)
+			}
+			if isSourceSpec && conf.SchemaType != "" {
+				node.InputSchemaTypes[normalized] = conf.SchemaType
+			}
+		}
+		sort.Strings(node.Inputs)
+	}
+	if output != nil && output.Topic != "" {
+		node.Output = NormalizeTopicName(output.Topic)
+		node.OutputSchemaType = output.SinkSchemaType
+	}
+	return node
+}
+
+// NormalizeTopicName returns the fully qualified name of the topic,
+// such as persistent://public/default/my-topic for my-topic
+func NormalizeTopicName(topic string) string {
+	topicName, err := pctlutil.GetTopicName(topic)
+	if err != nil {
+		return topic
+	}
+	return topicName.String()
+}
+
+// Consumes returns true if the node consumes the topic directly or through one of its patterns
+func (n *TopicGraphNode) Consumes(topic string) bool {
+	for _, input := range n.Inputs {
+		if input == topic {
+			return true
+		}
+	}
+	for _, pattern := range n.InputPatterns {
+		if pattern.MatchString(topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// Successors returns the nodes consuming the output topic of the node
+func (g *TopicGraph) Successors(node *TopicGraphNode) []*TopicGraphNode {
+	var successors []*TopicGraphNode
+	if node.Output == "" {
+		return successors
+	}
+	for _, other := range g.Nodes {
+		if other.Consumes(node.Output) {
+			successors = append(successors, other)
+		}
+	}
+	return successors
+}
+
+// ExternalInputs returns the input topics which are not produced by any component of the mesh,
+// the topics matched by the patterns are not included
+func (g *TopicGraph) ExternalInputs() []string {
+	produced := map[string]bool{}
+	for _, node := range g.Nodes {
+		if node.Output != "" {
+			produced[node.Output] = true
+		}
+	}
+	seen := map[string]bool{}
+	var inputs []string
+	for _, node := range g.Nodes {
+		for _, input := range node.Inputs {
+			if !produced[input] && !seen[input] {
+				seen[input] = true
+				inputs = append(inputs, input)
+			}
+		}
+	}
+	sort.Strings(inputs)
+	return inputs
+}
+
+// UnconsumedOutputs returns the output topics which are not consumed by any component of the mesh
+func (g *TopicGraph) UnconsumedOutputs() []string {
+	seen := map[string]bool{}
+	var outputs []string
+	for _, node := range g.Nodes {
+		if node.Output != "" && len(g.Successors(node)) == 0 && !seen[node.Output] {
+			seen[node.Output] = true
+			outputs = append(outputs, node.Output)
+		}
+	}
+	sort.Strings(outputs)
+	return outputs
+}
+
+// Cycles returns the cycles of the graph, each cycle is the list of the IDs of the nodes on it
+// starting from the first node found in the order of the mesh spec
+func (g *TopicGraph) Cycles() [][]string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[*TopicGraphNode]int{}
+	var stack []*TopicGraphNode
+	var cycles [][]string
+
+	var visit func(node *TopicGraphNode)
+	visit = func(node *TopicGraphNode) {
+		state[node] = visiting
+		stack = append(stack, node)
+		for _, successor := range g.Successors(node) {
+			switch state[successor] {
+			case unvisited:
+				visit(successor)
+			case visiting:
+				// the successor is on the stack, the nodes from it to the current node form a cycle
+				var cycle []string
+				for i := len(stack) - 1; i >= 0; i-- {
+					cycle = append([]string{stack[i].ID}, cycle...)
+					if stack[i] == successor {
+						break
+					}
+				}
+				cycles = append(cycles, cycle)
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[node] = visited
+	}
+
+	for _, node := range g.Nodes {
+		if state[node] == unvisited {
+			visit(node)
+		}
+	}
+	return cycles
+}
+
+// SchemaMismatches returns the topics whose producer declares a schema type different from the one of a consumer
+func (g *TopicGraph) SchemaMismatches() []TopicSchemaMismatch {
+	var mismatches []TopicSchemaMismatch
+	for _, producer := range g.Nodes {
+		if producer.Output == "" || producer.OutputSchemaType == "" {
+			continue
+		}
+		for _, consumer := range g.Successors(producer) {
+			schemaType, ok := consumer.InputSchemaTypes[producer.Output]
+			if ok && schemaType != producer.OutputSchemaType {
+				mismatches = append(mismatches, TopicSchemaMismatch{
+					Topic:              producer.Output,
+					Producer:           producer.ID,
+					ProducerSchemaType: producer.OutputSchemaType,
+					Consumer:           consumer.ID,
+					ConsumerSchemaType: schemaType,
+				})
+			}
+		}
+	}
+	return mismatches
+}
+
+// ConsumerFirstStages groups the nodes into stages, each node is in a later stage than all the nodes
+// consuming its output topic, so that the consumers of a topic are started before its producers.
+// The nodes of a stage are in the order of the mesh spec, the edges closing a cycle are ignored.
+func (g *TopicGraph) ConsumerFirstStages() [][]*TopicGraphNode {
+	levels := map[*TopicGraphNode]int{}
+	visiting := map[*TopicGraphNode]bool{}
+
+	var level func(node *TopicGraphNode) int
+	level = func(node *TopicGraphNode) int {
+		if l, ok := levels[node]; ok {
+			return l
+		}
+		visiting[node] = true
+		l := 0
+		for _, successor := range g.Successors(node) {
+			if visiting[successor] {
+				continue
+			}
+			if successorLevel := level(successor) + 1; successorLevel > l {
+				l = successorLevel
+			}
+		}
+		visiting[node] = false
+		levels[node] = l
+		return l
+	}
+
+	var stages [][]*TopicGraphNode
+	for _, node := range g.Nodes {
+		l := level(node)
+		for len(stages) <= l {
+			stages = append(stages, nil)
+		}
+		stages[l] = append(stages[l], node)
+	}
+	return stages
+}
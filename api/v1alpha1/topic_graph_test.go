@@ -0,0 +1,294 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func testSource(name, output, schemaType string) SourceSpec {
+	return SourceSpec{Name: name, Output: OutputConf{Topic: output, SinkSchemaType: schemaType}}
+}
+
+func testFunction(name string, inputs []string, output string) FunctionSpec {
+	return FunctionSpec{Name: name, Input: InputConf{Topics: inputs}, Output: OutputConf{Topic: output}}
+}
+
+func testSink(name string, inputs ...string) SinkSpec {
+	return SinkSpec{Name: name, Input: InputConf{Topics: inputs}}
+}
+
+// nodeIDs returns the IDs of the nodes of each stage
+func nodeIDs(stages [][]*TopicGraphNode) [][]string {
+	ids := make([][]string, 0, len(stages))
+	for _, stage := range stages {
+		var stageIDs []string
+		for _, node := range stage {
+			stageIDs = append(stageIDs, node.ID)
+		}
+		ids = append(ids, stageIDs)
+	}
+	return ids
+}
+
+func TestNewTopicGraph(t *testing.T) {
+	spec := &FunctionMeshSpec{
+		Sources: []SourceSpec{testSource("src", "a", "JSON")},
+		Functions: []FunctionSpec{{
+			Name: "fn",
+			Input: InputConf{
+				Topics:       []string{"a", "persistent://public/default/a", "b"},
+				TopicPattern: "persistent://public/default/c-.*",
+				SourceSpecs: map[string]ConsumerConfig{
+					"b":    {SchemaType: "AVRO"},
+					"d-.*": {IsRegexPattern: true},
+				},
+			},
+			Output: OutputConf{Topic: "out", SinkSchemaType: "STRING"},
+		}},
+		Sinks: []SinkSpec{testSink("sink", "out")},
+	}
+
+	graph := NewTopicGraph(spec)
+	assert.Equal(t, len(graph.Nodes), 3)
+
+	source := graph.Nodes[0]
+	assert.Equal(t, source.ID, "source/src")
+	assert.Equal(t, source.Output, "persistent://public/default/a")
+	assert.Equal(t, source.OutputSchemaType, "JSON")
+	assert.Equal(t, len(source.Inputs), 0)
+
+	function := graph.Nodes[1]
+	assert.Equal(t, function.ID, "function/fn")
+	assert.DeepEqual(t, function.Inputs, []string{"persistent://public/default/a", "persistent://public/default/b"})
+	assert.DeepEqual(t, function.InputSchemaTypes, map[string]string{"persistent://public/default/b": "AVRO"})
+	assert.Equal(t, len(function.InputPatterns), 2)
+	assert.Assert(t, function.Consumes("persistent://public/default/c-1"))
+	assert.Assert(t, function.Consumes("persistent://public/default/d-1"))
+	assert.Assert(t, !function.Consumes("persistent://public/default/e"))
+
+	sink := graph.Nodes[2]
+	assert.Equal(t, sink.ID, "sink/sink")
+	assert.DeepEqual(t, sink.Inputs, []string{"persistent://public/default/out"})
+	assert.Equal(t, sink.Output, "")
+	assert.DeepEqual(t, graph.Successors(function), []*TopicGraphNode{sink})
+}
+
+func TestTopicGraphCycles(t *testing.T) {
+	testCases := []struct {
+		name   string
+		spec   *FunctionMeshSpec
+		cycles [][]string
+	}{
+		{
+			name: "pipeline",
+			spec: &FunctionMeshSpec{
+				Sources:   []SourceSpec{testSource("src", "a", "")},
+				Functions: []FunctionSpec{testFunction("fn", []string{"a"}, "b")},
+				Sinks:     []SinkSpec{testSink("sink", "b")},
+			},
+		},
+		{
+			name: "self loop",
+			spec: &FunctionMeshSpec{
+				Functions: []FunctionSpec{testFunction("fn", []string{"a"}, "a")},
+			},
+			cycles: [][]string{{"function/fn"}},
+		},
+		{
+			name: "loop between functions",
+			spec: &FunctionMeshSpec{
+				Sources: []SourceSpec{testSource("src", "a", "")},
+				Functions: []FunctionSpec{
+					testFunction("fn1", []string{"a", "c"}, "b"),
+					testFunction("fn2", []string{"b"}, "c"),
+				},
+			},
+			cycles: [][]string{{"function/fn1", "function/fn2"}},
+		},
+		{
+			name: "loop through a pattern",
+			spec: &FunctionMeshSpec{
+				Functions: []FunctionSpec{
+					{Name: "fn1", Input: InputConf{TopicPattern: "persistent://public/default/b-.*"},
+						Output: OutputConf{Topic: "a"}},
+					testFunction("fn2", []string{"a"}, "b-1"),
+				},
+			},
+			cycles: [][]string{{"function/fn1", "function/fn2"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.DeepEqual(t, NewTopicGraph(tc.spec).Cycles(), tc.cycles)
+		})
+	}
+}
+
+func TestTopicGraphSchemaMismatches(t *testing.T) {
+	consumer := func(schemaType string) FunctionSpec {
+		function := testFunction("fn", nil, "")
+		function.Input.SourceSpecs = map[string]ConsumerConfig{"a": {SchemaType: schemaType}}
+		return function
+	}
+
+	testCases := []struct {
+		name       string
+		spec       *FunctionMeshSpec
+		mismatches []TopicSchemaMismatch
+	}{
+		{
+			name: "same schema type",
+			spec: &FunctionMeshSpec{
+				Sources:   []SourceSpec{testSource("src", "a", "JSON")},
+				Functions: []FunctionSpec{consumer("JSON")},
+			},
+		},
+		{
+			name: "schema type not declared by the producer",
+			spec: &FunctionMeshSpec{
+				Sources:   []SourceSpec{testSource("src", "a", "")},
+				Functions: []FunctionSpec{consumer("JSON")},
+			},
+		},
+		{
+			name: "schema type not declared by the consumer",
+			spec: &FunctionMeshSpec{
+				Sources:   []SourceSpec{testSource("src", "a", "JSON")},
+				Functions: []FunctionSpec{testFunction("fn", []string{"a"}, "")},
+			},
+		},
+		{
+			name: "different schema types",
+			spec: &FunctionMeshSpec{
+				Sources:   []SourceSpec{testSource("src", "a", "JSON")},
+				Functions: []FunctionSpec{consumer("AVRO")},
+			},
+			mismatches: []TopicSchemaMismatch{{
+				Topic:              "persistent://public/default/a",
+				Producer:           "source/src",
+				ProducerSchemaType: "JSON",
+				Consumer:           "function/fn",
+				ConsumerSchemaType: "AVRO",
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.DeepEqual(t, NewTopicGraph(tc.spec).SchemaMismatches(), tc.mismatches)
+		})
+	}
+}
+
+func TestTopicGraphExternalInputsAndUnconsumedOutputs(t *testing.T) {
+	testCases := []struct {
+		name              string
+		spec              *FunctionMeshSpec
+		externalInputs    []string
+		unconsumedOutputs []string
+	}{
+		{
+			name: "closed pipeline",
+			spec: &FunctionMeshSpec{
+				Sources:   []SourceSpec{testSource("src", "a", "")},
+				Functions: []FunctionSpec{testFunction("fn", []string{"a"}, "b")},
+				Sinks:     []SinkSpec{testSink("sink", "b")},
+			},
+		},
+		{
+			name: "open ends",
+			spec: &FunctionMeshSpec{
+				Functions: []FunctionSpec{
+					testFunction("fn1", []string{"x", "a"}, "b"),
+					testFunction("fn2", []string{"b", "x"}, "c"),
+				},
+				Sinks: []SinkSpec{testSink("sink", "persistent://public/default/b")},
+			},
+			externalInputs:    []string{"persistent://public/default/a", "persistent://public/default/x"},
+			unconsumedOutputs: []string{"persistent://public/default/c"},
+		},
+		{
+			name: "output consumed through a pattern",
+			spec: &FunctionMeshSpec{
+				Functions: []FunctionSpec{testFunction("fn", []string{"a"}, "b-1")},
+				Sinks: []SinkSpec{{Name: "sink",
+					Input: InputConf{TopicPattern: "persistent://public/default/b-.*"}}},
+			},
+			externalInputs: []string{"persistent://public/default/a"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			graph := NewTopicGraph(tc.spec)
+			assert.DeepEqual(t, graph.ExternalInputs(), tc.externalInputs)
+			assert.DeepEqual(t, graph.UnconsumedOutputs(), tc.unconsumedOutputs)
+		})
+	}
+}
+
+func TestTopicGraphConsumerFirstStages(t *testing.T) {
+	testCases := []struct {
+		name   string
+		spec   *FunctionMeshSpec
+		stages [][]string
+	}{
+		{
+			name: "unconnected components",
+			spec: &FunctionMeshSpec{
+				Functions: []FunctionSpec{
+					testFunction("fn1", []string{"a"}, "b"),
+					testFunction("fn2", []string{"c"}, "d"),
+				},
+			},
+			stages: [][]string{{"function/fn1", "function/fn2"}},
+		},
+		{
+			name: "pipeline",
+			spec: &FunctionMeshSpec{
+				Sources: []SourceSpec{testSource("src", "a", "")},
+				Functions: []FunctionSpec{
+					testFunction("fn1", []string{"a"}, "b"),
+					testFunction("fn2", []string{"b"}, "c"),
+				},
+				Sinks: []SinkSpec{testSink("sink", "c"), testSink("audit", "a")},
+			},
+			stages: [][]string{{"sink/sink", "sink/audit"}, {"function/fn2"}, {"function/fn1"}, {"source/src"}},
+		},
+		{
+			name: "cycle",
+			spec: &FunctionMeshSpec{
+				Functions: []FunctionSpec{
+					testFunction("fn1", []string{"b"}, "a"),
+					testFunction("fn2", []string{"a"}, "b"),
+				},
+			},
+			stages: [][]string{{"function/fn2"}, {"function/fn1"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.DeepEqual(t, nodeIDs(NewTopicGraph(tc.spec).ConsumerFirstStages()), tc.stages)
+		})
+	}
+}
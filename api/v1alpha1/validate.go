@@ -20,6 +20,9 @@ package v1alpha1
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -45,6 +48,10 @@ func validateJavaRuntime(java *JavaRuntime, className string) []*field.Error {
 				allErrs = append(allErrs, e)
 			}
 		}
+		if e := validatePackageChecksum(field.NewPath("spec").Child("java", "checksum"), java.Checksum,
+			java.JarLocation); e != nil {
+			allErrs = append(allErrs, e)
+		}
 	}
 	return allErrs
 }
@@ -68,6 +75,70 @@ func validatePythonRuntime(python *PythonRuntime, className string) []*field.Err
 				allErrs = append(allErrs, e)
 			}
 		}
+		if e := validatePackageChecksum(field.NewPath("spec").Child("python", "checksum"), python.Checksum,
+			python.PyLocation); e != nil {
+			allErrs = append(allErrs, e)
+		}
+		allErrs = append(allErrs, validatePythonDependencies(python)...)
+	}
+	return allErrs
+}
+
+// validatePythonDependencies checks the sources of the requirements, the instance installing the dependencies
+// on start only reads the requirements bundled in a zip package
+func validatePythonDependencies(python *PythonRuntime) []*field.Error {
+	var allErrs field.ErrorList
+	dependencies := python.Dependencies
+	if dependencies == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec").Child("python", "dependencies")
+	sources := 0
+	if len(dependencies.Requirements) > 0 {
+		sources++
+	}
+	if ref := dependencies.RequirementsConfigMap; ref != nil {
+		sources++
+		if ref.Name == "" || ref.Key == "" {
+			allErrs = append(allErrs, field.Required(path.Child("requirementsConfigMap"),
+				"name and key of the requirements config map are required"))
+		}
+	}
+	if file := dependencies.RequirementsFile; file != "" {
+		sources++
+		if !strings.HasSuffix(python.Py, ".zip") {
+			allErrs = append(allErrs, field.Invalid(path.Child("requirementsFile"), file,
+				"requirements file requires a zip package"))
+		}
+		if strings.HasPrefix(file, "/") || strings.Contains(file, "..") {
+			allErrs = append(allErrs, field.Invalid(path.Child("requirementsFile"), file,
+				"requirements file must be a relative path inside the package"))
+		}
+	}
+	if sources > 1 {
+		allErrs = append(allErrs, field.Invalid(path, dependencies,
+			"only one of requirements, requirementsConfigMap and requirementsFile can be set"))
+	}
+	if dependencies.InstallStrategy == InstallOnStart && sources > 0 {
+		allErrs = append(allErrs, field.Forbidden(path.Child("installStrategy"),
+			"requirements can only be installed by the init container"))
+	}
+	if dependencies.InstallStrategy != InstallOnStart && sources == 0 && !strings.HasSuffix(python.Py, ".whl") {
+		allErrs = append(allErrs, field.Required(path,
+			"requirements are required to install the dependencies of a package other than a wheel"))
+	}
+	if dependencies.IndexURL != "" {
+		if u, err := url.Parse(dependencies.IndexURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+			u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("indexURL"), dependencies.IndexURL,
+				"index url must be an http or https url"))
+		} else if u.User != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("indexURL"), dependencies.IndexURL,
+				"credentials of the index must be set in the index secret"))
+		}
+	}
+	if dependencies.IndexSecret != "" && dependencies.IndexURL == "" {
+		allErrs = append(allErrs, field.Required(path.Child("indexURL"), "index secret requires an index url"))
 	}
 	return allErrs
 }
@@ -87,6 +158,81 @@ func validateGolangRuntime(golang *GoRuntime) []*field.Error {
 				allErrs = append(allErrs, e)
 			}
 		}
+		if e := validatePackageChecksum(field.NewPath("spec").Child("golang", "checksum"), golang.Checksum,
+			golang.GoLocation); e != nil {
+			allErrs = append(allErrs, e)
+		}
+	}
+	return allErrs
+}
+
+// validateCustomRuntime checks that the function provides the image and the command of the custom runtime,
+// the window functions are run by the executor of the Java runtime
+func validateCustomRuntime(spec *FunctionSpec) []*field.Error {
+	var allErrs field.ErrorList
+	if spec.Custom == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec").Child("custom")
+	if len(spec.Custom.Command) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("command"), "command is required in custom runtime"))
+	}
+	if spec.Image == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec").Child("image"),
+			"image is required in custom runtime"))
+	}
+	if spec.WindowConfig != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("windowConfig"),
+			"window functions are not supported by the custom runtime"))
+	}
+	return allErrs
+}
+
+// validateGolangFunction rejects the settings the Go runtime cannot honour, they are either implemented
+// by Java classes or not supported by the Go instance
+func validateGolangFunction(spec *FunctionSpec) []*field.Error {
+	var allErrs field.ErrorList
+	if spec.Golang == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec")
+	if spec.ProcessingGuarantee == EffectivelyOnce {
+		allErrs = append(allErrs, field.NotSupported(path.Child("processingGuarantee"), spec.ProcessingGuarantee,
+			[]string{string(AtleastOnce), string(AtmostOnce)}))
+	}
+	if spec.RetainKeyOrdering {
+		allErrs = append(allErrs, field.Forbidden(path.Child("retainKeyOrdering"),
+			"key ordering is not supported by the Go runtime"))
+	}
+	if spec.WindowConfig != nil {
+		allErrs = append(allErrs, field.Forbidden(path.Child("windowConfig"),
+			"window functions are not supported by the Go runtime"))
+	}
+	if len(spec.Input.CustomSerdeSources) > 0 {
+		allErrs = append(allErrs, field.Forbidden(path.Child("input", "customSerdeSources"),
+			"serde classes are not supported by the Go runtime"))
+	}
+	for topic, conf := range spec.Input.SourceSpecs {
+		if conf.SerdeClassName != "" {
+			allErrs = append(allErrs, field.Forbidden(path.Child("input", "sourceSpecs").Key(topic).Child("serdeClassname"),
+				"serde classes are not supported by the Go runtime"))
+		}
+		if conf.CryptoConfig != nil {
+			allErrs = append(allErrs, field.Forbidden(path.Child("input", "sourceSpecs").Key(topic).Child("cryptoConfig"),
+				"crypto key readers are not supported by the Go runtime"))
+		}
+	}
+	if spec.Output.SinkSerdeClassName != "" {
+		allErrs = append(allErrs, field.Forbidden(path.Child("output", "sinkSerdeClassName"),
+			"serde classes are not supported by the Go runtime"))
+	}
+	if len(spec.Output.CustomSchemaSinks) > 0 {
+		allErrs = append(allErrs, field.Forbidden(path.Child("output", "customSchemaSinks"),
+			"the Go runtime only produces to the output topic"))
+	}
+	if spec.Output.ProducerConf != nil && spec.Output.ProducerConf.CryptoConfig != nil {
+		allErrs = append(allErrs, field.Forbidden(path.Child("output", "producerConf", "cryptoConfig"),
+			"crypto key readers are not supported by the Go runtime"))
 	}
 	return allErrs
 }
@@ -112,6 +258,74 @@ func validateReplicasAndMaxReplicas(replicas, maxReplicas *int32) []*field.Error
 	return allErrs
 }
 
+func validateSourceBuiltinHPARules(rules []BuiltinHPARule) []*field.Error {
+	var allErrs field.ErrorList
+	for _, rule := range rules {
+		if rule.IsBacklogRule() {
+			e := field.Invalid(field.NewPath("spec").Child("pod", "builtinAutoscaler"), rule,
+				"backlog based autoscaling is not available for sources since they have no input topics")
+			allErrs = append(allErrs, e)
+		}
+	}
+	return allErrs
+}
+
+func validateIdlePolicy(policy *IdlePolicy, input InputConf) *field.Error {
+	if policy != nil && input.TopicPattern != "" {
+		return field.Invalid(field.NewPath("spec").Child("idlePolicy"), policy,
+			"idle policy is not available for the topic pattern input")
+	}
+	return nil
+}
+
+// validateRuntimeTuning checks that the tuning applies to the runtime of the component,
+// the heap of the JVM is only set with the heap percentage
+func validateRuntimeTuning(tuning *RuntimeTuning, runtime Runtime) []*field.Error {
+	var allErrs field.ErrorList
+	if tuning == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec").Child("tuning")
+	if tuning.MaxBufferedTuples != nil && *tuning.MaxBufferedTuples < 1 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxBufferedTuples"), *tuning.MaxBufferedTuples,
+			"max buffered tuples must be greater than 0"))
+	}
+	if tuning.ExpectedHealthCheckIntervalSeconds != nil && *tuning.ExpectedHealthCheckIntervalSeconds < -1 {
+		allErrs = append(allErrs, field.Invalid(path.Child("expectedHealthCheckIntervalSeconds"),
+			*tuning.ExpectedHealthCheckIntervalSeconds, "health check interval must be -1 or greater"))
+	}
+	if jvm := tuning.JVM; jvm != nil {
+		if runtime.Java == nil {
+			allErrs = append(allErrs, field.Forbidden(path.Child("jvm"), "jvm tuning requires the java runtime"))
+		}
+		if jvm.HeapPercentage != nil && (*jvm.HeapPercentage < 1 || *jvm.HeapPercentage > 100) {
+			allErrs = append(allErrs, field.Invalid(path.Child("jvm", "heapPercentage"), *jvm.HeapPercentage,
+				"heap percentage must be between 1 and 100"))
+		}
+		for i, option := range jvm.Options {
+			if !strings.HasPrefix(option, "-") {
+				allErrs = append(allErrs, field.Invalid(path.Child("jvm", "options").Index(i), option,
+					"jvm option must start with -"))
+			} else if strings.HasPrefix(option, "-Xmx") || strings.HasPrefix(option, "-XX:MaxRAMPercentage") {
+				allErrs = append(allErrs, field.Invalid(path.Child("jvm", "options").Index(i), option,
+					"the maximum heap is set with the heap percentage"))
+			}
+		}
+	}
+	if python := tuning.Python; python != nil {
+		if runtime.Python == nil {
+			allErrs = append(allErrs, field.Forbidden(path.Child("python"), "python tuning requires the python runtime"))
+		}
+		for i, flag := range python.InterpreterFlags {
+			if !strings.HasPrefix(flag, "-") {
+				allErrs = append(allErrs, field.Invalid(path.Child("python", "interpreterFlags").Index(i), flag,
+					"interpreter flag must start with -"))
+			}
+		}
+	}
+	return allErrs
+}
+
 func validateResourceRequirement(requirements corev1.ResourceRequirements) *field.Error {
 	if !validResourceRequirement(requirements) {
 		return field.Invalid(field.NewPath("spec").Child("resources"), requirements, "resource requirement is invalid")
@@ -119,6 +333,52 @@ func validateResourceRequirement(requirements corev1.ResourceRequirements) *fiel
 	return nil
 }
 
+// packageChecksumPattern matches the sha256 checksums, the checksum is passed to the shell verifying the package
+var packageChecksumPattern = regexp.MustCompile(`^` + PackageChecksumSHA256 + `[0-9a-f]{64}$`)
+
+// validatePackageChecksum checks that the checksum is a sha256 checksum and that the package verified
+// with it is downloaded
+func validatePackageChecksum(path *field.Path, checksum, packageLocation string) *field.Error {
+	if checksum == "" {
+		return nil
+	}
+	if !strings.HasPrefix(checksum, PackageChecksumSHA256) {
+		return field.Invalid(path, checksum, "only sha256 checksums are supported")
+	}
+	if !packageChecksumPattern.MatchString(checksum) {
+		return field.Invalid(path, checksum, "checksum must be sha256: followed by 64 lowercase hex digits")
+	}
+	if packageLocation == "" || IsFilePackageLocation(packageLocation) {
+		return field.Invalid(path, checksum, "checksum requires a package location to download the package from")
+	}
+	return nil
+}
+
+// validatePulsarMessaging rejects the Pulsar settings which are replaced by the referred connection
+func validatePulsarMessaging(messaging *PulsarMessaging) []*field.Error {
+	var allErrs []*field.Error
+	if messaging == nil || messaging.ConnectionRef == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec").Child("pulsar")
+	if messaging.ConnectionRef.Name == "" {
+		allErrs = append(allErrs, field.Required(path.Child("connectionRef", "name"), "connection name is not provided"))
+	}
+	if messaging.PulsarConfig != "" {
+		allErrs = append(allErrs, field.Forbidden(path.Child("pulsarConfig"), "cannot be set with connectionRef"))
+	}
+	if messaging.AuthSecret != "" {
+		allErrs = append(allErrs, field.Forbidden(path.Child("authSecret"), "cannot be set with connectionRef"))
+	}
+	if messaging.TLSSecret != "" {
+		allErrs = append(allErrs, field.Forbidden(path.Child("tlsSecret"), "cannot be set with connectionRef"))
+	}
+	if messaging.TLSConfig != nil {
+		allErrs = append(allErrs, field.Forbidden(path.Child("tlsConfig"), "cannot be set with connectionRef"))
+	}
+	return allErrs
+}
+
 func validateTimeout(timeout int32, processingGuarantee ProcessGuarantee) *field.Error {
 	if timeout != 0 && processingGuarantee == EffectivelyOnce {
 		return field.Invalid(field.NewPath("spec").Child("timeout"), timeout,
@@ -328,6 +588,139 @@ func validateStatefulFunctionConfigs(statefulFunctionConfigs *Stateful, runtime
 	return nil
 }
 
+func validateWindowConfigs(windowConfig *WindowConfig, runtime Runtime) []*field.Error {
+	var allErrs field.ErrorList
+	if windowConfig == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec").Child("windowConfig")
+
+	// the Python window function executor windows the messages by processing time
+	if runtime.Python != nil && windowConfig.TimestampExtractorClassName != nil {
+		e := field.Forbidden(path.Child("timestampExtractorClassName"),
+			"event time windows are not supported by the Python runtime")
+		allErrs = append(allErrs, e)
+	}
+
+	if windowConfig.WindowLengthCount == nil && windowConfig.WindowLengthDurationMs == nil {
+		e := field.Invalid(path, windowConfig, "window length is not specified")
+		allErrs = append(allErrs, e)
+	}
+
+	if windowConfig.WindowLengthCount != nil && windowConfig.WindowLengthDurationMs != nil {
+		e := field.Invalid(path, windowConfig,
+			"only one of window length count or window length duration can be set")
+		allErrs = append(allErrs, e)
+	}
+
+	if windowConfig.WindowLengthCount != nil && *windowConfig.WindowLengthCount <= 0 {
+		e := field.Invalid(path.Child("windowLengthCount"), *windowConfig.WindowLengthCount,
+			"window length must be positive")
+		allErrs = append(allErrs, e)
+	}
+
+	if windowConfig.WindowLengthDurationMs != nil && *windowConfig.WindowLengthDurationMs <= 0 {
+		e := field.Invalid(path.Child("windowLengthDurationMs"), *windowConfig.WindowLengthDurationMs,
+			"window length must be positive")
+		allErrs = append(allErrs, e)
+	}
+
+	if windowConfig.SlidingIntervalCount != nil && windowConfig.SlidingIntervalDurationMs != nil {
+		e := field.Invalid(path, windowConfig,
+			"only one of sliding interval count or sliding interval duration can be set")
+		allErrs = append(allErrs, e)
+	}
+
+	if windowConfig.SlidingIntervalCount != nil && *windowConfig.SlidingIntervalCount <= 0 {
+		e := field.Invalid(path.Child("slidingIntervalCount"), *windowConfig.SlidingIntervalCount,
+			"sliding interval must be positive")
+		allErrs = append(allErrs, e)
+	}
+
+	if windowConfig.SlidingIntervalDurationMs != nil && *windowConfig.SlidingIntervalDurationMs <= 0 {
+		e := field.Invalid(path.Child("slidingIntervalDurationMs"), *windowConfig.SlidingIntervalDurationMs,
+			"sliding interval must be positive")
+		allErrs = append(allErrs, e)
+	}
+
+	if windowConfig.TimestampExtractorClassName != nil {
+		if *windowConfig.TimestampExtractorClassName == "" {
+			e := field.Invalid(path.Child("timestampExtractorClassName"), *windowConfig.TimestampExtractorClassName,
+				"timestamp extractor class name cannot be empty")
+			allErrs = append(allErrs, e)
+		}
+		if windowConfig.MaxLagMs != nil && *windowConfig.MaxLagMs < 0 {
+			e := field.Invalid(path.Child("maxLagMs"), *windowConfig.MaxLagMs,
+				"lag duration must be zero or positive")
+			allErrs = append(allErrs, e)
+		}
+		if windowConfig.WatermarkEmitIntervalMs != nil && *windowConfig.WatermarkEmitIntervalMs <= 0 {
+			e := field.Invalid(path.Child("watermarkEmitIntervalMs"), *windowConfig.WatermarkEmitIntervalMs,
+				"watermark interval must be positive")
+			allErrs = append(allErrs, e)
+		}
+	} else if windowConfig.LateDataTopic != "" {
+		e := field.Invalid(path.Child("lateDataTopic"), windowConfig.LateDataTopic,
+			"late data topic can only be set with a timestamp extractor")
+		allErrs = append(allErrs, e)
+	}
+
+	if windowConfig.LateDataTopic != "" {
+		err := isValidTopicName(windowConfig.LateDataTopic)
+		if err != nil {
+			e := field.Invalid(path.Child("lateDataTopic"), windowConfig.LateDataTopic,
+				fmt.Sprintf("Late data topic %s is invalid", windowConfig.LateDataTopic))
+			allErrs = append(allErrs, e)
+		}
+	}
+
+	return allErrs
+}
+
+// countRuntimes returns the number of runtimes set, a component runs with exactly one runtime
+func countRuntimes(runtime Runtime) int {
+	count := 0
+	for _, set := range []bool{runtime.Java != nil, runtime.Python != nil, runtime.Golang != nil,
+		runtime.Custom != nil} {
+		if set {
+			count++
+		}
+	}
+	return count
+}
+
 func isGolangRuntime(runtime Runtime) bool {
 	return runtime.Golang != nil && runtime.Python == nil && runtime.Java == nil
 }
+
+// isIdentityChangeAcknowledged returns true when the annotation acknowledges the change of the field to the value,
+// the annotation lists the acknowledged changes as comma separated field=value pairs
+func isIdentityChangeAcknowledged(annotations map[string]string, fieldName, value string) bool {
+	for _, change := range strings.Split(annotations[AnnotationAcknowledgeIdentityChange], ",") {
+		if strings.TrimSpace(change) == fieldName+"="+value {
+			return true
+		}
+	}
+	return false
+}
+
+// validateIdentityChange rejects the change of a field identifying the Pulsar resources of a component,
+// the state of the previous resources such as the consumer progress of a subscription is abandoned by the change
+func validateIdentityChange(fieldName, value, oldValue string, annotations map[string]string) *field.Error {
+	if value == oldValue || isIdentityChangeAcknowledged(annotations, fieldName, value) {
+		return nil
+	}
+	return field.Forbidden(field.NewPath("spec").Child(fieldName), fmt.Sprintf(
+		"changing the value from %q to %q abandons the pulsar resources of the previous value, "+
+			"add %q to the annotation %s to acknowledge the migration",
+		oldValue, value, fieldName+"="+value, AnnotationAcknowledgeIdentityChange))
+}
+
+// makeSubscriptionName returns the subscription name used by the instances,
+// which defaults to the fully qualified name of the component
+func makeSubscriptionName(subscriptionName, tenant, namespace, name string) string {
+	if subscriptionName != "" {
+		return subscriptionName
+	}
+	return fmt.Sprintf("%s/%s/%s", tenant, namespace, name)
+}
@@ -0,0 +1,208 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestIsIdentityChangeAcknowledged(t *testing.T) {
+	annotations := map[string]string{
+		AnnotationAcknowledgeIdentityChange: "tenant=team-a, subscriptionName=team-a/default/fn",
+	}
+	assert.Assert(t, isIdentityChangeAcknowledged(annotations, "tenant", "team-a"))
+	assert.Assert(t, isIdentityChangeAcknowledged(annotations, "subscriptionName", "team-a/default/fn"))
+	assert.Assert(t, !isIdentityChangeAcknowledged(annotations, "tenant", "team-b"))
+	assert.Assert(t, !isIdentityChangeAcknowledged(annotations, "namespace", "team-a"))
+	assert.Assert(t, !isIdentityChangeAcknowledged(nil, "tenant", "team-a"))
+	// the acknowledgement of any value is no longer supported
+	assert.Assert(t, !isIdentityChangeAcknowledged(map[string]string{AnnotationAcknowledgeIdentityChange: "true"},
+		"tenant", "team-a"))
+}
+
+func TestFunctionValidateIdentityChanges(t *testing.T) {
+	testCases := []struct {
+		name        string
+		update      func(function *Function)
+		annotation  string
+		expectPaths []string
+	}{
+		{
+			name:   "no identity change",
+			update: func(function *Function) { function.Spec.ClassName = "org.example.Other" },
+		},
+		{
+			name:        "tenant change",
+			update:      func(function *Function) { function.Spec.Tenant = "team-a" },
+			expectPaths: []string{"spec.tenant", "spec.subscriptionName"},
+		},
+		{
+			name:       "acknowledged tenant change",
+			update:     func(function *Function) { function.Spec.Tenant = "team-a" },
+			annotation: "tenant=team-a,subscriptionName=team-a/default/fn",
+		},
+		{
+			name:        "acknowledged for another value",
+			update:      func(function *Function) { function.Spec.Tenant = "team-b" },
+			annotation:  "tenant=team-a,subscriptionName=team-a/default/fn",
+			expectPaths: []string{"spec.tenant", "spec.subscriptionName"},
+		},
+		{
+			name:        "name change",
+			update:      func(function *Function) { function.Spec.Name = "fn2" },
+			annotation:  "name=fn2",
+			expectPaths: []string{"spec.subscriptionName"},
+		},
+		{
+			name: "explicit subscription name kept",
+			update: func(function *Function) {
+				function.Spec.SubscriptionName = "public/default/fn"
+			},
+		},
+		{
+			name:        "subscription name change",
+			update:      func(function *Function) { function.Spec.SubscriptionName = "sub" },
+			expectPaths: []string{"spec.subscriptionName"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			oldFunction := makeTestFunction("fn")
+			function := oldFunction.DeepCopy()
+			tc.update(function)
+			if tc.annotation != "" {
+				function.Annotations = map[string]string{AnnotationAcknowledgeIdentityChange: tc.annotation}
+			}
+
+			errs := function.validateIdentityChanges(oldFunction)
+			assert.DeepEqual(t, fieldErrorPaths(errs), tc.expectPaths)
+			for _, err := range errs {
+				assert.Equal(t, err.Type, field.ErrorTypeForbidden)
+			}
+		})
+	}
+}
+
+func TestSourceAndSinkValidateIdentityChanges(t *testing.T) {
+	oldSource := &Source{Spec: SourceSpec{Name: "src", Tenant: "public", Namespace: "default"}}
+	source := oldSource.DeepCopy()
+	source.Spec.Namespace = "other"
+	assert.DeepEqual(t, fieldErrorPaths(source.validateIdentityChanges(oldSource)), []string{"spec.namespace"})
+	source.Annotations = map[string]string{AnnotationAcknowledgeIdentityChange: "namespace=other"}
+	assert.Assert(t, len(source.validateIdentityChanges(oldSource)) == 0)
+
+	oldSink := &Sink{Spec: SinkSpec{Name: "sink", Tenant: "public", Namespace: "default"}}
+	sink := oldSink.DeepCopy()
+	sink.Spec.Namespace = "other"
+	assert.DeepEqual(t, fieldErrorPaths(sink.validateIdentityChanges(oldSink)),
+		[]string{"spec.namespace", "spec.subscriptionName"})
+	sink.Annotations = map[string]string{
+		AnnotationAcknowledgeIdentityChange: "namespace=other,subscriptionName=public/other/sink"}
+	assert.Assert(t, len(sink.validateIdentityChanges(oldSink)) == 0)
+}
+
+func TestFunctionValidateUpdate(t *testing.T) {
+	oldFunction := makeTestFunction("fn")
+	assert.NilError(t, oldFunction.ValidateCreate())
+
+	function := oldFunction.DeepCopy()
+	function.Spec.Namespace = "other"
+	err := function.ValidateUpdate(oldFunction)
+	assert.Assert(t, apierrors.IsInvalid(err), err)
+	assert.ErrorContains(t, err, `add "namespace=other" to the annotation`)
+
+	function.Annotations = map[string]string{
+		AnnotationAcknowledgeIdentityChange: "namespace=other,subscriptionName=public/other/fn"}
+	assert.NilError(t, function.ValidateUpdate(oldFunction))
+
+	// the acknowledgement of the previous migration does not allow the next one
+	next := function.DeepCopy()
+	next.Spec.Namespace = "another"
+	assert.Assert(t, apierrors.IsInvalid(next.ValidateUpdate(function)))
+
+	// the finalizers can be removed while the function is being deleted
+	deletionTimestamp := metav1.Now()
+	next.DeletionTimestamp = &deletionTimestamp
+	assert.NilError(t, next.ValidateUpdate(function))
+}
+
+func fieldErrorPaths(errs field.ErrorList) []string {
+	var paths []string
+	for _, err := range errs {
+		paths = append(paths, err.Field)
+	}
+	return paths
+}
+
+func TestValidatePackageChecksum(t *testing.T) {
+	path := field.NewPath("spec").Child("java", "checksum")
+	digest := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	testCases := []struct {
+		name            string
+		checksum        string
+		packageLocation string
+		valid           bool
+	}{
+		{name: "no checksum", valid: true},
+		{name: "valid", checksum: "sha256:" + digest, packageLocation: "function://public/default/fn@1.0", valid: true},
+		{name: "other algorithm", checksum: "md5:" + digest[:32], packageLocation: "function://public/default/fn@1.0"},
+		{name: "uppercase digest", checksum: "sha256:" + strings.ToUpper(digest),
+			packageLocation: "function://public/default/fn@1.0"},
+		{name: "short digest", checksum: "sha256:" + digest[:63], packageLocation: "function://public/default/fn@1.0"},
+		{name: "shell injection", checksum: "sha256:" + digest + "\"; rm -rf /; echo \"",
+			packageLocation: "function://public/default/fn@1.0"},
+		{name: "no package location", checksum: "sha256:" + digest},
+		{name: "file package location", checksum: "sha256:" + digest, packageLocation: "file:///pulsar/fn.jar"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validatePackageChecksum(path, tc.checksum, tc.packageLocation)
+			if tc.valid {
+				assert.Assert(t, err == nil, err)
+			} else {
+				assert.Assert(t, err != nil)
+				assert.Equal(t, err.Field, "spec.java.checksum")
+			}
+		})
+	}
+}
+
+func TestValidateWindowConfigs(t *testing.T) {
+	length := int32(10)
+	extractor := "org.example.TimestampExtractor"
+	python := Runtime{Python: &PythonRuntime{Py: "fn.py"}}
+	java := Runtime{Java: &JavaRuntime{Jar: "fn.jar"}}
+
+	assert.Equal(t, len(validateWindowConfigs(&WindowConfig{WindowLengthCount: &length}, python)), 0)
+	assert.Equal(t, len(validateWindowConfigs(&WindowConfig{WindowLengthCount: &length,
+		TimestampExtractorClassName: &extractor}, java)), 0)
+
+	// the Python runtime windows the messages by processing time
+	errs := validateWindowConfigs(&WindowConfig{WindowLengthCount: &length,
+		TimestampExtractorClassName: &extractor}, python)
+	assert.Equal(t, len(errs), 1)
+	assert.Equal(t, errs[0].Field, "spec.windowConfig.timestampExtractorClassName")
+}
@@ -0,0 +1,38 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// RejectionRecorder records the fields rejected by the validating webhooks
+type RejectionRecorder interface {
+	RecordRejections(kind string, errs field.ErrorList)
+}
+
+// WebhookRejections records the fields rejected by the validating webhooks, it is set by the manager
+// before the webhooks are registered, nothing is recorded when it is nil
+var WebhookRejections RejectionRecorder
+
+func recordRejections(kind string, errs field.ErrorList) {
+	if WebhookRejections == nil || len(errs) == 0 {
+		return
+	}
+	WebhookRejections.RecordRejections(kind, errs)
+}
@@ -0,0 +1,57 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+type testRejectionRecorder struct {
+	kinds  []string
+	fields []string
+}
+
+func (r *testRejectionRecorder) RecordRejections(kind string, errs field.ErrorList) {
+	for _, err := range errs {
+		r.kinds = append(r.kinds, kind)
+		r.fields = append(r.fields, err.Field)
+	}
+}
+
+func TestRecordRejections(t *testing.T) {
+	defer func() { WebhookRejections = nil }()
+
+	// nothing is recorded without a recorder
+	mesh := makeTestFunctionMesh("mesh", makeTestFunctionSpec(), makeTestFunctionSpec())
+	mesh.Default()
+	assert.Assert(t, mesh.ValidateCreate() != nil)
+
+	recorder := &testRejectionRecorder{}
+	WebhookRejections = recorder
+	valid := makeTestFunctionMesh("mesh", makeTestFunctionSpec())
+	valid.Default()
+	assert.NilError(t, valid.ValidateCreate())
+	assert.Equal(t, len(recorder.kinds), 0)
+
+	assert.Assert(t, mesh.ValidateCreate() != nil)
+	assert.DeepEqual(t, recorder.kinds, []string{"FunctionMesh"})
+	assert.DeepEqual(t, recorder.fields, []string{"spec.functions[1].name"})
+}
@@ -123,6 +123,31 @@ func (in *CryptoSecret) DeepCopy() *CryptoSecret {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomRuntime) DeepCopyInto(out *CustomRuntime) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomRuntime.
+func (in *CustomRuntime) DeepCopy() *CustomRuntime {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomRuntime)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Function) DeepCopyInto(out *Function) {
 	*out = *in
@@ -265,6 +290,16 @@ func (in *FunctionMeshSpec) DeepCopyInto(out *FunctionMeshSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(MeshRolloutStrategy)
+		**out = **in
+	}
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = new(MeshComponentDefaults)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionMeshSpec.
@@ -363,6 +398,21 @@ func (in *FunctionSpec) DeepCopyInto(out *FunctionSpec) {
 		**out = **in
 	}
 	in.Pod.DeepCopyInto(&out.Pod)
+	if in.WindowConfig != nil {
+		in, out := &in.WindowConfig, &out.WindowConfig
+		*out = new(WindowConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IdlePolicy != nil {
+		in, out := &in.IdlePolicy, &out.IdlePolicy
+		*out = new(IdlePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tuning != nil {
+		in, out := &in.Tuning, &out.Tuning
+		*out = new(RuntimeTuning)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Messaging.DeepCopyInto(&out.Messaging)
 	in.Runtime.DeepCopyInto(&out.Runtime)
 	if in.StateConfig != nil {
@@ -424,6 +474,26 @@ func (in *GoRuntime) DeepCopy() *GoRuntime {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdlePolicy) DeepCopyInto(out *IdlePolicy) {
+	*out = *in
+	if in.CheckIntervalSeconds != nil {
+		in, out := &in.CheckIntervalSeconds, &out.CheckIntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdlePolicy.
+func (in *IdlePolicy) DeepCopy() *IdlePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(IdlePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InputConf) DeepCopyInto(out *InputConf) {
 	*out = *in
@@ -465,6 +535,31 @@ func (in *InputConf) DeepCopy() *InputConf {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JVMTuning) DeepCopyInto(out *JVMTuning) {
+	*out = *in
+	if in.HeapPercentage != nil {
+		in, out := &in.HeapPercentage, &out.HeapPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JVMTuning.
+func (in *JVMTuning) DeepCopy() *JVMTuning {
+	if in == nil {
+		return nil
+	}
+	out := new(JVMTuning)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JavaRuntime) DeepCopyInto(out *JavaRuntime) {
 	*out = *in
@@ -500,6 +595,47 @@ func (in *LogConfig) DeepCopy() *LogConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeshComponentDefaults) DeepCopyInto(out *MeshComponentDefaults) {
+	*out = *in
+	in.Messaging.DeepCopyInto(&out.Messaging)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = new(PodPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshComponentDefaults.
+func (in *MeshComponentDefaults) DeepCopy() *MeshComponentDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(MeshComponentDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeshRolloutStrategy) DeepCopyInto(out *MeshRolloutStrategy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeshRolloutStrategy.
+func (in *MeshRolloutStrategy) DeepCopy() *MeshRolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(MeshRolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Messaging) DeepCopyInto(out *Messaging) {
 	*out = *in
@@ -520,6 +656,40 @@ func (in *Messaging) DeepCopy() *Messaging {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitorPolicy) DeepCopyInto(out *MonitorPolicy) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Relabelings != nil {
+		in, out := &in.Relabelings, &out.Relabelings
+		*out = make([]RelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorPolicy.
+func (in *MonitorPolicy) DeepCopy() *MonitorPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitorPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OutputConf) DeepCopyInto(out *OutputConf) {
 	*out = *in
@@ -638,6 +808,11 @@ func (in *PodPolicy) DeepCopyInto(out *PodPolicy) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Monitor != nil {
+		in, out := &in.Monitor, &out.Monitor
+		*out = new(MonitorPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodPolicy.
@@ -670,6 +845,21 @@ func (in *ProducerConfig) DeepCopy() *ProducerConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PulsarConnectionRef) DeepCopyInto(out *PulsarConnectionRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulsarConnectionRef.
+func (in *PulsarConnectionRef) DeepCopy() *PulsarConnectionRef {
+	if in == nil {
+		return nil
+	}
+	out := new(PulsarConnectionRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PulsarMessaging) DeepCopyInto(out *PulsarMessaging) {
 	*out = *in
@@ -678,6 +868,11 @@ func (in *PulsarMessaging) DeepCopyInto(out *PulsarMessaging) {
 		*out = new(PulsarTLSConfig)
 		**out = **in
 	}
+	if in.ConnectionRef != nil {
+		in, out := &in.ConnectionRef, &out.ConnectionRef
+		*out = new(PulsarConnectionRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulsarMessaging.
@@ -745,6 +940,31 @@ func (in *PulsarTLSConfig) DeepCopy() *PulsarTLSConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PythonDependencies) DeepCopyInto(out *PythonDependencies) {
+	*out = *in
+	if in.Requirements != nil {
+		in, out := &in.Requirements, &out.Requirements
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequirementsConfigMap != nil {
+		in, out := &in.RequirementsConfigMap, &out.RequirementsConfigMap
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PythonDependencies.
+func (in *PythonDependencies) DeepCopy() *PythonDependencies {
+	if in == nil {
+		return nil
+	}
+	out := new(PythonDependencies)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PythonRuntime) DeepCopyInto(out *PythonRuntime) {
 	*out = *in
@@ -753,6 +973,11 @@ func (in *PythonRuntime) DeepCopyInto(out *PythonRuntime) {
 		*out = new(RuntimeLogConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = new(PythonDependencies)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PythonRuntime.
@@ -765,6 +990,46 @@ func (in *PythonRuntime) DeepCopy() *PythonRuntime {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PythonTuning) DeepCopyInto(out *PythonTuning) {
+	*out = *in
+	if in.InterpreterFlags != nil {
+		in, out := &in.InterpreterFlags, &out.InterpreterFlags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PythonTuning.
+func (in *PythonTuning) DeepCopy() *PythonTuning {
+	if in == nil {
+		return nil
+	}
+	out := new(PythonTuning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RelabelConfig) DeepCopyInto(out *RelabelConfig) {
+	*out = *in
+	if in.SourceLabels != nil {
+		in, out := &in.SourceLabels, &out.SourceLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RelabelConfig.
+func (in *RelabelConfig) DeepCopy() *RelabelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RelabelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceCondition) DeepCopyInto(out *ResourceCondition) {
 	*out = *in
@@ -798,6 +1063,11 @@ func (in *Runtime) DeepCopyInto(out *Runtime) {
 		*out = new(GoRuntime)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Custom != nil {
+		in, out := &in.Custom, &out.Custom
+		*out = new(CustomRuntime)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Runtime.
@@ -830,6 +1100,41 @@ func (in *RuntimeLogConfig) DeepCopy() *RuntimeLogConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeTuning) DeepCopyInto(out *RuntimeTuning) {
+	*out = *in
+	if in.MaxBufferedTuples != nil {
+		in, out := &in.MaxBufferedTuples, &out.MaxBufferedTuples
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ExpectedHealthCheckIntervalSeconds != nil {
+		in, out := &in.ExpectedHealthCheckIntervalSeconds, &out.ExpectedHealthCheckIntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.JVM != nil {
+		in, out := &in.JVM, &out.JVM
+		*out = new(JVMTuning)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Python != nil {
+		in, out := &in.Python, &out.Python
+		*out = new(PythonTuning)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeTuning.
+func (in *RuntimeTuning) DeepCopy() *RuntimeTuning {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeTuning)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretRef) DeepCopyInto(out *SecretRef) {
 	*out = *in
@@ -943,6 +1248,16 @@ func (in *SinkSpec) DeepCopyInto(out *SinkSpec) {
 		**out = **in
 	}
 	in.Pod.DeepCopyInto(&out.Pod)
+	if in.IdlePolicy != nil {
+		in, out := &in.IdlePolicy, &out.IdlePolicy
+		*out = new(IdlePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tuning != nil {
+		in, out := &in.Tuning, &out.Tuning
+		*out = new(RuntimeTuning)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Messaging.DeepCopyInto(&out.Messaging)
 	in.Runtime.DeepCopyInto(&out.Runtime)
 }
@@ -1077,6 +1392,11 @@ func (in *SourceSpec) DeepCopyInto(out *SourceSpec) {
 		**out = **in
 	}
 	in.Pod.DeepCopyInto(&out.Pod)
+	if in.Tuning != nil {
+		in, out := &in.Tuning, &out.Tuning
+		*out = new(RuntimeTuning)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Messaging.DeepCopyInto(&out.Messaging)
 	in.Runtime.DeepCopyInto(&out.Runtime)
 }
@@ -1147,3 +1467,68 @@ func (in *TLSConfig) DeepCopy() *TLSConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopicSchemaMismatch) DeepCopyInto(out *TopicSchemaMismatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopicSchemaMismatch.
+func (in *TopicSchemaMismatch) DeepCopy() *TopicSchemaMismatch {
+	if in == nil {
+		return nil
+	}
+	out := new(TopicSchemaMismatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WindowConfig) DeepCopyInto(out *WindowConfig) {
+	*out = *in
+	if in.WindowLengthCount != nil {
+		in, out := &in.WindowLengthCount, &out.WindowLengthCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WindowLengthDurationMs != nil {
+		in, out := &in.WindowLengthDurationMs, &out.WindowLengthDurationMs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SlidingIntervalCount != nil {
+		in, out := &in.SlidingIntervalCount, &out.SlidingIntervalCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SlidingIntervalDurationMs != nil {
+		in, out := &in.SlidingIntervalDurationMs, &out.SlidingIntervalDurationMs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxLagMs != nil {
+		in, out := &in.MaxLagMs, &out.MaxLagMs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.WatermarkEmitIntervalMs != nil {
+		in, out := &in.WatermarkEmitIntervalMs, &out.WatermarkEmitIntervalMs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TimestampExtractorClassName != nil {
+		in, out := &in.TimestampExtractorClassName, &out.TimestampExtractorClassName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WindowConfig.
+func (in *WindowConfig) DeepCopy() *WindowConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WindowConfig)
+	in.DeepCopyInto(out)
+	return out
+}
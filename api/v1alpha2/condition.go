@@ -0,0 +1,235 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition contains details for one aspect of the current state of a resource.
+// It is a temporary stand-in for the metav1.Condition type, which is only available from k8s.io/apimachinery v0.19,
+// it mirrors its fields and validation and is to be replaced by it once the dependency is upgraded.
+type Condition struct {
+	// type of condition in CamelCase.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=316
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])$`
+	Type string `json:"type"`
+	// status of the condition, one of True, False, Unknown.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	Status metav1.ConditionStatus `json:"status"`
+	// observedGeneration represents the .metadata.generation that the condition was set based upon.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// lastTransitionTime is the last time the condition transitioned from one status to another.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=date-time
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+	// reason contains a programmatic identifier indicating the reason for the condition's last transition.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=1024
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern=`^[A-Za-z]([A-Za-z0-9_,:]*[A-Za-z0-9_])?$`
+	Reason string `json:"reason"`
+	// message is a human readable message indicating details about the transition.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=32768
+	Message string `json:"message,omitempty"`
+}
+
+// Aggregated condition types reported by every resource
+const (
+	// Ready indicates that all the subcomponents of the resource are ready
+	Ready string = "Ready"
+	// Progressing indicates that the controller is still driving the resource to its desired state
+	Progressing string = "Progressing"
+	// Degraded indicates that the last reconciliation of the resource failed
+	Degraded string = "Degraded"
+)
+
+// Component condition types
+const (
+	StatefulSetReady string = "StatefulSetReady"
+	ServiceReady     string = "ServiceReady"
+	HPAReady         string = "HPAReady"
+	MonitorReady     string = "MonitorReady"
+	// PackageReady indicates that the package of the component is downloaded and verified by the instances,
+	// and that the dependencies of the package are installed
+	PackageReady string = "PackageReady"
+
+	FunctionReady string = "FunctionReady"
+	SourceReady   string = "SourceReady"
+	SinkReady     string = "SinkReady"
+	Orphaned      string = "Orphaned"
+)
+
+// State condition types describe the resource itself rather than one of its subcomponents,
+// so they are not taken into account by the aggregated conditions
+const (
+	// ScaledToZero indicates that the resource is scaled to zero by its idle policy
+	ScaledToZero string = "ScaledToZero"
+	// Suspended indicates that the resource is suspended by spec.suspend
+	Suspended string = "Suspended"
+	// Reachable indicates that the Pulsar cluster of a connection is reachable from the operator
+	Reachable string = "Reachable"
+)
+
+// Condition reasons, the pending ones also tell the controller what to do next
+const (
+	ReasonCreating        string = "Creating"
+	ReasonUpdating        string = "Updating"
+	ReasonDeleting        string = "Deleting"
+	ReasonWaiting         string = "Waiting"
+	ReasonReconciled      string = "Reconciled"
+	ReasonReconcileFailed string = "ReconcileFailed"
+
+	ReasonIdle      string = "Idle"
+	ReasonActive    string = "Active"
+	ReasonSuspended string = "Suspended"
+
+	ReasonUnreachable string = "Unreachable"
+
+	ReasonDownloadFailed          string = "DownloadFailed"
+	ReasonChecksumMismatch        string = "ChecksumMismatch"
+	ReasonDependencyInstallFailed string = "DependencyInstallFailed"
+)
+
+// CreateCondition returns a condition observed at the given generation
+func CreateCondition(condType string, status metav1.ConditionStatus, reason, message string,
+	generation int64) Condition {
+	return Condition{
+		Type:               condType,
+		Status:             status,
+		ObservedGeneration: generation,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+}
+
+// SetCondition adds the condition to the list or updates the existing one with the same type.
+// LastTransitionTime is only changed when the status of the condition changes.
+func SetCondition(conditions *[]Condition, newCondition Condition) {
+	if conditions == nil {
+		return
+	}
+	existingCondition := FindCondition(*conditions, newCondition.Type)
+	if existingCondition == nil {
+		if newCondition.LastTransitionTime.IsZero() {
+			newCondition.LastTransitionTime = metav1.Now()
+		}
+		*conditions = append(*conditions, newCondition)
+		return
+	}
+
+	if existingCondition.Status != newCondition.Status {
+		existingCondition.Status = newCondition.Status
+		if !newCondition.LastTransitionTime.IsZero() {
+			existingCondition.LastTransitionTime = newCondition.LastTransitionTime
+		} else {
+			existingCondition.LastTransitionTime = metav1.Now()
+		}
+	}
+
+	existingCondition.Reason = newCondition.Reason
+	existingCondition.Message = newCondition.Message
+	existingCondition.ObservedGeneration = newCondition.ObservedGeneration
+}
+
+// RemoveCondition removes the condition with the given type from the list
+func RemoveCondition(conditions *[]Condition, conditionType string) {
+	if conditions == nil || len(*conditions) == 0 {
+		return
+	}
+	newConditions := make([]Condition, 0, len(*conditions)-1)
+	for _, condition := range *conditions {
+		if condition.Type != conditionType {
+			newConditions = append(newConditions, condition)
+		}
+	}
+	*conditions = newConditions
+}
+
+// FindCondition returns the condition with the given type, or nil if it is not found
+func FindCondition(conditions []Condition, conditionType string) *Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// IsConditionTrue returns true if the condition with the given type is present and true
+func IsConditionTrue(conditions []Condition, conditionType string) bool {
+	condition := FindCondition(conditions, conditionType)
+	return condition != nil && condition.Status == metav1.ConditionTrue
+}
+
+// IsAggregatedCondition returns true if the condition type is one of Ready, Progressing or Degraded
+func IsAggregatedCondition(conditionType string) bool {
+	return conditionType == Ready || conditionType == Progressing || conditionType == Degraded
+}
+
+// IsStateCondition returns true if the condition type describes the state of the resource itself
+func IsStateCondition(conditionType string) bool {
+	return conditionType == ScaledToZero || conditionType == Suspended
+}
+
+// ComponentConditions returns the conditions reported by the subcomponents of a resource
+func ComponentConditions(conditions []Condition) []Condition {
+	components := make([]Condition, 0, len(conditions))
+	for _, condition := range conditions {
+		if !IsAggregatedCondition(condition.Type) && !IsStateCondition(condition.Type) {
+			components = append(components, condition)
+		}
+	}
+	return components
+}
+
+// SetAggregatedConditions computes the Ready and Progressing conditions from the given
+// component conditions, and initializes the Degraded condition if it is not reported yet.
+func SetAggregatedConditions(conditions *[]Condition, components []Condition, generation int64) {
+	ready := CreateCondition(Ready, metav1.ConditionTrue, ReasonReconciled, "", generation)
+	progressing := CreateCondition(Progressing, metav1.ConditionFalse, ReasonReconciled, "", generation)
+
+	for _, component := range components {
+		if component.Status != metav1.ConditionTrue && ready.Status == metav1.ConditionTrue {
+			ready.Status = metav1.ConditionFalse
+			ready.Reason = component.Reason
+			ready.Message = component.Type + " is not true"
+		}
+		switch component.Reason {
+		case ReasonCreating, ReasonUpdating, ReasonDeleting, ReasonWaiting:
+			if progressing.Status != metav1.ConditionTrue {
+				progressing.Status = metav1.ConditionTrue
+				progressing.Reason = component.Reason
+				progressing.Message = component.Type + " is " + component.Reason
+			}
+		}
+	}
+
+	SetCondition(conditions, ready)
+	SetCondition(conditions, progressing)
+	if FindCondition(*conditions, Degraded) == nil {
+		SetCondition(conditions, CreateCondition(Degraded, metav1.ConditionFalse, ReasonReconciled, "", generation))
+	}
+}
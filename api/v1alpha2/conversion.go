@@ -0,0 +1,375 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha2
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// v1alpha1 is the conversion hub, the status conditions are converted between the
+// component keyed map of v1alpha1 and the condition list of v1alpha2.
+// The v1alpha2 status is kept in the AnnotationStatus annotation of the hub, so that the fields v1alpha1 has
+// no representation for survive a round trip through v1alpha1: the observed generation, the reasons, messages
+// and transition times of the conditions, the conditions other than the subcomponent ones and the idle status.
+// The fields which grow with the replicas or the components and are observed again by the controllers are
+// not kept: the instance statuses, and the topic graph, topology and rollout of the meshes. The annotation is
+// dropped when the status still exceeds maxStoredStatusSize, and it is removed from the converted resources.
+// The conditions, replicas and selector of the hub take precedence over the stored ones, the conditions which are
+// only in the hub get the creation time of the resource as their transition time, so that the conversion is stable.
+
+// AnnotationStatus keeps the v1alpha2 status of a resource converted to v1alpha1
+const AnnotationStatus = "compute.functionmesh.io/v1alpha2-status"
+
+// maxStoredStatusSize is the size limit of the AnnotationStatus annotation
+const maxStoredStatusSize = 32 * 1024
+
+var (
+	componentConditionTypes = map[v1alpha1.Component]string{
+		v1alpha1.StatefulSet: StatefulSetReady,
+		v1alpha1.Service:     ServiceReady,
+		v1alpha1.HPA:         HPAReady,
+	}
+
+	actionReasons = map[v1alpha1.ReconcileAction]string{
+		v1alpha1.Create:   ReasonCreating,
+		v1alpha1.Update:   ReasonUpdating,
+		v1alpha1.Delete:   ReasonDeleting,
+		v1alpha1.Wait:     ReasonWaiting,
+		v1alpha1.NoAction: ReasonReconciled,
+	}
+)
+
+// ConvertTo converts this Function to the Hub version (v1alpha1).
+func (src *Function) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Function)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	stored := src.Status
+	stored.Instances = nil
+	if err := storeStatus(&dst.ObjectMeta, &stored); err != nil {
+		return err
+	}
+	dst.Spec = src.Spec
+	dst.Status.Conditions = convertComponentConditionsToHub(src.Status.Conditions)
+	dst.Status.Replicas = src.Status.Replicas
+	dst.Status.Selector = src.Status.Selector
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this version.
+func (dst *Function) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.Function)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	stored := FunctionStatus{}
+	restoreStatus(&dst.ObjectMeta, &stored)
+	dst.Spec = src.Spec
+	dst.Status = stored
+	dst.Status.Conditions = mergeStoredConditions(convertComponentConditionsFromHub(src.Status.Conditions,
+		src.CreationTimestamp), stored.Conditions)
+	dst.Status.Replicas = src.Status.Replicas
+	dst.Status.Selector = src.Status.Selector
+	return nil
+}
+
+// ConvertTo converts this Source to the Hub version (v1alpha1).
+func (src *Source) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Source)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	stored := src.Status
+	stored.Instances = nil
+	if err := storeStatus(&dst.ObjectMeta, &stored); err != nil {
+		return err
+	}
+	dst.Spec = src.Spec
+	dst.Status.Conditions = convertComponentConditionsToHub(src.Status.Conditions)
+	dst.Status.Replicas = src.Status.Replicas
+	dst.Status.Selector = src.Status.Selector
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this version.
+func (dst *Source) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.Source)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	stored := SourceStatus{}
+	restoreStatus(&dst.ObjectMeta, &stored)
+	dst.Spec = src.Spec
+	dst.Status = stored
+	dst.Status.Conditions = mergeStoredConditions(convertComponentConditionsFromHub(src.Status.Conditions,
+		src.CreationTimestamp), stored.Conditions)
+	dst.Status.Replicas = src.Status.Replicas
+	dst.Status.Selector = src.Status.Selector
+	return nil
+}
+
+// ConvertTo converts this Sink to the Hub version (v1alpha1).
+func (src *Sink) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Sink)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	stored := src.Status
+	stored.Instances = nil
+	if err := storeStatus(&dst.ObjectMeta, &stored); err != nil {
+		return err
+	}
+	dst.Spec = src.Spec
+	dst.Status.Conditions = convertComponentConditionsToHub(src.Status.Conditions)
+	dst.Status.Replicas = src.Status.Replicas
+	dst.Status.Selector = src.Status.Selector
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this version.
+func (dst *Sink) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.Sink)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	stored := SinkStatus{}
+	restoreStatus(&dst.ObjectMeta, &stored)
+	dst.Spec = src.Spec
+	dst.Status = stored
+	dst.Status.Conditions = mergeStoredConditions(convertComponentConditionsFromHub(src.Status.Conditions,
+		src.CreationTimestamp), stored.Conditions)
+	dst.Status.Replicas = src.Status.Replicas
+	dst.Status.Selector = src.Status.Selector
+	return nil
+}
+
+// ConvertTo converts this FunctionMesh to the Hub version (v1alpha1).
+func (src *FunctionMesh) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.FunctionMesh)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	stored := src.Status
+	stored.TopicGraph = nil
+	stored.Topology = nil
+	stored.Rollout = nil
+	if err := storeStatus(&dst.ObjectMeta, &stored); err != nil {
+		return err
+	}
+	dst.Spec = src.Spec
+	dst.Status.FunctionConditions = convertMeshConditionsToHub(src.Status.FunctionConditions)
+	dst.Status.SourceConditions = convertMeshConditionsToHub(src.Status.SourceConditions)
+	dst.Status.SinkConditions = convertMeshConditionsToHub(src.Status.SinkConditions)
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this version.
+func (dst *FunctionMesh) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.FunctionMesh)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	stored := FunctionMeshStatus{}
+	restoreStatus(&dst.ObjectMeta, &stored)
+	dst.Spec = src.Spec
+	dst.Status = stored
+	dst.Status.FunctionConditions = mergeStoredMeshConditions(
+		convertMeshConditionsFromHub(src.Status.FunctionConditions, src.CreationTimestamp), stored.FunctionConditions)
+	dst.Status.SourceConditions = mergeStoredMeshConditions(
+		convertMeshConditionsFromHub(src.Status.SourceConditions, src.CreationTimestamp), stored.SourceConditions)
+	dst.Status.SinkConditions = mergeStoredMeshConditions(
+		convertMeshConditionsFromHub(src.Status.SinkConditions, src.CreationTimestamp), stored.SinkConditions)
+	dst.Status.Conditions = nil
+	if len(src.Status.FunctionConditions)+len(src.Status.SourceConditions)+len(src.Status.SinkConditions) > 0 {
+		var components []Condition
+		for _, conditions := range []map[string]Condition{
+			dst.Status.FunctionConditions, dst.Status.SourceConditions, dst.Status.SinkConditions} {
+			for _, condition := range conditions {
+				components = append(components, condition)
+			}
+		}
+		SetAggregatedConditions(&dst.Status.Conditions, components, 0)
+		setTransitionTime(dst.Status.Conditions, src.CreationTimestamp)
+		dst.Status.Conditions = mergeStoredConditions(dst.Status.Conditions, stored.Conditions)
+	}
+	return nil
+}
+
+// storeStatus keeps the v1alpha2 status in the annotations of the hub,
+// an empty status or a status exceeding maxStoredStatusSize is not kept
+func storeStatus(objectMeta *metav1.ObjectMeta, status interface{}) error {
+	if reflect.ValueOf(status).Elem().IsZero() {
+		delete(objectMeta.Annotations, AnnotationStatus)
+		return nil
+	}
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxStoredStatusSize {
+		delete(objectMeta.Annotations, AnnotationStatus)
+		return nil
+	}
+	if objectMeta.Annotations == nil {
+		objectMeta.Annotations = make(map[string]string)
+	}
+	objectMeta.Annotations[AnnotationStatus] = string(data)
+	return nil
+}
+
+// restoreStatus removes the v1alpha2 status kept in the annotations and decodes it into the status,
+// an annotation which cannot be decoded is dropped and the status is converted from the hub only
+func restoreStatus(objectMeta *metav1.ObjectMeta, status interface{}) {
+	data, ok := objectMeta.Annotations[AnnotationStatus]
+	if !ok {
+		return
+	}
+	delete(objectMeta.Annotations, AnnotationStatus)
+	if len(objectMeta.Annotations) == 0 {
+		objectMeta.Annotations = nil
+	}
+	if err := json.Unmarshal([]byte(data), status); err != nil {
+		value := reflect.ValueOf(status).Elem()
+		value.Set(reflect.Zero(value.Type()))
+	}
+}
+
+// mergeStoredConditions replaces the converted conditions by the stored ones of the same type and status,
+// so that their reasons, messages and transition times are kept unless the status changed in the hub.
+// The stored conditions the hub has no representation for are kept as they are.
+func mergeStoredConditions(conditions, stored []Condition) []Condition {
+	if len(stored) == 0 {
+		return conditions
+	}
+	merged := make([]Condition, 0, len(stored)+len(conditions))
+	for _, condition := range conditions {
+		if storedCondition := FindCondition(stored, condition.Type); storedCondition != nil &&
+			storedCondition.Status == condition.Status {
+			condition = *storedCondition
+		}
+		merged = append(merged, condition)
+	}
+	for _, storedCondition := range stored {
+		if _, ok := componentConditionType(storedCondition.Type); ok || IsAggregatedCondition(storedCondition.Type) {
+			continue
+		}
+		if FindCondition(merged, storedCondition.Type) == nil {
+			merged = append(merged, storedCondition)
+		}
+	}
+	return merged
+}
+
+// mergeStoredMeshConditions replaces the converted conditions of the mesh components by the stored ones
+// with the same status
+func mergeStoredMeshConditions(conditions, stored map[string]Condition) map[string]Condition {
+	for name, condition := range conditions {
+		if storedCondition, ok := stored[name]; ok && storedCondition.Status == condition.Status {
+			conditions[name] = storedCondition
+		}
+	}
+	return conditions
+}
+
+func componentConditionType(conditionType string) (v1alpha1.Component, bool) {
+	for component, componentType := range componentConditionTypes {
+		if componentType == conditionType {
+			return component, true
+		}
+	}
+	return "", false
+}
+
+func convertComponentConditionsToHub(conditions []Condition) map[v1alpha1.Component]v1alpha1.ResourceCondition {
+	hubConditions := make(map[v1alpha1.Component]v1alpha1.ResourceCondition)
+	for component, conditionType := range componentConditionTypes {
+		condition := FindCondition(conditions, conditionType)
+		if condition == nil {
+			continue
+		}
+		hubConditions[component] = convertConditionToHub(*condition)
+	}
+	return hubConditions
+}
+
+func convertComponentConditionsFromHub(hubConditions map[v1alpha1.Component]v1alpha1.ResourceCondition,
+	transitionTime metav1.Time) []Condition {
+	if len(hubConditions) == 0 {
+		return nil
+	}
+	var conditions []Condition
+	for _, component := range []v1alpha1.Component{v1alpha1.StatefulSet, v1alpha1.Service, v1alpha1.HPA} {
+		hubCondition, ok := hubConditions[component]
+		if !ok {
+			continue
+		}
+		condition := convertConditionFromHub(hubCondition, transitionTime)
+		if condition.Type == "" {
+			condition.Type = componentConditionTypes[component]
+		}
+		SetCondition(&conditions, condition)
+	}
+	SetAggregatedConditions(&conditions, ComponentConditions(conditions), 0)
+	setTransitionTime(conditions, transitionTime)
+	return conditions
+}
+
+// setTransitionTime sets the transition time of the conditions converted from the hub, which has none
+func setTransitionTime(conditions []Condition, transitionTime metav1.Time) {
+	for i := range conditions {
+		conditions[i].LastTransitionTime = transitionTime
+	}
+}
+
+func convertMeshConditionsToHub(conditions map[string]Condition) map[string]v1alpha1.ResourceCondition {
+	if conditions == nil {
+		return nil
+	}
+	hubConditions := make(map[string]v1alpha1.ResourceCondition, len(conditions))
+	for name, condition := range conditions {
+		hubConditions[name] = convertConditionToHub(condition)
+	}
+	return hubConditions
+}
+
+func convertMeshConditionsFromHub(hubConditions map[string]v1alpha1.ResourceCondition,
+	transitionTime metav1.Time) map[string]Condition {
+	if hubConditions == nil {
+		return nil
+	}
+	conditions := make(map[string]Condition, len(hubConditions))
+	for name, hubCondition := range hubConditions {
+		conditions[name] = convertConditionFromHub(hubCondition, transitionTime)
+	}
+	return conditions
+}
+
+func convertConditionToHub(condition Condition) v1alpha1.ResourceCondition {
+	action := v1alpha1.NoAction
+	for hubAction, reason := range actionReasons {
+		if reason == condition.Reason {
+			action = hubAction
+			break
+		}
+	}
+	return v1alpha1.CreateCondition(v1alpha1.ResourceConditionType(condition.Type), condition.Status, action)
+}
+
+func convertConditionFromHub(hubCondition v1alpha1.ResourceCondition, transitionTime metav1.Time) Condition {
+	status := hubCondition.Status
+	if status == "" {
+		status = metav1.ConditionUnknown
+	}
+	reason, ok := actionReasons[hubCondition.Action]
+	if !ok {
+		reason = ReasonReconciled
+	}
+	condition := CreateCondition(string(hubCondition.Condition), status, reason, "", 0)
+	condition.LastTransitionTime = transitionTime
+	return condition
+}
@@ -0,0 +1,211 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha2
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var transitionTime = metav1.NewTime(time.Unix(1600000000, 0))
+
+func makeTestConditions() []Condition {
+	return []Condition{
+		{Type: StatefulSetReady, Status: metav1.ConditionTrue, ObservedGeneration: 3,
+			LastTransitionTime: transitionTime, Reason: ReasonReconciled, Message: "statefulset is ready"},
+		{Type: ServiceReady, Status: metav1.ConditionFalse, ObservedGeneration: 3,
+			LastTransitionTime: transitionTime, Reason: ReasonCreating},
+		{Type: PackageReady, Status: metav1.ConditionFalse, ObservedGeneration: 3,
+			LastTransitionTime: transitionTime, Reason: ReasonChecksumMismatch, Message: "checksum mismatch"},
+		{Type: Ready, Status: metav1.ConditionFalse, ObservedGeneration: 3,
+			LastTransitionTime: transitionTime, Reason: ReasonCreating, Message: "service is not ready"},
+		{Type: Progressing, Status: metav1.ConditionTrue, ObservedGeneration: 3,
+			LastTransitionTime: transitionTime, Reason: ReasonCreating, Message: "creating the service"},
+		{Type: Degraded, Status: metav1.ConditionFalse, ObservedGeneration: 3,
+			LastTransitionTime: transitionTime, Reason: ReasonReconciled},
+		{Type: Suspended, Status: metav1.ConditionFalse, ObservedGeneration: 3,
+			LastTransitionTime: transitionTime, Reason: ReasonActive},
+	}
+}
+
+func TestFunctionConversionRoundTrip(t *testing.T) {
+	src := &Function{
+		ObjectMeta: metav1.ObjectMeta{Name: "fn", Annotations: map[string]string{"owner": "team"}},
+		Status: FunctionStatus{
+			Conditions:         makeTestConditions(),
+			ObservedGeneration: 3,
+			Replicas:           1,
+			Selector:           "app=fn",
+			Instances: &InstancesStatus{LastScrapeTime: transitionTime,
+				Items: []InstanceStatus{{InstanceID: 0, Running: true, NumReceived: 10}}},
+			Idle: &IdleStatus{LastCheckTime: transitionTime, LastActiveTime: transitionTime, Backlog: 5},
+		},
+	}
+
+	hub := &v1alpha1.Function{}
+	assert.NilError(t, src.ConvertTo(hub))
+	assert.Assert(t, hub.Annotations[AnnotationStatus] != "")
+	assert.Equal(t, len(src.Annotations), 1)
+	assert.Equal(t, hub.Status.Conditions[v1alpha1.StatefulSet].Status, metav1.ConditionTrue)
+	assert.Equal(t, hub.Status.Conditions[v1alpha1.Service].Action, v1alpha1.Create)
+
+	dst := &Function{}
+	assert.NilError(t, dst.ConvertFrom(hub))
+	assert.DeepEqual(t, dst.Annotations, map[string]string{"owner": "team"})
+	assert.Equal(t, len(dst.Status.Conditions), len(src.Status.Conditions))
+	for _, condition := range src.Status.Conditions {
+		converted := FindCondition(dst.Status.Conditions, condition.Type)
+		assert.Assert(t, converted != nil, condition.Type)
+		assert.DeepEqual(t, *converted, condition)
+	}
+	// the fields v1alpha1 has no representation for are kept, except the ones observed again by the controller
+	assert.Equal(t, dst.Status.ObservedGeneration, int64(3))
+	assert.Assert(t, dst.Status.Instances == nil)
+	assert.DeepEqual(t, dst.Status.Idle, src.Status.Idle)
+	assert.Equal(t, dst.Status.Replicas, int32(1))
+	assert.Equal(t, dst.Status.Selector, "app=fn")
+}
+
+func TestFunctionConversionReplicasChangedInHub(t *testing.T) {
+	src := &Function{Status: FunctionStatus{ObservedGeneration: 2, Replicas: 1, Selector: "app=fn"}}
+
+	hub := &v1alpha1.Function{}
+	assert.NilError(t, src.ConvertTo(hub))
+	hub.Status.Replicas = 3
+
+	dst := &Function{}
+	assert.NilError(t, dst.ConvertFrom(hub))
+	assert.Equal(t, dst.Status.Replicas, int32(3))
+	assert.Equal(t, dst.Status.ObservedGeneration, int64(2))
+}
+
+func TestSourceConversionStatusChangedInHub(t *testing.T) {
+	creationTime := metav1.NewTime(time.Unix(1500000000, 0))
+	src := &Source{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: creationTime},
+		Status:     SourceStatus{Conditions: makeTestConditions()},
+	}
+
+	hub := &v1alpha1.Source{}
+	assert.NilError(t, src.ConvertTo(hub))
+	hub.Status.Conditions[v1alpha1.Service] = v1alpha1.CreateCondition(v1alpha1.ServiceReady,
+		metav1.ConditionTrue, v1alpha1.NoAction)
+	delete(hub.Status.Conditions, v1alpha1.StatefulSet)
+
+	dst := &Source{}
+	assert.NilError(t, dst.ConvertFrom(hub))
+	assert.Assert(t, FindCondition(dst.Status.Conditions, StatefulSetReady) == nil)
+
+	service := FindCondition(dst.Status.Conditions, ServiceReady)
+	assert.Assert(t, service != nil)
+	assert.Equal(t, service.Status, metav1.ConditionTrue)
+	assert.Equal(t, service.Reason, ReasonReconciled)
+	assert.Equal(t, service.LastTransitionTime, creationTime)
+
+	// the conversion of the same hub is stable
+	again := &Source{}
+	assert.NilError(t, again.ConvertFrom(hub))
+	assert.DeepEqual(t, again.Status, dst.Status)
+
+	// the conditions v1alpha1 has no representation for are kept
+	assert.DeepEqual(t, *FindCondition(dst.Status.Conditions, PackageReady), makeTestConditions()[2])
+	assert.DeepEqual(t, *FindCondition(dst.Status.Conditions, Suspended), makeTestConditions()[6])
+}
+
+func TestSinkConversionFromHubWithoutAnnotation(t *testing.T) {
+	hub := &v1alpha1.Sink{
+		Status: v1alpha1.SinkStatus{Conditions: map[v1alpha1.Component]v1alpha1.ResourceCondition{
+			v1alpha1.StatefulSet: v1alpha1.CreateCondition(v1alpha1.StatefulSetReady, metav1.ConditionTrue,
+				v1alpha1.NoAction),
+		}},
+	}
+
+	dst := &Sink{}
+	assert.NilError(t, dst.ConvertFrom(hub))
+	statefulSet := FindCondition(dst.Status.Conditions, StatefulSetReady)
+	assert.Assert(t, statefulSet != nil)
+	assert.Equal(t, statefulSet.Reason, ReasonReconciled)
+	assert.Assert(t, IsConditionTrue(dst.Status.Conditions, Ready))
+	assert.Assert(t, dst.Annotations == nil)
+}
+
+func TestConversionIgnoresInvalidAnnotation(t *testing.T) {
+	hub := &v1alpha1.Function{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{AnnotationStatus: "{invalid"},
+	}}
+
+	dst := &Function{}
+	assert.NilError(t, dst.ConvertFrom(hub))
+	assert.Assert(t, dst.Status.Conditions == nil)
+	assert.Assert(t, dst.Annotations == nil)
+	assert.Equal(t, hub.Annotations[AnnotationStatus], "{invalid")
+}
+
+func TestFunctionMeshConversionRoundTrip(t *testing.T) {
+	functionCondition := Condition{Type: FunctionReady, Status: metav1.ConditionFalse, ObservedGeneration: 2,
+		LastTransitionTime: transitionTime, Reason: ReasonWaiting, Message: "waiting for the statefulset"}
+	sinkCondition := Condition{Type: SinkReady, Status: metav1.ConditionTrue, ObservedGeneration: 2,
+		LastTransitionTime: transitionTime, Reason: ReasonReconciled}
+	readyCondition := Condition{Type: Ready, Status: metav1.ConditionFalse, ObservedGeneration: 2,
+		LastTransitionTime: transitionTime, Reason: ReasonWaiting, Message: "function ex1 is not ready"}
+	src := &FunctionMesh{Status: FunctionMeshStatus{
+		Conditions:         []Condition{readyCondition},
+		ObservedGeneration: 2,
+		FunctionConditions: map[string]Condition{"ex1": functionCondition},
+		SinkConditions:     map[string]Condition{"sink1": sinkCondition},
+		TopicGraph: &TopicGraphStatus{ObservedGeneration: 2, LastCheckTime: transitionTime,
+			UnconsumedOutputs: []string{"out"}},
+		Topology: &TopologyStatus{Nodes: []TopologyNode{{ID: "function/ex1", Kind: "function", Name: "ex1",
+			Ready: metav1.ConditionFalse, Output: "out"}}, DOT: "digraph {}", Mermaid: "flowchart LR"},
+		Rollout: &MeshRolloutStatus{Strategy: v1alpha1.OrderedMeshRollout,
+			Stages: [][]string{{"sink/sink1"}, {"function/ex1"}}, CurrentStage: 1, Waiting: []string{"function/ex1"}},
+	}}
+
+	hub := &v1alpha1.FunctionMesh{}
+	assert.NilError(t, src.ConvertTo(hub))
+	assert.Equal(t, hub.Status.FunctionConditions["ex1"].Action, v1alpha1.Wait)
+
+	dst := &FunctionMesh{}
+	assert.NilError(t, dst.ConvertFrom(hub))
+	assert.DeepEqual(t, dst.Status.FunctionConditions, src.Status.FunctionConditions)
+	assert.DeepEqual(t, dst.Status.SinkConditions, src.Status.SinkConditions)
+	assert.DeepEqual(t, *FindCondition(dst.Status.Conditions, Ready), readyCondition)
+	assert.Equal(t, dst.Status.ObservedGeneration, int64(2))
+	// the topic graph, topology and rollout are observed again by the controller
+	assert.Assert(t, dst.Status.TopicGraph == nil)
+	assert.Assert(t, dst.Status.Topology == nil)
+	assert.Assert(t, dst.Status.Rollout == nil)
+	assert.Assert(t, dst.Annotations == nil)
+}
+
+func TestConversionDropsLargeStatus(t *testing.T) {
+	conditions := makeTestConditions()
+	conditions[0].Message = strings.Repeat("x", maxStoredStatusSize)
+	src := &Function{Status: FunctionStatus{Conditions: conditions, ObservedGeneration: 3}}
+
+	hub := &v1alpha1.Function{}
+	assert.NilError(t, src.ConvertTo(hub))
+	_, ok := hub.Annotations[AnnotationStatus]
+	assert.Assert(t, !ok)
+	assert.Equal(t, hub.Status.Conditions[v1alpha1.StatefulSet].Status, metav1.ConditionTrue)
+}
@@ -0,0 +1,124 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha2
+
+import (
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FunctionMeshStatus defines the observed state of FunctionMesh
+type FunctionMeshStatus struct {
+	// Conditions contains the aggregated Ready, Progressing and Degraded conditions of the mesh
+	// +listType=map
+	// +listMapKey=type
+	Conditions []Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the most recent generation observed by the controller
+	ObservedGeneration int64                `json:"observedGeneration,omitempty"`
+	SourceConditions   map[string]Condition `json:"sourceConditions,omitempty"`
+	SinkConditions     map[string]Condition `json:"sinkConditions,omitempty"`
+	FunctionConditions map[string]Condition `json:"functionConditions,omitempty"`
+	// TopicGraph reports the problems found in the topics connecting the components of the mesh
+	TopicGraph *TopicGraphStatus `json:"topicGraph,omitempty"`
+	// Topology is the graph of the components of the mesh connected by their topics
+	Topology *TopologyStatus `json:"topology,omitempty"`
+	// Rollout is the progress of the rollout of the components
+	Rollout *MeshRolloutStatus `json:"rollout,omitempty"`
+}
+
+// MeshRolloutStatus is the progress of the rollout of the components of a mesh
+type MeshRolloutStatus struct {
+	Strategy v1alpha1.MeshRolloutStrategyType `json:"strategy"`
+	// Stages are the IDs of the components in the order they are rolled out,
+	// the components of a stage are rolled out once the components of the previous stages are ready
+	Stages [][]string `json:"stages,omitempty"`
+	// CurrentStage is the index of the stage being rolled out, it equals the number of stages once completed
+	CurrentStage int32 `json:"currentStage"`
+	// Waiting are the IDs of the components of the current stage which are not ready
+	Waiting []string `json:"waiting,omitempty"`
+}
+
+// TopologyStatus is the topology of a mesh as an adjacency list, and rendered in the DOT and Mermaid languages
+type TopologyStatus struct {
+	// Nodes are the sources, functions and sinks of the mesh in the order of the mesh spec
+	Nodes []TopologyNode `json:"nodes,omitempty"`
+	// DOT is the topology in the DOT language of Graphviz
+	DOT string `json:"dot,omitempty"`
+	// Mermaid is the topology as a Mermaid flowchart
+	Mermaid string `json:"mermaid,omitempty"`
+}
+
+// TopologyNode is a component of the mesh with the components consuming its output topic
+type TopologyNode struct {
+	// ID is the kind and the name of the component, such as function/ex1
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Ready is the status of the readiness condition of the component in the mesh
+	Ready metav1.ConditionStatus `json:"ready"`
+	// Inputs are the input topics and topic patterns of the component
+	Inputs []string `json:"inputs,omitempty"`
+	// Output is the output topic of the component
+	Output string `json:"output,omitempty"`
+	// Successors are the IDs of the components consuming the output topic
+	Successors []string `json:"successors,omitempty"`
+}
+
+// TopicGraphStatus reports the problems found in the topic graph of a mesh
+type TopicGraphStatus struct {
+	// ObservedGeneration is the generation of the mesh the topic graph is built from
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastCheckTime is the last time the input topics produced outside of the mesh were checked in Pulsar
+	LastCheckTime metav1.Time `json:"lastCheckTime,omitempty"`
+	// MissingInputs are the input topics which are neither produced in the mesh nor exist in Pulsar
+	MissingInputs []string `json:"missingInputs,omitempty"`
+	// UnconsumedOutputs are the output topics which are not consumed in the mesh
+	UnconsumedOutputs []string `json:"unconsumedOutputs,omitempty"`
+	// Cycles are the components connected in a cycle through their topics
+	Cycles [][]string `json:"cycles,omitempty"`
+	// SchemaMismatches are the topics whose producer and consumer declare different schema types
+	SchemaMismatches []v1alpha1.TopicSchemaMismatch `json:"schemaMismatches,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// FunctionMesh is the Schema for the functionmeshes API
+type FunctionMesh struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   v1alpha1.FunctionMeshSpec `json:"spec,omitempty"`
+	Status FunctionMeshStatus        `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FunctionMeshList contains a list of FunctionMesh
+type FunctionMeshList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FunctionMesh `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FunctionMesh{}, &FunctionMeshList{})
+}
@@ -0,0 +1,32 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IdleStatus is the observed activity of a component with an idle policy
+type IdleStatus struct {
+	// LastCheckTime is the last time the backlog of the input subscription was checked
+	LastCheckTime metav1.Time `json:"lastCheckTime,omitempty"`
+	// LastActiveTime is the last time the input subscription was found with backlog
+	LastActiveTime metav1.Time `json:"lastActiveTime,omitempty"`
+	// Backlog is the backlog of the input subscription at the last check
+	Backlog int64 `json:"backlog"`
+}
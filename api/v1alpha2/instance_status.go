@@ -0,0 +1,62 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// InstancesStatus contains the runtime status reported by the instances through the instance control port
+type InstancesStatus struct {
+	// LastScrapeTime is the last time the status of the instances was scraped
+	LastScrapeTime metav1.Time      `json:"lastScrapeTime,omitempty"`
+	Items          []InstanceStatus `json:"items,omitempty"`
+}
+
+// InstanceStatus is the runtime status of a single instance
+type InstanceStatus struct {
+	InstanceID int32 `json:"instanceId"`
+	Running    bool  `json:"running"`
+	// Error is set when the status of the instance cannot be scraped
+	Error              string       `json:"error,omitempty"`
+	FailureException   string       `json:"failureException,omitempty"`
+	LastException      string       `json:"lastException,omitempty"`
+	LastExceptionTime  *metav1.Time `json:"lastExceptionTime,omitempty"`
+	LastInvocationTime *metav1.Time `json:"lastInvocationTime,omitempty"`
+
+	NumRestarts              int64 `json:"numRestarts,omitempty"`
+	NumReceived              int64 `json:"numReceived,omitempty"`
+	NumSuccessfullyProcessed int64 `json:"numSuccessfullyProcessed,omitempty"`
+	NumUserExceptions        int64 `json:"numUserExceptions,omitempty"`
+	NumSystemExceptions      int64 `json:"numSystemExceptions,omitempty"`
+	NumSourceExceptions      int64 `json:"numSourceExceptions,omitempty"`
+	NumSinkExceptions        int64 `json:"numSinkExceptions,omitempty"`
+
+	// Metrics are the metrics reported by the instance over the last minute
+	Metrics *InstanceMetrics `json:"metrics,omitempty"`
+}
+
+// InstanceMetrics are the metrics of a single instance over the last minute
+type InstanceMetrics struct {
+	ReceivedLastMinute              int64 `json:"receivedLastMinute,omitempty"`
+	ProcessedSuccessfullyLastMinute int64 `json:"processedSuccessfullyLastMinute,omitempty"`
+	UserExceptionsLastMinute        int64 `json:"userExceptionsLastMinute,omitempty"`
+	SystemExceptionsLastMinute      int64 `json:"systemExceptionsLastMinute,omitempty"`
+	// AvgProcessLatencyMs is the average process latency in milliseconds
+	AvgProcessLatencyMs string `json:"avgProcessLatencyMs,omitempty"`
+}
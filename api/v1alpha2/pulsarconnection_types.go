@@ -0,0 +1,122 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PulsarConnectionSpec bundles the service URLs, the authentication and the TLS settings of a Pulsar cluster
+type PulsarConnectionSpec struct {
+	// +kubebuilder:validation:Required
+	WebServiceURL string `json:"webServiceURL"`
+	// +kubebuilder:validation:Required
+	BrokerServiceURL string `json:"brokerServiceURL"`
+
+	// +optional
+	Auth *PulsarConnectionAuth `json:"auth,omitempty"`
+	// +optional
+	TLS *PulsarConnectionTLS `json:"tls,omitempty"`
+}
+
+type PulsarConnectionAuth struct {
+	// SecretRef refers to the secret with the clientAuthenticationPlugin and clientAuthenticationParameters keys,
+	// the namespace defaults to the namespace of a PulsarConnection and is required by a ClusterPulsarConnection
+	// +kubebuilder:validation:Required
+	SecretRef corev1.SecretReference `json:"secretRef"`
+}
+
+type PulsarConnectionTLS struct {
+	Enabled              bool `json:"enabled,omitempty"`
+	AllowInsecure        bool `json:"allowInsecure,omitempty"`
+	HostnameVerification bool `json:"hostnameVerification,omitempty"`
+	// CertSecretRef refers to the secret with the trust certs,
+	// the namespace defaults to the namespace of a PulsarConnection and is required by a ClusterPulsarConnection
+	// +optional
+	CertSecretRef *corev1.SecretReference `json:"certSecretRef,omitempty"`
+	// CertSecretKey is the key of the trust certs in the secret
+	// +optional
+	CertSecretKey string `json:"certSecretKey,omitempty"`
+}
+
+// PulsarConnectionStatus reports whether the Pulsar cluster is reachable from the operator
+type PulsarConnectionStatus struct {
+	// Conditions contains the Reachable condition of the connection
+	// +listType=map
+	// +listMapKey=type
+	Conditions []Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the most recent generation observed by the controller
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastCheckTime is the last time the connectivity was checked
+	LastCheckTime metav1.Time `json:"lastCheckTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Web Service URL",type="string",JSONPath=".spec.webServiceURL"
+// +kubebuilder:printcolumn:name="Reachable",type="string",JSONPath=".status.conditions[?(@.type==\"Reachable\")].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// PulsarConnection is the connection to a Pulsar cluster shared by the components in its namespace
+type PulsarConnection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PulsarConnectionSpec   `json:"spec,omitempty"`
+	Status PulsarConnectionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PulsarConnectionList contains a list of PulsarConnection
+type PulsarConnectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PulsarConnection `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Web Service URL",type="string",JSONPath=".spec.webServiceURL"
+// +kubebuilder:printcolumn:name="Reachable",type="string",JSONPath=".status.conditions[?(@.type==\"Reachable\")].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ClusterPulsarConnection is the connection to a Pulsar cluster shared by the components in all the namespaces
+type ClusterPulsarConnection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PulsarConnectionSpec   `json:"spec,omitempty"`
+	Status PulsarConnectionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterPulsarConnectionList contains a list of ClusterPulsarConnection
+type ClusterPulsarConnectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterPulsarConnection `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PulsarConnection{}, &PulsarConnectionList{})
+	SchemeBuilder.Register(&ClusterPulsarConnection{}, &ClusterPulsarConnectionList{})
+}
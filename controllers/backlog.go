@@ -0,0 +1,214 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"github.com/streamnative/pulsarctl/pkg/pulsar"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/common"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// makeFunctionSubscriptionName returns the subscription of the function on its input topics,
+// Pulsar uses the fully qualified function name by default
+func makeFunctionSubscriptionName(function *v1alpha2.Function) string {
+	if function.Spec.SubscriptionName != "" {
+		return function.Spec.SubscriptionName
+	}
+	return fmt.Sprintf("%s/%s/%s", function.Spec.Tenant, function.Spec.Namespace, function.Spec.Name)
+}
+
+func makeSinkSubscriptionName(sink *v1alpha2.Sink) string {
+	if sink.Spec.SubscriptionName != "" {
+		return sink.Spec.SubscriptionName
+	}
+	return fmt.Sprintf("%s/%s/%s", sink.Spec.Tenant, sink.Spec.Namespace, sink.Spec.Name)
+}
+
+// getInputBacklog returns the total backlog of the subscription on all the input topics
+func getInputBacklog(ctx context.Context, c client.Client, namespace string, input v1alpha1.InputConf,
+	messaging *v1alpha1.PulsarMessaging, subscription string) (int64, error) {
+	if messaging == nil {
+		return 0, errors.NewBadRequest("pulsar messaging config is not provided")
+	}
+	if input.TopicPattern != "" {
+		return 0, errors.NewBadRequest("backlog is not available for the topic pattern input")
+	}
+
+	admin, err := newPulsarAdmin(ctx, c, namespace, messaging)
+	if err != nil {
+		return 0, err
+	}
+
+	var backlog int64
+	for _, topic := range collectInputTopics(input) {
+		topicBacklog, err := getSubscriptionBacklog(admin, topic, subscription)
+		if err != nil {
+			return 0, err
+		}
+		backlog += topicBacklog
+	}
+	return backlog, nil
+}
+
+// newPulsarAdmin creates the admin client with the same Pulsar configs as the component's instances
+func newPulsarAdmin(ctx context.Context, c client.Client, namespace string,
+	messaging *v1alpha1.PulsarMessaging) (pulsar.Client, error) {
+	if messaging.ConnectionRef != nil {
+		connection, secretNamespace, err := getPulsarConnection(ctx, c, namespace, messaging.ConnectionRef)
+		if err != nil {
+			return nil, err
+		}
+		return newPulsarConnectionAdmin(ctx, c, secretNamespace, connection)
+	}
+
+	pulsarConfig := &corev1.ConfigMap{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: messaging.PulsarConfig}, pulsarConfig)
+	if err != nil {
+		return nil, err
+	}
+	config := &common.Config{
+		WebServiceURL:    pulsarConfig.Data["webServiceURL"],
+		PulsarAPIVersion: common.V2,
+	}
+
+	if messaging.AuthSecret != "" {
+		authSecret := &corev1.Secret{}
+		err = c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: messaging.AuthSecret}, authSecret)
+		if err != nil {
+			return nil, err
+		}
+		config.AuthPlugin = string(authSecret.Data["clientAuthenticationPlugin"])
+		config.AuthParams = string(authSecret.Data["clientAuthenticationParameters"])
+	}
+
+	if messaging.TLSConfig != nil && messaging.TLSConfig.IsEnabled() {
+		config.TLSAllowInsecureConnection = messaging.TLSConfig.AllowInsecure
+		config.TLSEnableHostnameVerification = messaging.TLSConfig.HostnameVerification
+		if messaging.TLSConfig.SecretName() != "" {
+			certPath, err := writeTrustCert(ctx, c, namespace, messaging.TLSConfig)
+			if err != nil {
+				return nil, err
+			}
+			config.TLSTrustCertsFilePath = certPath
+		}
+	} else if messaging.TLSSecret != "" {
+		// the trust cert path of the legacy TLS secret refers to the file system of the instances,
+		// so only the flags are reused here
+		tlsSecret := &corev1.Secret{}
+		err = c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: messaging.TLSSecret}, tlsSecret)
+		if err != nil {
+			return nil, err
+		}
+		config.TLSAllowInsecureConnection, _ = strconv.ParseBool(string(tlsSecret.Data["tls_allow_insecure"]))
+		config.TLSEnableHostnameVerification, _ =
+			strconv.ParseBool(string(tlsSecret.Data["hostname_verification_enabled"]))
+	}
+
+	return pulsar.New(config)
+}
+
+func writeTrustCert(ctx context.Context, c client.Client, namespace string,
+	tlsConfig *v1alpha1.PulsarTLSConfig) (string, error) {
+	return writeTrustCertFromSecret(ctx, c, namespace, tlsConfig.SecretName(), tlsConfig.SecretKey())
+}
+
+func writeTrustCertFromSecret(ctx context.Context, c client.Client, namespace, name, key string) (string, error) {
+	secret := &corev1.Secret{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret)
+	if err != nil {
+		return "", err
+	}
+	cert, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in secret %s/%s", key, namespace, name)
+	}
+	certPath := filepath.Join(os.TempDir(), "function-mesh-tls", namespace, name, key)
+	if err := os.MkdirAll(filepath.Dir(certPath), 0700); err != nil {
+		return "", err
+	}
+	if err := ioutil.WriteFile(certPath, cert, 0600); err != nil {
+		return "", err
+	}
+	return certPath, nil
+}
+
+// getSubscriptionBacklog returns the backlog of the subscription on the topic, partitioned topics are aggregated
+func getSubscriptionBacklog(admin pulsar.Client, topic, subscription string) (int64, error) {
+	topicName, err := utils.GetTopicName(topic)
+	if err != nil {
+		return 0, err
+	}
+	metadata, err := admin.Topics().GetMetadata(*topicName)
+	if err != nil {
+		return 0, err
+	}
+
+	var subscriptions map[string]utils.SubscriptionStats
+	if metadata.Partitions > 0 {
+		stats, err := admin.Topics().GetPartitionedStats(*topicName, false)
+		if err != nil {
+			return 0, err
+		}
+		subscriptions = stats.Subscriptions
+	} else {
+		stats, err := admin.Topics().GetStats(*topicName)
+		if err != nil {
+			return 0, err
+		}
+		subscriptions = stats.Subscriptions
+	}
+
+	// the subscription is created once the instances are started
+	return subscriptions[subscription].MsgBacklog, nil
+}
+
+func collectInputTopics(input v1alpha1.InputConf) []string {
+	topics := make(map[string]bool)
+	for _, topic := range input.Topics {
+		topics[topic] = true
+	}
+	for topic := range input.CustomSerdeSources {
+		topics[topic] = true
+	}
+	for topic := range input.CustomSchemaSources {
+		topics[topic] = true
+	}
+	for topic, conf := range input.SourceSpecs {
+		if !conf.IsRegexPattern {
+			topics[topic] = true
+		}
+	}
+	ret := make([]string, 0, len(topics))
+	for topic := range topics {
+		ret = append(ret, topic)
+	}
+	return ret
+}
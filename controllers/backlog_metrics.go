@@ -0,0 +1,273 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	externalMetricsGroupVersion = "external.metrics.k8s.io/v1beta1"
+	externalMetricsPath         = "/apis/" + externalMetricsGroupVersion
+)
+
+// ExternalMetricValue mirrors the type of the external metrics API,
+// see k8s.io/metrics/pkg/apis/external_metrics/v1beta1
+type ExternalMetricValue struct {
+	metav1.TypeMeta `json:",inline"`
+	MetricName      string            `json:"metricName"`
+	MetricLabels    map[string]string `json:"metricLabels"`
+	Timestamp       metav1.Time       `json:"timestamp"`
+	Value           resource.Quantity `json:"value"`
+}
+
+// ExternalMetricValueList mirrors the list type of the external metrics API
+type ExternalMetricValueList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ExternalMetricValue `json:"items"`
+}
+
+// BacklogMetricsServer serves the subscription backlog of functions and sinks through the external metrics API,
+// so that the HPAs generated from the backlog builtin rules can consume it.
+// The API is served on its own TLS listener which only accepts the requests proxied by the API aggregation
+// layer, the users of the requests are authorized with SubjectAccessReviews.
+type BacklogMetricsServer struct {
+	client.Client
+	// Reader reads the request header configuration of the aggregation layer in kube-system
+	Reader client.Reader
+	Log    logr.Logger
+	// BindAddress is the address the external metrics API binds to
+	BindAddress string
+	// CertDir contains the tls.crt and tls.key serving certificate, the webhook certificate is used by default
+	CertDir string
+
+	authenticator *requestHeaderAuthenticator
+}
+
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+func (s *BacklogMetricsServer) SetupWithManager(mgr ctrl.Manager) error {
+	if s.CertDir == "" {
+		s.CertDir = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
+	}
+	return mgr.Add(s)
+}
+
+// NeedLeaderElection returns false since the metrics are served by every replica of the controller manager
+func (s *BacklogMetricsServer) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves the external metrics API until the stop channel is closed
+func (s *BacklogMetricsServer) Start(stop <-chan struct{}) error {
+	authenticator, clientCAs, err := loadRequestHeaderAuthenticator(context.Background(), s.Reader)
+	if err != nil {
+		return err
+	}
+	s.authenticator = authenticator
+
+	server := &http.Server{
+		Addr:    s.BindAddress,
+		Handler: s,
+		TLSConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			ClientCAs:  clientCAs,
+			ClientAuth: tls.RequireAndVerifyClientCert,
+			// the certificate is loaded on each handshake to pick up the renewed certificates
+			GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+				certificate, err := tls.LoadX509KeyPair(filepath.Join(s.CertDir, "tls.crt"),
+					filepath.Join(s.CertDir, "tls.key"))
+				return &certificate, err
+			},
+		},
+	}
+	go func() {
+		<-stop
+		if err := server.Shutdown(context.Background()); err != nil {
+			s.Log.Error(err, "failed to shut down the external metrics server")
+		}
+	}()
+
+	s.Log.Info("serving the external metrics API", "address", s.BindAddress)
+	if err := server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func (s *BacklogMetricsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, err := s.authenticator.authenticate(r)
+	if err != nil {
+		s.writeError(w, errors.NewUnauthorized(err.Error()))
+		return
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	if path == externalMetricsPath {
+		if !s.authorize(r.Context(), w, user,
+			&authorizationv1.NonResourceAttributes{Path: r.URL.Path, Verb: "get"}, nil) {
+			return
+		}
+		s.writeResponse(w, http.StatusOK, &metav1.APIResourceList{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "APIResourceList",
+				APIVersion: "v1",
+			},
+			GroupVersion: externalMetricsGroupVersion,
+			APIResources: []metav1.APIResource{
+				{
+					Name:       spec.BacklogMetricName,
+					Namespaced: true,
+					Kind:       "ExternalMetricValueList",
+					Verbs:      []string{"get"},
+				},
+			},
+		})
+		return
+	}
+
+	// the metric is requested with /namespaces/{namespace}/{metric}
+	parts := strings.Split(strings.TrimPrefix(path, externalMetricsPath+"/"), "/")
+	if len(parts) != 3 || parts[0] != "namespaces" || parts[2] != spec.BacklogMetricName {
+		s.writeError(w, errors.NewNotFound(
+			schema.GroupResource{Group: "external.metrics.k8s.io", Resource: "metrics"}, path))
+		return
+	}
+	namespace := parts[1]
+	if !s.authorize(r.Context(), w, user, nil, &authorizationv1.ResourceAttributes{
+		Namespace: namespace,
+		Verb:      "get",
+		Group:     "external.metrics.k8s.io",
+		Version:   "v1beta1",
+		Resource:  spec.BacklogMetricName,
+	}) {
+		return
+	}
+
+	selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		s.writeError(w, errors.NewBadRequest(err.Error()))
+		return
+	}
+	component, ok := selector.RequiresExactMatch("component")
+	if !ok {
+		s.writeError(w, errors.NewBadRequest("label selector must match the component"))
+		return
+	}
+	name, ok := selector.RequiresExactMatch("name")
+	if !ok {
+		s.writeError(w, errors.NewBadRequest("label selector must match the name"))
+		return
+	}
+
+	backlog, err := s.getBacklog(r.Context(), namespace, component, name)
+	if err != nil {
+		s.Log.Error(err, "failed to get the subscription backlog", "namespace", namespace,
+			"component", component, "name", name)
+		if status, ok := err.(errors.APIStatus); ok {
+			s.writeError(w, status)
+			return
+		}
+		s.writeError(w, errors.NewServiceUnavailable(err.Error()))
+		return
+	}
+
+	metricLabels := map[string]string{
+		"component": component,
+		"name":      name,
+	}
+	s.writeResponse(w, http.StatusOK, &ExternalMetricValueList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ExternalMetricValueList",
+			APIVersion: externalMetricsGroupVersion,
+		},
+		Items: []ExternalMetricValue{
+			{
+				MetricName:   spec.BacklogMetricName,
+				MetricLabels: metricLabels,
+				Timestamp:    metav1.NewTime(time.Now()),
+				Value:        *resource.NewQuantity(backlog, resource.DecimalSI),
+			},
+		},
+	})
+}
+
+func (s *BacklogMetricsServer) writeResponse(w http.ResponseWriter, code int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		s.Log.Error(err, "failed to write the external metrics response")
+	}
+}
+
+func (s *BacklogMetricsServer) writeError(w http.ResponseWriter, err errors.APIStatus) {
+	status := err.Status()
+	status.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+	s.writeResponse(w, int(status.Code), &status)
+}
+
+// getBacklog returns the total backlog of the component's subscription on all its input topics
+func (s *BacklogMetricsServer) getBacklog(ctx context.Context, namespace, component, name string) (int64, error) {
+	var input v1alpha1.InputConf
+	var messaging *v1alpha1.PulsarMessaging
+	var subscription string
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+
+	switch component {
+	case spec.ComponentFunction:
+		function := &v1alpha2.Function{}
+		if err := s.Get(ctx, key, function); err != nil {
+			return 0, err
+		}
+		input, messaging, subscription = function.Spec.Input, function.Spec.Pulsar, makeFunctionSubscriptionName(function)
+	case spec.ComponentSink:
+		sink := &v1alpha2.Sink{}
+		if err := s.Get(ctx, key, sink); err != nil {
+			return 0, err
+		}
+		input, messaging, subscription = sink.Spec.Input, sink.Spec.Pulsar, makeSinkSubscriptionName(sink)
+	default:
+		return 0, errors.NewBadRequest(fmt.Sprintf("backlog is not available for the component %s", component))
+	}
+
+	return getInputBacklog(ctx, s.Client, namespace, input, messaging, subscription)
+}
@@ -0,0 +1,107 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	autov2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// updateDegradedCondition records the result of applying the desired state in the Degraded condition.
+// The status is only updated when the condition changes, and the reconcile error is returned if there is one.
+func updateDegradedCondition(ctx context.Context, c client.Client, obj runtime.Object,
+	conditions *[]v1alpha2.Condition, generation int64, reconcileErr error) error {
+	degraded := v1alpha2.CreateCondition(v1alpha2.Degraded, metav1.ConditionFalse,
+		v1alpha2.ReasonReconciled, "", generation)
+	if reconcileErr != nil {
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = v1alpha2.ReasonReconcileFailed
+		degraded.Message = reconcileErr.Error()
+	}
+
+	existing := v1alpha2.FindCondition(*conditions, v1alpha2.Degraded)
+	if existing != nil && existing.Status == degraded.Status && existing.Reason == degraded.Reason &&
+		existing.Message == degraded.Message && existing.ObservedGeneration == degraded.ObservedGeneration {
+		return reconcileErr
+	}
+
+	v1alpha2.SetCondition(conditions, degraded)
+	if err := c.Status().Update(ctx, obj); err != nil {
+		if reconcileErr != nil {
+			return reconcileErr
+		}
+		return err
+	}
+	return reconcileErr
+}
+
+// minRequeueAfter returns the shortest of the requeue intervals, zero means no requeue
+func minRequeueAfter(intervals ...time.Duration) time.Duration {
+	var requeueAfter time.Duration
+	for _, interval := range intervals {
+		if interval > 0 && (requeueAfter == 0 || interval < requeueAfter) {
+			requeueAfter = interval
+		}
+	}
+	return requeueAfter
+}
+
+// observeSuspend sets the Suspended condition according to spec.suspend
+func observeSuspend(conditions *[]v1alpha2.Condition, suspend bool, generation int64) {
+	if !suspend {
+		v1alpha2.RemoveCondition(conditions, v1alpha2.Suspended)
+		return
+	}
+	v1alpha2.SetCondition(conditions, v1alpha2.CreateCondition(v1alpha2.Suspended, metav1.ConditionTrue,
+		v1alpha2.ReasonSuspended, "scaled to zero by spec.suspend", generation))
+}
+
+// deleteHPA deletes the HPA of the component, it returns true if the HPA existed
+func deleteHPA(ctx context.Context, c client.Client, objectMeta *metav1.ObjectMeta) (bool, error) {
+	hpa := &autov2beta2.HorizontalPodAutoscaler{}
+	hpa.Namespace = objectMeta.Namespace
+	hpa.Name = objectMeta.Name
+	if err := c.Delete(ctx, hpa); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// applyConfigMap creates or updates a ConfigMap generated by the operator
+func applyConfigMap(ctx context.Context, c client.Client, configMap *corev1.ConfigMap) error {
+	desired := configMap.DeepCopy()
+	_, err := ctrl.CreateOrUpdate(ctx, c, configMap, func() error {
+		configMap.Labels = desired.Labels
+		configMap.OwnerReferences = desired.OwnerReferences
+		configMap.Data = desired.Data
+		return nil
+	})
+	return err
+}
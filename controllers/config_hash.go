@@ -0,0 +1,257 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// getConfigHash returns the hash of the content of the ConfigMaps and the Secrets in the namespace,
+// the objects which do not exist yet are left out
+func getConfigHash(ctx context.Context, c client.Client, namespace string,
+	configMapNames, secretNames []string) (string, error) {
+	configMaps := make([]corev1.ConfigMap, 0, len(configMapNames))
+	for _, name := range configMapNames {
+		configMap := corev1.ConfigMap{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &configMap); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return "", err
+		}
+		configMaps = append(configMaps, configMap)
+	}
+	secrets := make([]corev1.Secret, 0, len(secretNames))
+	for _, name := range secretNames {
+		secret := corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &secret); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return "", err
+		}
+		secrets = append(secrets, secret)
+	}
+	return spec.MakeConfigHash(configMaps, secrets), nil
+}
+
+// setConfigHash sets the hash into the annotations of the pod template generated with the policy
+func setConfigHash(policy *v1alpha1.PodPolicy, hash string) {
+	if hash == "" {
+		return
+	}
+	annotations := make(map[string]string, len(policy.Annotations)+1)
+	for key, value := range policy.Annotations {
+		annotations[key] = value
+	}
+	annotations[spec.AnnotationConfigHash] = hash
+	policy.Annotations = annotations
+}
+
+// the fields the components are indexed with by the objects they refer to
+const (
+	configMapsIndex = "spec.references.configMaps"
+	secretsIndex    = "spec.references.secrets"
+	connectionIndex = "spec.references.connection"
+)
+
+// componentReferences are the objects referred by a component, the component is reconciled when any of them changes
+type componentReferences struct {
+	configMaps    []string
+	secrets       []string
+	connectionRef *v1alpha1.PulsarConnectionRef
+}
+
+// referencesOf returns the references of a component
+type referencesOf func(object runtime.Object) componentReferences
+
+// referencesLister lists the requests of the components selected by the options
+type referencesLister func(ctx context.Context, opts ...client.ListOption) ([]reconcile.Request, error)
+
+func makeComponentReferences(configMaps, secrets []string, messaging *v1alpha1.PulsarMessaging) componentReferences {
+	references := componentReferences{configMaps: configMaps, secrets: secrets}
+	if messaging != nil {
+		references.connectionRef = messaging.ConnectionRef
+	}
+	return references
+}
+
+// connectionIndexValue returns the value a component referring to the connection is indexed with
+func connectionIndexValue(clusterScoped bool, name string) string {
+	if clusterScoped {
+		return string(v1alpha1.PulsarConnectionKindCluster) + "/" + name
+	}
+	return string(v1alpha1.PulsarConnectionKindNamespaced) + "/" + name
+}
+
+// indexReferences indexes the components by the ConfigMaps, the Secrets and the connection they refer to
+func indexReferences(indexer client.FieldIndexer, component runtime.Object, references referencesOf) error {
+	ctx := context.Background()
+	if err := indexer.IndexField(ctx, component, configMapsIndex, func(object runtime.Object) []string {
+		return references(object).configMaps
+	}); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(ctx, component, secretsIndex, func(object runtime.Object) []string {
+		return references(object).secrets
+	}); err != nil {
+		return err
+	}
+	return indexer.IndexField(ctx, component, connectionIndex, func(object runtime.Object) []string {
+		ref := references(object).connectionRef
+		if ref == nil {
+			return nil
+		}
+		return []string{connectionIndexValue(ref.IsClusterScoped(), ref.Name)}
+	})
+}
+
+// watchReferences reconciles the components referring to the ConfigMaps, the Secrets and the connections which change,
+// the components are looked up in the cache through the indexes of their references
+func watchReferences(mgr ctrl.Manager, blder *builder.Builder, c client.Client, log logr.Logger,
+	component runtime.Object, references referencesOf, list referencesLister) (*builder.Builder, error) {
+	if err := indexReferences(mgr.GetFieldIndexer(), component, references); err != nil {
+		return nil, err
+	}
+	eventHandler := &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(object handler.MapObject) []reconcile.Request {
+			requests, err := mapReferences(context.Background(), c, list, object)
+			if err != nil {
+				log.Error(err, "failed to list the components referring to the object",
+					"namespace", object.Meta.GetNamespace(), "name", object.Meta.GetName())
+			}
+			return requests
+		}),
+	}
+	return blder.
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, eventHandler).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, eventHandler).
+		Watches(&source.Kind{Type: &v1alpha2.PulsarConnection{}}, eventHandler).
+		Watches(&source.Kind{Type: &v1alpha2.ClusterPulsarConnection{}}, eventHandler), nil
+}
+
+func mapReferences(ctx context.Context, c client.Client, list referencesLister,
+	object handler.MapObject) ([]reconcile.Request, error) {
+	namespace, name := object.Meta.GetNamespace(), object.Meta.GetName()
+	switch object.Object.(type) {
+	case *corev1.ConfigMap:
+		return list(ctx, client.InNamespace(namespace), client.MatchingFields{configMapsIndex: name})
+	case *corev1.Secret:
+		return mapSecretReferences(ctx, c, list, namespace, name)
+	case *v1alpha2.PulsarConnection:
+		return list(ctx, client.InNamespace(namespace),
+			client.MatchingFields{connectionIndex: connectionIndexValue(false, name)})
+	case *v1alpha2.ClusterPulsarConnection:
+		return list(ctx, client.MatchingFields{connectionIndex: connectionIndexValue(true, name)})
+	}
+	return nil, nil
+}
+
+// mapSecretReferences returns the requests of the components referring to the secret, directly or through
+// a connection. The secrets of a ClusterPulsarConnection may be in any namespace.
+func mapSecretReferences(ctx context.Context, c client.Client, list referencesLister,
+	namespace, name string) ([]reconcile.Request, error) {
+	requests, err := list(ctx, client.InNamespace(namespace), client.MatchingFields{secretsIndex: name})
+	if err != nil {
+		return nil, err
+	}
+	connections := &v1alpha2.PulsarConnectionList{}
+	if err := c.List(ctx, connections, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for i := range connections.Items {
+		connection := &connections.Items[i]
+		if !connectionRefersToSecret(&connection.Spec, connection.Namespace, namespace, name) {
+			continue
+		}
+		connectionRequests, err := list(ctx, client.InNamespace(namespace),
+			client.MatchingFields{connectionIndex: connectionIndexValue(false, connection.Name)})
+		if err != nil {
+			return nil, err
+		}
+		requests = appendRequests(requests, connectionRequests...)
+	}
+	clusterConnections := &v1alpha2.ClusterPulsarConnectionList{}
+	if err := c.List(ctx, clusterConnections); err != nil {
+		return nil, err
+	}
+	for i := range clusterConnections.Items {
+		connection := &clusterConnections.Items[i]
+		if !connectionRefersToSecret(&connection.Spec, "", namespace, name) {
+			continue
+		}
+		connectionRequests, err := list(ctx,
+			client.MatchingFields{connectionIndex: connectionIndexValue(true, connection.Name)})
+		if err != nil {
+			return nil, err
+		}
+		requests = appendRequests(requests, connectionRequests...)
+	}
+	return requests, nil
+}
+
+// connectionRefersToSecret returns whether the connection in the namespace refers to the secret
+func connectionRefersToSecret(connection *v1alpha2.PulsarConnectionSpec, connectionNamespace,
+	namespace, name string) bool {
+	var secretRefs []corev1.SecretReference
+	if connection.Auth != nil {
+		secretRefs = append(secretRefs, connection.Auth.SecretRef)
+	}
+	if connection.TLS != nil && connection.TLS.CertSecretRef != nil {
+		secretRefs = append(secretRefs, *connection.TLS.CertSecretRef)
+	}
+	for _, ref := range secretRefs {
+		secretNamespace, err := resolveSecretNamespace(ref, connectionNamespace)
+		if err == nil && secretNamespace == namespace && ref.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// appendRequests appends the requests which are not in the list yet
+func appendRequests(requests []reconcile.Request, others ...reconcile.Request) []reconcile.Request {
+	for _, request := range others {
+		found := false
+		for _, existing := range requests {
+			if existing == request {
+				found = true
+				break
+			}
+		}
+		if !found {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
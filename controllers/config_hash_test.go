@@ -0,0 +1,187 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func makeReferencingFunction(namespace, name string, messaging *v1alpha1.PulsarMessaging) *v1alpha2.Function {
+	return &v1alpha2.Function{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       v1alpha1.FunctionSpec{Messaging: v1alpha1.Messaging{Pulsar: messaging}},
+	}
+}
+
+// makeIndexedLister lists the functions the way the cache does with the indexes of their references
+func makeIndexedLister(calls *int, functions ...*v1alpha2.Function) referencesLister {
+	return func(ctx context.Context, opts ...client.ListOption) ([]reconcile.Request, error) {
+		*calls++
+		listOpts := &client.ListOptions{}
+		listOpts.ApplyOptions(opts)
+		var requests []reconcile.Request
+		for _, function := range functions {
+			if listOpts.Namespace != "" && function.Namespace != listOpts.Namespace {
+				continue
+			}
+			if listOpts.FieldSelector != nil && !indexedFieldsMatch(function, listOpts) {
+				continue
+			}
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: function.Namespace, Name: function.Name}})
+		}
+		return requests, nil
+	}
+}
+
+func indexedFieldsMatch(function *v1alpha2.Function, listOpts *client.ListOptions) bool {
+	references := functionReferences(function)
+	values := map[string][]string{
+		configMapsIndex: references.configMaps,
+		secretsIndex:    references.secrets,
+	}
+	if ref := references.connectionRef; ref != nil {
+		values[connectionIndex] = []string{connectionIndexValue(ref.IsClusterScoped(), ref.Name)}
+	}
+	for field, indexed := range values {
+		if value, ok := listOpts.FieldSelector.RequiresExactMatch(field); ok {
+			for _, v := range indexed {
+				if v == value {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return false
+}
+
+func requestNames(requests []reconcile.Request) []string {
+	names := make([]string, 0, len(requests))
+	for _, request := range requests {
+		names = append(names, request.String())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestMapReferences(t *testing.T) {
+	connection := &v1alpha2.PulsarConnection{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "conn"},
+		Spec: v1alpha2.PulsarConnectionSpec{Auth: &v1alpha2.PulsarConnectionAuth{
+			SecretRef: corev1.SecretReference{Name: "conn-auth"}}},
+	}
+	clusterConnection := &v1alpha2.ClusterPulsarConnection{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-conn"},
+		Spec: v1alpha2.PulsarConnectionSpec{Auth: &v1alpha2.PulsarConnectionAuth{
+			SecretRef: corev1.SecretReference{Namespace: "pulsar", Name: "cluster-auth"}}},
+	}
+	c := newFinalizerTestClient(t, connection, clusterConnection)
+	var calls int
+	list := makeIndexedLister(&calls,
+		makeReferencingFunction("default", "direct", &v1alpha1.PulsarMessaging{
+			PulsarConfig: "pulsar-config", AuthSecret: "auth"}),
+		makeReferencingFunction("other", "direct", &v1alpha1.PulsarMessaging{
+			PulsarConfig: "pulsar-config", AuthSecret: "auth"}),
+		makeReferencingFunction("default", "connected", &v1alpha1.PulsarMessaging{
+			ConnectionRef: &v1alpha1.PulsarConnectionRef{Name: "conn"}}),
+		makeReferencingFunction("other", "cluster-connected", &v1alpha1.PulsarMessaging{
+			ConnectionRef: &v1alpha1.PulsarConnectionRef{Name: "cluster-conn", Kind: v1alpha1.PulsarConnectionKindCluster}}),
+	)
+
+	tests := []struct {
+		name     string
+		object   runtime.Object
+		meta     metav1.Object
+		expected []string
+		calls    int
+	}{
+		{
+			name:     "configmap in the namespace",
+			object:   &corev1.ConfigMap{},
+			meta:     &metav1.ObjectMeta{Namespace: "default", Name: "pulsar-config"},
+			expected: []string{"default/direct"},
+			calls:    1,
+		},
+		{
+			name:     "secret referred directly",
+			object:   &corev1.Secret{},
+			meta:     &metav1.ObjectMeta{Namespace: "other", Name: "auth"},
+			expected: []string{"other/direct"},
+			calls:    1,
+		},
+		{
+			name:     "secret of a connection",
+			object:   &corev1.Secret{},
+			meta:     &metav1.ObjectMeta{Namespace: "default", Name: "conn-auth"},
+			expected: []string{"default/connected"},
+			calls:    2,
+		},
+		{
+			name:     "secret of a cluster connection in another namespace",
+			object:   &corev1.Secret{},
+			meta:     &metav1.ObjectMeta{Namespace: "pulsar", Name: "cluster-auth"},
+			expected: []string{"other/cluster-connected"},
+			calls:    2,
+		},
+		{
+			name:     "unreferenced secret",
+			object:   &corev1.Secret{},
+			meta:     &metav1.ObjectMeta{Namespace: "default", Name: "unused"},
+			expected: []string{},
+			calls:    1,
+		},
+		{
+			name:     "connection",
+			object:   &v1alpha2.PulsarConnection{},
+			meta:     &metav1.ObjectMeta{Namespace: "default", Name: "conn"},
+			expected: []string{"default/connected"},
+			calls:    1,
+		},
+		{
+			name:     "cluster connection",
+			object:   &v1alpha2.ClusterPulsarConnection{},
+			meta:     &metav1.ObjectMeta{Name: "cluster-conn"},
+			expected: []string{"other/cluster-connected"},
+			calls:    1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			requests, err := mapReferences(context.TODO(), c, list, handler.MapObject{Meta: tt.meta, Object: tt.object})
+			assert.NilError(t, err)
+			assert.DeepEqual(t, requestNames(requests), tt.expected)
+			// the components are only listed through the indexes of the referred objects
+			assert.Equal(t, calls, tt.calls)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// the request header configuration of the API aggregation layer published by the kube-apiserver
+const (
+	extensionAPIServerAuthenticationNamespace = "kube-system"
+	extensionAPIServerAuthenticationName      = "extension-apiserver-authentication"
+
+	requestHeaderClientCAKey        = "requestheader-client-ca-file"
+	requestHeaderAllowedNamesKey    = "requestheader-allowed-names"
+	requestHeaderUsernameHeadersKey = "requestheader-username-headers"
+	requestHeaderGroupHeadersKey    = "requestheader-group-headers"
+)
+
+// requestHeaderAuthenticator authenticates the requests proxied by the API aggregation layer,
+// the proxy presents a client certificate signed by the request header CA and passes the user in the headers
+type requestHeaderAuthenticator struct {
+	allowedNames    []string
+	usernameHeaders []string
+	groupHeaders    []string
+}
+
+// requestUser is the user on behalf of whom the aggregation layer proxies the request
+type requestUser struct {
+	name   string
+	groups []string
+}
+
+// loadRequestHeaderAuthenticator reads the request header configuration and returns the authenticator
+// with the CAs verifying the client certificates of the proxy
+func loadRequestHeaderAuthenticator(ctx context.Context, reader client.Reader) (*requestHeaderAuthenticator,
+	*x509.CertPool, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := reader.Get(ctx, types.NamespacedName{
+		Namespace: extensionAPIServerAuthenticationNamespace,
+		Name:      extensionAPIServerAuthenticationName,
+	}, configMap); err != nil {
+		return nil, nil, fmt.Errorf("failed to get the request header configuration: %v", err)
+	}
+
+	clientCA, ok := configMap.Data[requestHeaderClientCAKey]
+	if !ok {
+		return nil, nil, fmt.Errorf("%s is not set in %s/%s, the API aggregation layer is not enabled",
+			requestHeaderClientCAKey, extensionAPIServerAuthenticationNamespace, extensionAPIServerAuthenticationName)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM([]byte(clientCA)) {
+		return nil, nil, fmt.Errorf("invalid %s", requestHeaderClientCAKey)
+	}
+
+	authenticator := &requestHeaderAuthenticator{}
+	for key, value := range map[string]*[]string{
+		requestHeaderAllowedNamesKey:    &authenticator.allowedNames,
+		requestHeaderUsernameHeadersKey: &authenticator.usernameHeaders,
+		requestHeaderGroupHeadersKey:    &authenticator.groupHeaders,
+	} {
+		if data, ok := configMap.Data[key]; ok && data != "" {
+			if err := json.Unmarshal([]byte(data), value); err != nil {
+				return nil, nil, fmt.Errorf("invalid %s: %v", key, err)
+			}
+		}
+	}
+	if len(authenticator.usernameHeaders) == 0 {
+		return nil, nil, fmt.Errorf("%s is not set", requestHeaderUsernameHeadersKey)
+	}
+	return authenticator, clientCAs, nil
+}
+
+// authenticate returns the user of a request whose client certificate is verified by the TLS handshake
+func (a *requestHeaderAuthenticator) authenticate(r *http.Request) (*requestUser, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, fmt.Errorf("no verified client certificate")
+	}
+	if len(a.allowedNames) > 0 {
+		commonName := r.TLS.VerifiedChains[0][0].Subject.CommonName
+		allowed := false
+		for _, name := range a.allowedNames {
+			if name == commonName {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, fmt.Errorf("client certificate %s is not allowed", commonName)
+		}
+	}
+
+	user := &requestUser{}
+	for _, header := range a.usernameHeaders {
+		if name := r.Header.Get(header); name != "" {
+			user.name = name
+			break
+		}
+	}
+	if user.name == "" {
+		return nil, fmt.Errorf("no user in the request headers")
+	}
+	for _, header := range a.groupHeaders {
+		user.groups = append(user.groups, r.Header.Values(header)...)
+	}
+	return user, nil
+}
+
+// authorize checks with a SubjectAccessReview that the user can access the resource or the path,
+// the error response is written when the user is not allowed
+func (s *BacklogMetricsServer) authorize(ctx context.Context, w http.ResponseWriter, user *requestUser,
+	nonResourceAttributes *authorizationv1.NonResourceAttributes,
+	resourceAttributes *authorizationv1.ResourceAttributes) bool {
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:                  user.name,
+			Groups:                user.groups,
+			NonResourceAttributes: nonResourceAttributes,
+			ResourceAttributes:    resourceAttributes,
+		},
+	}
+	if err := s.Create(ctx, review); err != nil {
+		s.Log.Error(err, "failed to authorize the external metrics request", "user", user.name)
+		s.writeError(w, errors.NewInternalError(err))
+		return false
+	}
+	if !review.Status.Allowed {
+		s.writeError(w, errors.NewForbidden(schema.GroupResource{Group: "external.metrics.k8s.io"}, "",
+			fmt.Errorf("user %s is not allowed: %s", user.name, review.Status.Reason)))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reviewClient answers the SubjectAccessReviews with the allowed users
+type reviewClient struct {
+	client.Client
+	allowedUsers map[string]bool
+	reviews      []authorizationv1.SubjectAccessReviewSpec
+}
+
+func (c *reviewClient) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	review := obj.(*authorizationv1.SubjectAccessReview)
+	c.reviews = append(c.reviews, review.Spec)
+	review.Status.Allowed = c.allowedUsers[review.Spec.User]
+	return nil
+}
+
+func makeAggregatedRequest(path, commonName string, headers map[string][]string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	if commonName != "" {
+		r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{
+			{{Subject: pkix.Name{CommonName: commonName}}},
+		}}
+	}
+	for header, values := range headers {
+		for _, value := range values {
+			r.Header.Add(header, value)
+		}
+	}
+	return r
+}
+
+func TestRequestHeaderAuthenticator(t *testing.T) {
+	authenticator := &requestHeaderAuthenticator{
+		allowedNames:    []string{"front-proxy-client"},
+		usernameHeaders: []string{"X-Remote-User"},
+		groupHeaders:    []string{"X-Remote-Group"},
+	}
+
+	user, err := authenticator.authenticate(makeAggregatedRequest(externalMetricsPath, "front-proxy-client",
+		map[string][]string{"X-Remote-User": {"system:serviceaccount:kube-system:horizontal-pod-autoscaler"},
+			"X-Remote-Group": {"system:serviceaccounts", "system:authenticated"}}))
+	assert.NilError(t, err)
+	assert.Equal(t, user.name, "system:serviceaccount:kube-system:horizontal-pod-autoscaler")
+	assert.DeepEqual(t, user.groups, []string{"system:serviceaccounts", "system:authenticated"})
+
+	_, err = authenticator.authenticate(makeAggregatedRequest(externalMetricsPath, "",
+		map[string][]string{"X-Remote-User": {"admin"}}))
+	assert.ErrorContains(t, err, "no verified client certificate")
+
+	_, err = authenticator.authenticate(makeAggregatedRequest(externalMetricsPath, "kubelet",
+		map[string][]string{"X-Remote-User": {"admin"}}))
+	assert.ErrorContains(t, err, "client certificate kubelet is not allowed")
+
+	_, err = authenticator.authenticate(makeAggregatedRequest(externalMetricsPath, "front-proxy-client", nil))
+	assert.ErrorContains(t, err, "no user in the request headers")
+}
+
+func TestBacklogMetricsServerAuthorization(t *testing.T) {
+	reviews := &reviewClient{allowedUsers: map[string]bool{"hpa": true}}
+	server := &BacklogMetricsServer{
+		Client: reviews,
+		Log:    ctrl.Log.WithName("test"),
+		authenticator: &requestHeaderAuthenticator{
+			usernameHeaders: []string{"X-Remote-User"},
+		},
+	}
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, makeAggregatedRequest(externalMetricsPath, "front-proxy-client", nil))
+	assert.Equal(t, recorder.Code, http.StatusUnauthorized)
+	assert.Equal(t, len(reviews.reviews), 0)
+
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, makeAggregatedRequest(externalMetricsPath, "front-proxy-client",
+		map[string][]string{"X-Remote-User": {"hpa"}}))
+	assert.Equal(t, recorder.Code, http.StatusOK)
+	assert.DeepEqual(t, reviews.reviews[0].NonResourceAttributes,
+		&authorizationv1.NonResourceAttributes{Path: externalMetricsPath, Verb: "get"})
+
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, makeAggregatedRequest(
+		externalMetricsPath+"/namespaces/default/pulsar_subscription_backlog?labelSelector=component%3Dfunction%2Cname%3Dfn",
+		"front-proxy-client", map[string][]string{"X-Remote-User": {"developer"}}))
+	assert.Equal(t, recorder.Code, http.StatusForbidden)
+	assert.DeepEqual(t, reviews.reviews[1].ResourceAttributes, &authorizationv1.ResourceAttributes{
+		Namespace: "default",
+		Verb:      "get",
+		Group:     "external.metrics.k8s.io",
+		Version:   "v1beta1",
+		Resource:  "pulsar_subscription_backlog",
+	})
+}
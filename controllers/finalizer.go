@@ -0,0 +1,263 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	"github.com/streamnative/pulsarctl/pkg/cli"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// cleanupRetryInterval is the interval of checking that the instances are stopped before the cleanup,
+	// and the initial backoff of the failed cleanups
+	cleanupRetryInterval = 5 * time.Second
+	// maxCleanupAttempts bounds the attempts of cleaning up the pulsar resources,
+	// the finalizer is removed without cleaning up once they are exhausted
+	maxCleanupAttempts = 5
+)
+
+// cleanupFailures counts the failed cleanups of the components being deleted
+var cleanupFailures = &cleanupTracker{failures: make(map[types.UID]*cleanupFailure)}
+
+type cleanupTracker struct {
+	mu       sync.Mutex
+	failures map[types.UID]*cleanupFailure
+}
+
+type cleanupFailure struct {
+	attempts  int
+	nextRetry time.Time
+}
+
+// backoff returns how long to wait before the next attempt of cleaning up the component
+func (t *cleanupTracker) backoff(uid types.UID) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if failure, ok := t.failures[uid]; ok {
+		return time.Until(failure.nextRetry)
+	}
+	return 0
+}
+
+// fail records a failed attempt and returns the number of attempts and the backoff before the next one
+func (t *cleanupTracker) fail(uid types.UID) (int, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	failure, ok := t.failures[uid]
+	if !ok {
+		failure = &cleanupFailure{}
+		t.failures[uid] = failure
+	}
+	failure.attempts++
+	backoff := cleanupRetryInterval << (failure.attempts - 1)
+	failure.nextRetry = time.Now().Add(backoff)
+	return failure.attempts, backoff
+}
+
+func (t *cleanupTracker) forget(uid types.UID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.failures, uid)
+}
+
+// cleanupResources are the Pulsar-side resources of a component which are deleted with it
+type cleanupResources struct {
+	messaging *v1alpha1.PulsarMessaging
+	// input and subscription are only set when the subscription should be deleted
+	input        *v1alpha1.InputConf
+	subscription string
+	packages     []string
+}
+
+func (c *cleanupResources) isEmpty() bool {
+	return c.input == nil && len(c.packages) == 0
+}
+
+// makeCleanupResources returns the resources to clean up according to CleanupSubscription
+// and the cleanup-packages annotation, nothing is cleaned up when the skip-cleanup annotation is set
+func makeCleanupResources(obj metav1.Object, messaging *v1alpha1.PulsarMessaging, runtime v1alpha1.Runtime,
+	cleanupSubscription bool, input *v1alpha1.InputConf, subscription string) *cleanupResources {
+	resources := &cleanupResources{messaging: messaging}
+	if obj.GetAnnotations()[spec.AnnotationSkipCleanup] == "true" {
+		return resources
+	}
+	if cleanupSubscription && input != nil {
+		resources.input = input
+		resources.subscription = subscription
+	}
+	if obj.GetAnnotations()[spec.AnnotationCleanupPackages] == "true" {
+		resources.packages = spec.GetPackageURLs(runtime)
+	}
+	return resources
+}
+
+// reconcileFinalizer keeps the cleanup finalizer in sync with the cleanup settings of the component, and cleans
+// up the Pulsar-side resources once the component is being deleted. It returns true when the component is being
+// deleted, the reconciliation should then stop with the returned result.
+func reconcileFinalizer(ctx context.Context, c client.Client, log logr.Logger, recorder record.EventRecorder,
+	obj controllerutil.Object, statefulSetName string, resources *cleanupResources) (bool, ctrl.Result, error) {
+	if obj.GetDeletionTimestamp().IsZero() {
+		enabled := !resources.isEmpty()
+		if enabled != controllerutil.ContainsFinalizer(obj, spec.CleanupFinalizerName) {
+			if enabled {
+				controllerutil.AddFinalizer(obj, spec.CleanupFinalizerName)
+			} else {
+				controllerutil.RemoveFinalizer(obj, spec.CleanupFinalizerName)
+			}
+			if err := c.Update(ctx, obj); err != nil {
+				log.Error(err, "failed to update the cleanup finalizer", "name", obj.GetName())
+				return false, ctrl.Result{}, err
+			}
+		}
+		return false, ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(obj, spec.CleanupFinalizerName) {
+		return true, ctrl.Result{}, nil
+	}
+	if resources.isEmpty() {
+		// the cleanup is skipped by annotation or disabled after the finalizer was added
+		return true, ctrl.Result{}, removeCleanupFinalizer(ctx, c, log, obj)
+	}
+	if backoff := cleanupFailures.backoff(obj.GetUID()); backoff > 0 {
+		return true, ctrl.Result{RequeueAfter: backoff}, nil
+	}
+
+	// the subscription can not be deleted while the instances are still consuming from it
+	stopped, err := stopInstances(ctx, c, obj.GetNamespace(), statefulSetName)
+	if err != nil {
+		return true, ctrl.Result{}, err
+	}
+	if !stopped {
+		return true, ctrl.Result{RequeueAfter: cleanupRetryInterval}, nil
+	}
+
+	err = cleanupPulsarResources(ctx, c, obj.GetNamespace(), resources)
+	switch {
+	case err == nil:
+		if resources.messaging != nil {
+			recorder.Event(obj, corev1.EventTypeNormal, EventReasonCleanedUp, "Cleaned up the pulsar resources")
+		}
+	case errors.IsNotFound(err):
+		// the pulsar cluster can not be reached without the referenced ConfigMap, Secret or connection,
+		// which are usually deleted along with the component
+		log.Info("skipped the cleanup of the pulsar resources", "name", obj.GetName(), "reason", err.Error())
+		recorder.Eventf(obj, corev1.EventTypeWarning, EventReasonSkippedCleanup,
+			"Skipped the cleanup of the pulsar resources: %v", err)
+	default:
+		attempts, backoff := cleanupFailures.fail(obj.GetUID())
+		if attempts < maxCleanupAttempts {
+			log.Error(err, "failed to clean up the pulsar resources", "name", obj.GetName(), "attempts", attempts)
+			recorder.Eventf(obj, corev1.EventTypeWarning, EventReasonFailedCleanup,
+				"Failed to clean up the pulsar resources (attempt %d of %d): %v", attempts, maxCleanupAttempts, err)
+			return true, ctrl.Result{RequeueAfter: backoff}, nil
+		}
+		log.Error(err, "gave up cleaning up the pulsar resources", "name", obj.GetName(), "attempts", attempts)
+		recorder.Eventf(obj, corev1.EventTypeWarning, EventReasonAbandonedCleanup,
+			"Gave up cleaning up the pulsar resources after %d attempts, they should be deleted manually: %v",
+			attempts, err)
+	}
+
+	if err := removeCleanupFinalizer(ctx, c, log, obj); err != nil {
+		return true, ctrl.Result{}, err
+	}
+	cleanupFailures.forget(obj.GetUID())
+	return true, ctrl.Result{}, nil
+}
+
+func removeCleanupFinalizer(ctx context.Context, c client.Client, log logr.Logger, obj controllerutil.Object) error {
+	controllerutil.RemoveFinalizer(obj, spec.CleanupFinalizerName)
+	if err := c.Update(ctx, obj); err != nil {
+		log.Error(err, "failed to remove the cleanup finalizer", "name", obj.GetName())
+		return err
+	}
+	return nil
+}
+
+// stopInstances deletes the statefulset of the component in the foreground,
+// and returns true once the statefulset and its pods are gone
+func stopInstances(ctx context.Context, c client.Client, namespace, name string) (bool, error) {
+	statefulSet := &appsv1.StatefulSet{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, statefulSet)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	if statefulSet.DeletionTimestamp.IsZero() {
+		err = c.Delete(ctx, statefulSet, client.PropagationPolicy(metav1.DeletePropagationForeground))
+		if err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+	}
+	return false, nil
+}
+
+func cleanupPulsarResources(ctx context.Context, c client.Client, namespace string,
+	resources *cleanupResources) error {
+	if resources.isEmpty() || resources.messaging == nil {
+		return nil
+	}
+	admin, err := newPulsarAdmin(ctx, c, namespace, resources.messaging)
+	if err != nil {
+		return err
+	}
+
+	if resources.input != nil {
+		for _, topic := range collectInputTopics(*resources.input) {
+			topicName, err := utils.GetTopicName(topic)
+			if err != nil {
+				return err
+			}
+			err = admin.Subscriptions().Delete(*topicName, resources.subscription)
+			if err != nil && !isPulsarNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	for _, packageURL := range resources.packages {
+		if err := admin.Packages().Delete(packageURL); err != nil && !isPulsarNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func isPulsarNotFound(err error) bool {
+	pulsarErr, ok := err.(cli.Error)
+	return ok && pulsarErr.Code == http.StatusNotFound
+}
@@ -0,0 +1,139 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func makeDeletingFunction(name string, annotations map[string]string) *v1alpha2.Function {
+	deletionTimestamp := metav1.Now()
+	return &v1alpha2.Function{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			UID:               types.UID(name),
+			Annotations:       annotations,
+			Finalizers:        []string{spec.CleanupFinalizerName},
+			DeletionTimestamp: &deletionTimestamp,
+		},
+		Spec: v1alpha1.FunctionSpec{
+			Input: v1alpha1.InputConf{Topics: []string{"persistent://public/default/in"}},
+			Messaging: v1alpha1.Messaging{
+				Pulsar: &v1alpha1.PulsarMessaging{
+					PulsarConfig: "pulsar-config",
+				},
+			},
+		},
+	}
+}
+
+func reconcileTestFinalizer(t *testing.T, c client.Client, recorder record.EventRecorder,
+	function *v1alpha2.Function) (ctrl.Result, error) {
+	resources := makeCleanupResources(function, function.Spec.Pulsar, function.Spec.Runtime, true,
+		&function.Spec.Input, "sub")
+	deleted, result, err := reconcileFinalizer(context.TODO(), c, ctrl.Log.WithName("test"), recorder, function,
+		function.Name+"-function", resources)
+	assert.Assert(t, deleted)
+	return result, err
+}
+
+func newFinalizerTestClient(t *testing.T, objs ...runtime.Object) client.Client {
+	scheme := runtime.NewScheme()
+	assert.NilError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NilError(t, v1alpha2.AddToScheme(scheme))
+	return fake.NewFakeClientWithScheme(scheme, objs...)
+}
+
+func TestReconcileFinalizerSkipCleanup(t *testing.T) {
+	function := makeDeletingFunction("skipped", map[string]string{spec.AnnotationSkipCleanup: "true"})
+	c := newFinalizerTestClient(t, function)
+	recorder := record.NewFakeRecorder(10)
+
+	result, err := reconcileTestFinalizer(t, c, recorder, function)
+	assert.NilError(t, err)
+	assert.Equal(t, result, ctrl.Result{})
+	assert.Assert(t, !controllerutil.ContainsFinalizer(function, spec.CleanupFinalizerName))
+	assert.Equal(t, len(recorder.Events), 0)
+}
+
+func TestReconcileFinalizerReferenceNotFound(t *testing.T) {
+	function := makeDeletingFunction("orphaned", nil)
+	c := newFinalizerTestClient(t, function)
+	recorder := record.NewFakeRecorder(10)
+
+	result, err := reconcileTestFinalizer(t, c, recorder, function)
+	assert.NilError(t, err)
+	assert.Equal(t, result, ctrl.Result{})
+	assert.Assert(t, !controllerutil.ContainsFinalizer(function, spec.CleanupFinalizerName))
+	event := <-recorder.Events
+	assert.Assert(t, strings.HasPrefix(event, "Warning "+EventReasonSkippedCleanup), event)
+}
+
+func TestReconcileFinalizerBoundedRetries(t *testing.T) {
+	function := makeDeletingFunction("unreachable", nil)
+	c := newFinalizerTestClient(t, function, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "pulsar-config", Namespace: "default"},
+		Data:       map[string]string{"webServiceURL": "http://127.0.0.1:1"},
+	})
+	recorder := record.NewFakeRecorder(maxCleanupAttempts)
+	defer cleanupFailures.forget(function.UID)
+
+	for attempt := 1; attempt < maxCleanupAttempts; attempt++ {
+		result, err := reconcileTestFinalizer(t, c, recorder, function)
+		assert.NilError(t, err)
+		assert.Equal(t, result.RequeueAfter, cleanupRetryInterval<<(attempt-1))
+		assert.Assert(t, controllerutil.ContainsFinalizer(function, spec.CleanupFinalizerName))
+		event := <-recorder.Events
+		assert.Assert(t, strings.HasPrefix(event, "Warning "+EventReasonFailedCleanup), event)
+
+		// the cleanup is not attempted again before the backoff
+		result, err = reconcileTestFinalizer(t, c, recorder, function)
+		assert.NilError(t, err)
+		assert.Assert(t, result.RequeueAfter > 0)
+		assert.Equal(t, len(recorder.Events), 0)
+		cleanupFailures.failures[function.UID].nextRetry = time.Now()
+	}
+
+	result, err := reconcileTestFinalizer(t, c, recorder, function)
+	assert.NilError(t, err)
+	assert.Equal(t, result, ctrl.Result{})
+	assert.Assert(t, !controllerutil.ContainsFinalizer(function, spec.CleanupFinalizerName))
+	event := <-recorder.Events
+	assert.Assert(t, strings.HasPrefix(event, "Warning "+EventReasonAbandonedCleanup), event)
+	_, tracked := cleanupFailures.failures[function.UID]
+	assert.Assert(t, !tracked)
+}
@@ -19,9 +19,10 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 
-	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
 	"github.com/streamnative/function-mesh/controllers/spec"
 	appsv1 "k8s.io/api/apps/v1"
 	autov2beta2 "k8s.io/api/autoscaling/v2beta2"
@@ -33,14 +34,12 @@ import (
 )
 
 func (r *FunctionReconciler) ObserveFunctionStatefulSet(ctx context.Context, req ctrl.Request,
-	function *v1alpha1.Function) error {
-	condition, ok := function.Status.Conditions[v1alpha1.StatefulSet]
-	if !ok {
-		function.Status.Conditions[v1alpha1.StatefulSet] = v1alpha1.ResourceCondition{
-			Condition: v1alpha1.StatefulSetReady,
-			Status:    metav1.ConditionFalse,
-			Action:    v1alpha1.Create,
-		}
+	function *v1alpha2.Function) error {
+	condition := v1alpha2.FindCondition(function.Status.Conditions, v1alpha2.StatefulSetReady)
+	if condition == nil {
+		v1alpha2.SetCondition(&function.Status.Conditions, v1alpha2.CreateCondition(
+			v1alpha2.StatefulSetReady, metav1.ConditionFalse, v1alpha2.ReasonCreating,
+			"statefulset is not created", function.Generation))
 		return nil
 	}
 
@@ -64,27 +63,51 @@ func (r *FunctionReconciler) ObserveFunctionStatefulSet(ctx context.Context, req
 	}
 	function.Status.Selector = selector.String()
 
-	if *statefulSet.Spec.Replicas != *function.Spec.Replicas || !reflect.DeepEqual(statefulSet.Spec.Template, spec.MakeFunctionStatefulSet(function).Spec.Template) {
-		condition.Status = metav1.ConditionFalse
-		condition.Action = v1alpha1.Update
-		function.Status.Conditions[v1alpha1.StatefulSet] = condition
+	desiredStatefulSet, err := r.makeFunctionStatefulSet(ctx, function)
+	if err != nil {
+		return err
+	}
+	if *statefulSet.Spec.Replicas != *desiredStatefulSet.Spec.Replicas || !reflect.DeepEqual(statefulSet.Spec.Template, desiredStatefulSet.Spec.Template) {
+		v1alpha2.SetCondition(&function.Status.Conditions, v1alpha2.CreateCondition(
+			v1alpha2.StatefulSetReady, metav1.ConditionFalse, v1alpha2.ReasonUpdating,
+			"statefulset is outdated", function.Generation))
 		return nil
 	}
 
-	if statefulSet.Status.ReadyReplicas == *function.Spec.Replicas {
-		condition.Action = v1alpha1.NoAction
-		condition.Status = metav1.ConditionTrue
+	if statefulSet.Status.ReadyReplicas == *desiredStatefulSet.Spec.Replicas {
+		v1alpha2.SetCondition(&function.Status.Conditions, v1alpha2.CreateCondition(
+			v1alpha2.StatefulSetReady, metav1.ConditionTrue, v1alpha2.ReasonReconciled,
+			"", function.Generation))
 	} else {
-		condition.Action = v1alpha1.Wait
+		v1alpha2.SetCondition(&function.Status.Conditions, v1alpha2.CreateCondition(
+			v1alpha2.StatefulSetReady, metav1.ConditionFalse, v1alpha2.ReasonWaiting,
+			fmt.Sprintf("%d of %d replicas are ready", statefulSet.Status.ReadyReplicas, *desiredStatefulSet.Spec.Replicas),
+			function.Generation))
 	}
 	function.Status.Replicas = *statefulSet.Spec.Replicas
-	function.Status.Conditions[v1alpha1.StatefulSet] = condition
 
 	return nil
 }
 
-func (r *FunctionReconciler) ApplyFunctionStatefulSet(ctx context.Context, function *v1alpha1.Function) error {
-	desiredStatefulSet := spec.MakeFunctionStatefulSet(function)
+func (r *FunctionReconciler) ApplyFunctionStatefulSet(ctx context.Context, function *v1alpha2.Function) error {
+	if err := applyPulsarConnection(ctx, r.Client, spec.MakeFunctionObjectMeta(function), function.Spec.Pulsar); err != nil {
+		r.Log.Error(err, "error copying the pulsar connection", "namespace", function.Namespace, "name", function.Name)
+		r.Recorder.Eventf(function, corev1.EventTypeWarning, EventReasonFailedUpdate,
+			"Failed to copy PulsarConnection %s: %v", function.Spec.Pulsar.ConnectionRef.Name, err)
+		return err
+	}
+	// the details are applied before the statefulset, so that the pods started by the update read them
+	detailsConfigMap := spec.MakeFunctionDetailsConfigMap(function)
+	if err := applyConfigMap(ctx, r.Client, detailsConfigMap); err != nil {
+		r.Log.Error(err, "error create or update details configmap", "namespace", function.Namespace, "name", detailsConfigMap.Name)
+		r.Recorder.Eventf(function, corev1.EventTypeWarning, EventReasonFailedUpdate,
+			"Failed to create or update ConfigMap %s: %v", detailsConfigMap.Name, err)
+		return err
+	}
+	desiredStatefulSet, err := r.makeFunctionStatefulSet(ctx, function)
+	if err != nil {
+		return err
+	}
 	desiredStatefulSetSpec := desiredStatefulSet.Spec
 	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, desiredStatefulSet, func() error {
 		// function statefulset mutate logic
@@ -92,20 +115,34 @@ func (r *FunctionReconciler) ApplyFunctionStatefulSet(ctx context.Context, funct
 		return nil
 	}); err != nil {
 		r.Log.Error(err, "error create or update statefulSet workload", "namespace", desiredStatefulSet.Namespace, "name", desiredStatefulSet.Name)
+		r.Recorder.Eventf(function, corev1.EventTypeWarning, EventReasonFailedUpdate,
+			"Failed to create or update StatefulSet %s: %v", desiredStatefulSet.Name, err)
 		return err
 	}
+
+	// the statefulset is updated on every reconciliation, the events are only recorded for observed changes
+	condition := v1alpha2.FindCondition(function.Status.Conditions, v1alpha2.StatefulSetReady)
+	if condition != nil {
+		switch condition.Reason {
+		case v1alpha2.ReasonCreating:
+			r.Recorder.Eventf(function, corev1.EventTypeNormal, EventReasonCreated,
+				"Created StatefulSet %s", desiredStatefulSet.Name)
+		case v1alpha2.ReasonUpdating:
+			statefulSetDriftUpdates.WithLabelValues(kindFunction).Inc()
+			r.Recorder.Eventf(function, corev1.EventTypeNormal, EventReasonUpdated,
+				"Updated StatefulSet %s", desiredStatefulSet.Name)
+		}
+	}
 	return nil
 }
 
 func (r *FunctionReconciler) ObserveFunctionService(ctx context.Context, req ctrl.Request,
-	function *v1alpha1.Function) error {
-	condition, ok := function.Status.Conditions[v1alpha1.Service]
-	if !ok {
-		function.Status.Conditions[v1alpha1.Service] = v1alpha1.ResourceCondition{
-			Condition: v1alpha1.ServiceReady,
-			Status:    metav1.ConditionFalse,
-			Action:    v1alpha1.Create,
-		}
+	function *v1alpha2.Function) error {
+	condition := v1alpha2.FindCondition(function.Status.Conditions, v1alpha2.ServiceReady)
+	if condition == nil {
+		v1alpha2.SetCondition(&function.Status.Conditions, v1alpha2.CreateCondition(
+			v1alpha2.ServiceReady, metav1.ConditionFalse, v1alpha2.ReasonCreating,
+			"service is not created", function.Generation))
 		return nil
 	}
 
@@ -125,28 +162,30 @@ func (r *FunctionReconciler) ObserveFunctionService(ctx context.Context, req ctr
 		return err
 	}
 
-	condition.Action = v1alpha1.NoAction
-	condition.Status = metav1.ConditionTrue
-	function.Status.Conditions[v1alpha1.Service] = condition
+	v1alpha2.SetCondition(&function.Status.Conditions, v1alpha2.CreateCondition(
+		v1alpha2.ServiceReady, metav1.ConditionTrue, v1alpha2.ReasonReconciled, "", function.Generation))
 	return nil
 }
 
 func (r *FunctionReconciler) ApplyFunctionService(ctx context.Context, req ctrl.Request,
-	function *v1alpha1.Function) error {
-	condition := function.Status.Conditions[v1alpha1.Service]
+	function *v1alpha2.Function) error {
+	condition := v1alpha2.FindCondition(function.Status.Conditions, v1alpha2.ServiceReady)
 
-	if condition.Status == metav1.ConditionTrue {
+	if condition == nil || condition.Status == metav1.ConditionTrue {
 		return nil
 	}
 
-	switch condition.Action {
-	case v1alpha1.Create:
+	switch condition.Reason {
+	case v1alpha2.ReasonCreating:
 		svc := spec.MakeFunctionService(function)
 		if err := r.Create(ctx, svc); err != nil {
 			r.Log.Error(err, "failed to expose service for function", "name", function.Name)
+			r.Recorder.Eventf(function, corev1.EventTypeWarning, EventReasonFailedCreate,
+				"Failed to create Service %s: %v", svc.Name, err)
 			return err
 		}
-	case v1alpha1.Wait:
+		r.Recorder.Eventf(function, corev1.EventTypeNormal, EventReasonCreated, "Created Service %s", svc.Name)
+	case v1alpha2.ReasonWaiting:
 		// do nothing
 	}
 
@@ -154,19 +193,18 @@ func (r *FunctionReconciler) ApplyFunctionService(ctx context.Context, req ctrl.
 }
 
 func (r *FunctionReconciler) ObserveFunctionHPA(ctx context.Context, req ctrl.Request,
-	function *v1alpha1.Function) error {
-	if function.Spec.MaxReplicas == nil {
-		// HPA not enabled, skip further action
+	function *v1alpha2.Function) error {
+	if function.Spec.MaxReplicas == nil || function.Spec.Suspend {
+		// HPA not enabled or removed while suspended, skip further action
+		v1alpha2.RemoveCondition(&function.Status.Conditions, v1alpha2.HPAReady)
 		return nil
 	}
 
-	condition, ok := function.Status.Conditions[v1alpha1.HPA]
-	if !ok {
-		function.Status.Conditions[v1alpha1.HPA] = v1alpha1.ResourceCondition{
-			Condition: v1alpha1.HPAReady,
-			Status:    metav1.ConditionFalse,
-			Action:    v1alpha1.Create,
-		}
+	condition := v1alpha2.FindCondition(function.Status.Conditions, v1alpha2.HPAReady)
+	if condition == nil {
+		v1alpha2.SetCondition(&function.Status.Conditions, v1alpha2.CreateCondition(
+			v1alpha2.HPAReady, metav1.ConditionFalse, v1alpha2.ReasonCreating,
+			"hpa is not created", function.Generation))
 		return nil
 	}
 
@@ -185,44 +223,54 @@ func (r *FunctionReconciler) ObserveFunctionHPA(ctx context.Context, req ctrl.Re
 		return err
 	}
 
+	// the HPA takes the metrics of the builtin rules, or the default ones when no metrics are set
+	desired := spec.MakeFunctionHPA(function)
 	if hpa.Spec.MaxReplicas != *function.Spec.MaxReplicas ||
-		!reflect.DeepEqual(hpa.Spec.Metrics, function.Spec.Pod.AutoScalingMetrics) ||
+		!reflect.DeepEqual(hpa.Spec.Metrics, desired.Spec.Metrics) ||
 		(function.Spec.Pod.AutoScalingBehavior != nil && hpa.Spec.Behavior == nil) ||
 		(function.Spec.Pod.AutoScalingBehavior != nil && hpa.Spec.Behavior != nil &&
 			!reflect.DeepEqual(*hpa.Spec.Behavior, *function.Spec.Pod.AutoScalingBehavior)) {
-		condition.Status = metav1.ConditionFalse
-		condition.Action = v1alpha1.Update
-		function.Status.Conditions[v1alpha1.HPA] = condition
+		v1alpha2.SetCondition(&function.Status.Conditions, v1alpha2.CreateCondition(
+			v1alpha2.HPAReady, metav1.ConditionFalse, v1alpha2.ReasonUpdating,
+			"hpa is outdated", function.Generation))
 		return nil
 	}
 
-	condition.Action = v1alpha1.NoAction
-	condition.Status = metav1.ConditionTrue
-	function.Status.Conditions[v1alpha1.HPA] = condition
+	v1alpha2.SetCondition(&function.Status.Conditions, v1alpha2.CreateCondition(
+		v1alpha2.HPAReady, metav1.ConditionTrue, v1alpha2.ReasonReconciled, "", function.Generation))
 	return nil
 }
 
 func (r *FunctionReconciler) ApplyFunctionHPA(ctx context.Context, req ctrl.Request,
-	function *v1alpha1.Function) error {
+	function *v1alpha2.Function) error {
 	if function.Spec.MaxReplicas == nil {
 		// HPA not enabled, skip further action
 		return nil
 	}
 
-	condition := function.Status.Conditions[v1alpha1.HPA]
+	if function.Spec.Suspend {
+		// the HPA would scale the suspended statefulset up again, it is recreated once resumed
+		return r.deleteHPA(ctx, function)
+	}
 
-	if condition.Status == metav1.ConditionTrue {
+	condition := v1alpha2.FindCondition(function.Status.Conditions, v1alpha2.HPAReady)
+
+	if condition == nil || condition.Status == metav1.ConditionTrue {
 		return nil
 	}
 
-	switch condition.Action {
-	case v1alpha1.Create:
+	switch condition.Reason {
+	case v1alpha2.ReasonCreating:
 		hpa := spec.MakeFunctionHPA(function)
 		if err := r.Create(ctx, hpa); err != nil {
 			r.Log.Error(err, "failed to create pod autoscaler for function", "name", function.Name)
+			r.Recorder.Eventf(function, corev1.EventTypeWarning, EventReasonFailedCreate,
+				"Failed to create HorizontalPodAutoscaler %s: %v", hpa.Name, err)
 			return err
 		}
-	case v1alpha1.Update:
+		r.Recorder.Eventf(function, corev1.EventTypeNormal, EventReasonCreated,
+			"Created HorizontalPodAutoscaler %s", hpa.Name)
+	case v1alpha2.ReasonUpdating:
 		hpa := &autov2beta2.HorizontalPodAutoscaler{}
 		err := r.Get(ctx, types.NamespacedName{Namespace: function.Namespace,
 			Name: spec.MakeFunctionObjectMeta(function).Name}, hpa)
@@ -233,25 +281,81 @@ func (r *FunctionReconciler) ApplyFunctionHPA(ctx context.Context, req ctrl.Requ
 		if hpa.Spec.MaxReplicas != *function.Spec.MaxReplicas {
 			hpa.Spec.MaxReplicas = *function.Spec.MaxReplicas
 		}
-		if len(function.Spec.Pod.AutoScalingMetrics) > 0 && !reflect.DeepEqual(hpa.Spec.Metrics, function.Spec.Pod.AutoScalingMetrics) {
-			hpa.Spec.Metrics = function.Spec.Pod.AutoScalingMetrics
+		if metrics := spec.MakeFunctionHPA(function).Spec.Metrics; !reflect.DeepEqual(hpa.Spec.Metrics, metrics) {
+			hpa.Spec.Metrics = metrics
 		}
 		if function.Spec.Pod.AutoScalingBehavior != nil {
 			hpa.Spec.Behavior = function.Spec.Pod.AutoScalingBehavior
 		}
-		if len(function.Spec.Pod.BuiltinAutoscaler) > 0 {
-			metrics := spec.MakeMetricsFromBuiltinHPARules(function.Spec.Pod.BuiltinAutoscaler)
-			if !reflect.DeepEqual(hpa.Spec.Metrics, metrics) {
-				hpa.Spec.Metrics = metrics
-			}
-		}
 		if err := r.Update(ctx, hpa); err != nil {
 			r.Log.Error(err, "failed to update pod autoscaler for function", "name", function.Name)
+			r.Recorder.Eventf(function, corev1.EventTypeWarning, EventReasonFailedUpdate,
+				"Failed to update HorizontalPodAutoscaler %s: %v", hpa.Name, err)
 			return err
 		}
-	case v1alpha1.Wait, v1alpha1.NoAction:
+		r.Recorder.Eventf(function, corev1.EventTypeNormal, EventReasonUpdated,
+			"Updated HorizontalPodAutoscaler %s", hpa.Name)
+	case v1alpha2.ReasonWaiting, v1alpha2.ReasonReconciled:
 		// do nothing
 	}
 
 	return nil
 }
+
+// deleteHPA deletes the HPA of the function if it exists
+func (r *FunctionReconciler) deleteHPA(ctx context.Context, function *v1alpha2.Function) error {
+	objectMeta := spec.MakeFunctionObjectMeta(function)
+	deleted, err := deleteHPA(ctx, r.Client, objectMeta)
+	if err != nil {
+		r.Log.Error(err, "failed to delete pod autoscaler for function", "name", function.Name)
+		r.Recorder.Eventf(function, corev1.EventTypeWarning, EventReasonFailedDelete,
+			"Failed to delete HorizontalPodAutoscaler %s: %v", objectMeta.Name, err)
+		return err
+	}
+	if deleted {
+		r.Recorder.Eventf(function, corev1.EventTypeNormal, EventReasonDeleted,
+			"Deleted HorizontalPodAutoscaler %s", objectMeta.Name)
+	}
+	return nil
+}
+
+func (r *FunctionReconciler) ObserveFunctionMonitor(ctx context.Context, function *v1alpha2.Function) error {
+	desired, err := spec.MakeFunctionMonitor(function)
+	if err != nil {
+		return err
+	}
+	return observeMonitor(ctx, r.Client, types.NamespacedName{Namespace: function.Namespace,
+		Name: spec.MakeFunctionObjectMeta(function).Name}, desired, &function.Status.Conditions, function.Generation)
+}
+
+func (r *FunctionReconciler) ApplyFunctionMonitor(ctx context.Context, function *v1alpha2.Function) error {
+	desired, err := spec.MakeFunctionMonitor(function)
+	if err != nil {
+		return err
+	}
+	err = applyMonitor(ctx, r.Client, r.Recorder, function, types.NamespacedName{Namespace: function.Namespace,
+		Name: spec.MakeFunctionObjectMeta(function).Name}, desired, function.Status.Conditions)
+	if err != nil {
+		r.Log.Error(err, "failed to apply monitor for function", "name", function.Name)
+	}
+	return err
+}
+
+// makeFunctionStatefulSet generates the statefulset of the function, with the connection it refers to resolved
+// and the hash of the objects read by its pods in the pod template
+func (r *FunctionReconciler) makeFunctionStatefulSet(ctx context.Context, function *v1alpha2.Function) (*appsv1.StatefulSet, error) {
+	messaging, err := resolvePulsarMessaging(ctx, r.Client, function.Namespace, spec.MakeFunctionObjectMeta(function).Name,
+		function.Spec.Pulsar)
+	if err != nil {
+		return nil, err
+	}
+	resolved := function.DeepCopy()
+	resolved.Spec.Pulsar = messaging
+	configMaps, secrets := spec.MakeFunctionReferences(resolved)
+	hash, err := getConfigHash(ctx, r.Client, function.Namespace, configMaps, secrets)
+	if err != nil {
+		return nil, err
+	}
+	setConfigHash(&resolved.Spec.Pod, hash)
+	return spec.MakeFunctionStatefulSet(resolved), nil
+}
@@ -21,13 +21,15 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
-	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
 	"github.com/streamnative/function-mesh/controllers/spec"
 	appsv1 "k8s.io/api/apps/v1"
 	autov2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -36,8 +38,9 @@ import (
 // FunctionReconciler reconciles a Function object
 type FunctionReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=compute.functionmesh.io,resources=functions,verbs=get;list;watch;create;update;patch;delete
@@ -46,13 +49,14 @@ type FunctionReconciler struct {
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 func (r *FunctionReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := context.Background()
 	_ = r.Log.WithValues("function", req.NamespacedName)
 
 	// your logic here
-	function := &v1alpha1.Function{}
+	function := &v1alpha2.Function{}
 	err := r.Get(ctx, req.NamespacedName, function)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -67,15 +71,31 @@ func (r *FunctionReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return reconcile.Result{}, nil
 	}
 
-	// initialize component status map
-	if function.Status.Conditions == nil {
-		function.Status.Conditions = make(map[v1alpha1.Component]v1alpha1.ResourceCondition)
+	// the pulsar resources of the function are cleaned up by the finalizer on deletion
+	deleted, result, err := reconcileFinalizer(ctx, r.Client, r.Log, r.Recorder, function, spec.MakeFunctionObjectMeta(function).Name,
+		makeCleanupResources(function, function.Spec.Pulsar, function.Spec.Runtime, function.Spec.CleanupSubscription, &function.Spec.Input, makeFunctionSubscriptionName(function)))
+	if deleted || err != nil {
+		return result, err
 	}
 
+	// the suspend setting and the idle policy decide the desired replicas of the statefulset,
+	// so they are observed first
+	observeSuspend(&function.Status.Conditions, function.Spec.Suspend, function.Generation)
+	idle, idleRequeueAfter := observeIdle(r.Log, r.Recorder, function, function.Spec.IdlePolicy, func() (int64, error) {
+		return getInputBacklog(ctx, r.Client, function.Namespace, function.Spec.Input, function.Spec.Pulsar,
+			makeFunctionSubscriptionName(function))
+	}, &function.Status.Conditions, function.Status.Idle, function.Generation)
+	function.Status.Idle = idle
+
 	err = r.ObserveFunctionStatefulSet(ctx, req, function)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	err = observePackageDownload(ctx, r.Client, r.Recorder, function, function.Namespace, spec.MakeFunctionLabels(function),
+		function.Spec.Runtime, &function.Status.Conditions, function.Generation)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
 	err = r.ObserveFunctionService(ctx, req, function)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -84,6 +104,19 @@ func (r *FunctionReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	err = r.ObserveFunctionMonitor(ctx, function)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// the runtime status of the instances is scraped periodically in the background
+	instances, requeueAfter := observeInstances(spec.MakeFunctionObjectMeta(function), function.Status.Replicas,
+		function.Status.Conditions, function.Status.Instances)
+	function.Status.Instances = instances
+
+	function.Status.ObservedGeneration = function.Generation
+	setAggregatedConditions(kindFunction, &function.Status.Conditions,
+		v1alpha2.ComponentConditions(function.Status.Conditions), function.Generation)
 
 	err = r.Status().Update(ctx, function)
 	if err != nil {
@@ -93,26 +126,62 @@ func (r *FunctionReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	err = r.ApplyFunctionStatefulSet(ctx, function)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, updateDegradedCondition(ctx, r.Client, function, &function.Status.Conditions, function.Generation, err)
 	}
 	err = r.ApplyFunctionService(ctx, req, function)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, updateDegradedCondition(ctx, r.Client, function, &function.Status.Conditions, function.Generation, err)
 	}
 	err = r.ApplyFunctionHPA(ctx, req, function)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, updateDegradedCondition(ctx, r.Client, function, &function.Status.Conditions, function.Generation, err)
+	}
+	err = r.ApplyFunctionMonitor(ctx, function)
+	if err != nil {
+		return reconcile.Result{}, updateDegradedCondition(ctx, r.Client, function, &function.Status.Conditions, function.Generation, err)
 	}
 
-	return ctrl.Result{}, nil
+	err = updateDegradedCondition(ctx, r.Client, function, &function.Status.Conditions, function.Generation, nil)
+	if err != nil {
+		r.Log.Error(err, "failed to update function status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: minRequeueAfter(requeueAfter, idleRequeueAfter)}, nil
 }
 
 func (r *FunctionReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Function{}).
+	blder := ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha2.Function{}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&corev1.Service{}).
 		Owns(&autov2beta2.HorizontalPodAutoscaler{}).
-		Owns(&corev1.Secret{}).
-		Complete(r)
+		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{})
+	// the pods are rolled when the objects they read change
+	blder, err := watchReferences(mgr, blder, r.Client, r.Log, &v1alpha2.Function{}, functionReferences, r.listFunctionRequests)
+	if err != nil {
+		return err
+	}
+	// the monitors are watched only when the Prometheus Operator is installed
+	return ownMonitors(blder, mgr.GetRESTMapper()).Complete(r)
+}
+
+func functionReferences(object runtime.Object) componentReferences {
+	function := object.(*v1alpha2.Function)
+	configMaps, secrets := spec.MakeFunctionReferences(function)
+	return makeComponentReferences(configMaps, secrets, function.Spec.Pulsar)
+}
+
+func (r *FunctionReconciler) listFunctionRequests(ctx context.Context, opts ...client.ListOption) ([]reconcile.Request, error) {
+	functions := &v1alpha2.FunctionList{}
+	if err := r.List(ctx, functions, opts...); err != nil {
+		return nil, err
+	}
+	requests := make([]reconcile.Request, 0, len(functions.Items))
+	for i := range functions.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: functions.Items[i].Namespace, Name: functions.Items[i].Name}})
+	}
+	return requests, nil
 }
@@ -36,6 +36,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
 	"github.com/streamnative/function-mesh/controllers/spec"
 	v1 "k8s.io/api/core/v1"
 )
@@ -180,7 +181,7 @@ var _ = Describe("Function Controller (Stateful Function)", func() {
 	})
 })
 
-func createFunction(function *v1alpha1.Function) {
+func createFunction(function *v1alpha2.Function) {
 
 	It("Function should be created", func() {
 		Eventually(func() bool {
@@ -248,7 +249,7 @@ func createFunction(function *v1alpha1.Function) {
 			if len(function.Spec.Pod.BuiltinAutoscaler) > 0 {
 				Expect(len(hpa.Spec.Metrics)).Should(Equal(len(function.Spec.Pod.BuiltinAutoscaler)))
 				for _, rule := range function.Spec.Pod.BuiltinAutoscaler {
-					autoscaler := spec.GetBuiltinAutoScaler(rule)
+					autoscaler := spec.GetBuiltinAutoScaler(rule, spec.MakeFunctionLabels(function))
 					Expect(autoscaler).Should(Not(BeNil()))
 					Expect(hpa.Spec.Metrics).Should(ContainElement(autoscaler.Metrics()[0]))
 				}
@@ -21,15 +21,17 @@ import (
 	"context"
 
 	"github.com/streamnative/function-mesh/api/v1alpha1"
-	"github.com/streamnative/function-mesh/controllers/spec"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 func (r *FunctionMeshReconciler) ObserveFunctionMesh(ctx context.Context, req ctrl.Request,
-	mesh *v1alpha1.FunctionMesh) error {
+	mesh *v1alpha2.FunctionMesh) error {
 	// TODO update deleted function status
 	if err := r.observeFunctions(ctx, mesh); err != nil {
 		return err
@@ -46,7 +48,7 @@ func (r *FunctionMeshReconciler) ObserveFunctionMesh(ctx context.Context, req ct
 	return nil
 }
 
-func (r *FunctionMeshReconciler) observeFunctions(ctx context.Context, mesh *v1alpha1.FunctionMesh) error {
+func (r *FunctionMeshReconciler) observeFunctions(ctx context.Context, mesh *v1alpha2.FunctionMesh) error {
 	orphanedFunctions := map[string]bool{}
 
 	if len(mesh.Status.FunctionConditions) > 0 {
@@ -59,17 +61,15 @@ func (r *FunctionMeshReconciler) observeFunctions(ctx context.Context, mesh *v1a
 		delete(orphanedFunctions, functionSpec.Name)
 
 		// present the original name to use in Status, but underlying use the complete-name
-		condition, ok := mesh.Status.FunctionConditions[functionSpec.Name]
+		_, ok := mesh.Status.FunctionConditions[functionSpec.Name]
 		if !ok {
-			mesh.Status.FunctionConditions[functionSpec.Name] = v1alpha1.ResourceCondition{
-				Condition: v1alpha1.FunctionReady,
-				Status:    metav1.ConditionFalse,
-				Action:    v1alpha1.Create,
-			}
+			mesh.Status.FunctionConditions[functionSpec.Name] = v1alpha2.CreateCondition(
+				v1alpha2.FunctionReady, metav1.ConditionFalse, v1alpha2.ReasonCreating,
+				"function is not created", mesh.Generation)
 			continue
 		}
 
-		function := &v1alpha1.Function{}
+		function := &v1alpha2.Function{}
 		err := r.Get(ctx, types.NamespacedName{
 			Namespace: mesh.Namespace,
 			Name:      makeComponentName(mesh.Name, functionSpec.Name),
@@ -82,31 +82,30 @@ func (r *FunctionMeshReconciler) observeFunctions(ctx context.Context, mesh *v1a
 			return err
 		}
 
-		if function.Status.Conditions[v1alpha1.StatefulSet].Status == metav1.ConditionTrue &&
-			function.Status.Conditions[v1alpha1.Service].Status == metav1.ConditionTrue {
-			condition.Action = v1alpha1.NoAction
-			condition.Status = metav1.ConditionTrue
-			mesh.Status.FunctionConditions[functionSpec.Name] = condition
+		if function.Status.ObservedGeneration == function.Generation &&
+			v1alpha2.IsConditionTrue(function.Status.Conditions, v1alpha2.Ready) {
+			setMeshCondition(mesh.Status.FunctionConditions, functionSpec.Name, v1alpha2.CreateCondition(
+				v1alpha2.FunctionReady, metav1.ConditionTrue, v1alpha2.ReasonReconciled, "", mesh.Generation))
 		} else {
 			// function created but subcomponents not ready, we need to wait
-			condition.Action = v1alpha1.Wait
-			mesh.Status.FunctionConditions[functionSpec.Name] = condition
+			setMeshCondition(mesh.Status.FunctionConditions, functionSpec.Name, v1alpha2.CreateCondition(
+				v1alpha2.FunctionReady, metav1.ConditionFalse, v1alpha2.ReasonWaiting,
+				"function is not ready", mesh.Generation))
 		}
 	}
 
 	for functionName, isOrphaned := range orphanedFunctions {
 		if isOrphaned {
-			mesh.Status.FunctionConditions[functionName] = v1alpha1.CreateCondition(
-				v1alpha1.Orphaned,
-				metav1.ConditionTrue,
-				v1alpha1.Delete)
+			setMeshCondition(mesh.Status.FunctionConditions, functionName, v1alpha2.CreateCondition(
+				v1alpha2.Orphaned, metav1.ConditionTrue, v1alpha2.ReasonDeleting,
+				"function is removed from the mesh", mesh.Generation))
 		}
 	}
 
 	return nil
 }
 
-func (r *FunctionMeshReconciler) observeSources(ctx context.Context, mesh *v1alpha1.FunctionMesh) error {
+func (r *FunctionMeshReconciler) observeSources(ctx context.Context, mesh *v1alpha2.FunctionMesh) error {
 	orphanedSources := map[string]bool{}
 
 	if len(mesh.Status.SourceConditions) > 0 {
@@ -119,17 +118,15 @@ func (r *FunctionMeshReconciler) observeSources(ctx context.Context, mesh *v1alp
 		delete(orphanedSources, sourceSpec.Name)
 
 		// present the original name to use in Status, but underlying use the complete-name
-		condition, ok := mesh.Status.SourceConditions[sourceSpec.Name]
+		_, ok := mesh.Status.SourceConditions[sourceSpec.Name]
 		if !ok {
-			mesh.Status.SourceConditions[sourceSpec.Name] = v1alpha1.ResourceCondition{
-				Condition: v1alpha1.SourceReady,
-				Status:    metav1.ConditionFalse,
-				Action:    v1alpha1.Create,
-			}
+			mesh.Status.SourceConditions[sourceSpec.Name] = v1alpha2.CreateCondition(
+				v1alpha2.SourceReady, metav1.ConditionFalse, v1alpha2.ReasonCreating,
+				"source is not created", mesh.Generation)
 			continue
 		}
 
-		source := &v1alpha1.Source{}
+		source := &v1alpha2.Source{}
 		err := r.Get(ctx, types.NamespacedName{
 			Namespace: mesh.Namespace,
 			Name:      makeComponentName(mesh.Name, sourceSpec.Name),
@@ -142,30 +139,29 @@ func (r *FunctionMeshReconciler) observeSources(ctx context.Context, mesh *v1alp
 			return err
 		}
 
-		if source.Status.Conditions[v1alpha1.StatefulSet].Status == metav1.ConditionTrue &&
-			source.Status.Conditions[v1alpha1.Service].Status == metav1.ConditionTrue {
-			condition.Action = v1alpha1.NoAction
-			condition.Status = metav1.ConditionTrue
-			mesh.Status.SourceConditions[sourceSpec.Name] = condition
+		if source.Status.ObservedGeneration == source.Generation &&
+			v1alpha2.IsConditionTrue(source.Status.Conditions, v1alpha2.Ready) {
+			setMeshCondition(mesh.Status.SourceConditions, sourceSpec.Name, v1alpha2.CreateCondition(
+				v1alpha2.SourceReady, metav1.ConditionTrue, v1alpha2.ReasonReconciled, "", mesh.Generation))
 		} else {
-			// function created but subcomponents not ready, we need to wait
-			condition.Action = v1alpha1.Wait
-			mesh.Status.SourceConditions[sourceSpec.Name] = condition
+			// source created but subcomponents not ready, we need to wait
+			setMeshCondition(mesh.Status.SourceConditions, sourceSpec.Name, v1alpha2.CreateCondition(
+				v1alpha2.SourceReady, metav1.ConditionFalse, v1alpha2.ReasonWaiting,
+				"source is not ready", mesh.Generation))
 		}
 	}
 
 	for sourceName, isOrphaned := range orphanedSources {
 		if isOrphaned {
-			mesh.Status.SourceConditions[sourceName] = v1alpha1.CreateCondition(
-				v1alpha1.Orphaned,
-				metav1.ConditionTrue,
-				v1alpha1.Delete)
+			setMeshCondition(mesh.Status.SourceConditions, sourceName, v1alpha2.CreateCondition(
+				v1alpha2.Orphaned, metav1.ConditionTrue, v1alpha2.ReasonDeleting,
+				"source is removed from the mesh", mesh.Generation))
 		}
 	}
 	return nil
 }
 
-func (r *FunctionMeshReconciler) observeSinks(ctx context.Context, mesh *v1alpha1.FunctionMesh) error {
+func (r *FunctionMeshReconciler) observeSinks(ctx context.Context, mesh *v1alpha2.FunctionMesh) error {
 	orphanedSinks := map[string]bool{}
 
 	if len(mesh.Status.SinkConditions) > 0 {
@@ -178,17 +174,15 @@ func (r *FunctionMeshReconciler) observeSinks(ctx context.Context, mesh *v1alpha
 		delete(orphanedSinks, sinkSpec.Name)
 
 		// present the original name to use in Status, but underlying use the complete-name
-		condition, ok := mesh.Status.SinkConditions[sinkSpec.Name]
+		_, ok := mesh.Status.SinkConditions[sinkSpec.Name]
 		if !ok {
-			mesh.Status.SinkConditions[sinkSpec.Name] = v1alpha1.ResourceCondition{
-				Condition: v1alpha1.SinkReady,
-				Status:    metav1.ConditionFalse,
-				Action:    v1alpha1.Create,
-			}
+			mesh.Status.SinkConditions[sinkSpec.Name] = v1alpha2.CreateCondition(
+				v1alpha2.SinkReady, metav1.ConditionFalse, v1alpha2.ReasonCreating,
+				"sink is not created", mesh.Generation)
 			continue
 		}
 
-		sink := &v1alpha1.Sink{}
+		sink := &v1alpha2.Sink{}
 		err := r.Get(ctx, types.NamespacedName{
 			Namespace: mesh.Namespace,
 			Name:      makeComponentName(mesh.Name, sinkSpec.Name),
@@ -201,24 +195,23 @@ func (r *FunctionMeshReconciler) observeSinks(ctx context.Context, mesh *v1alpha
 			return err
 		}
 
-		if sink.Status.Conditions[v1alpha1.StatefulSet].Status == metav1.ConditionTrue &&
-			sink.Status.Conditions[v1alpha1.Service].Status == metav1.ConditionTrue {
-			condition.Action = v1alpha1.NoAction
-			condition.Status = metav1.ConditionTrue
-			mesh.Status.SinkConditions[sinkSpec.Name] = condition
+		if sink.Status.ObservedGeneration == sink.Generation &&
+			v1alpha2.IsConditionTrue(sink.Status.Conditions, v1alpha2.Ready) {
+			setMeshCondition(mesh.Status.SinkConditions, sinkSpec.Name, v1alpha2.CreateCondition(
+				v1alpha2.SinkReady, metav1.ConditionTrue, v1alpha2.ReasonReconciled, "", mesh.Generation))
 		} else {
-			// function created but subcomponents not ready, we need to wait
-			condition.Action = v1alpha1.Wait
-			mesh.Status.SinkConditions[sinkSpec.Name] = condition
+			// sink created but subcomponents not ready, we need to wait
+			setMeshCondition(mesh.Status.SinkConditions, sinkSpec.Name, v1alpha2.CreateCondition(
+				v1alpha2.SinkReady, metav1.ConditionFalse, v1alpha2.ReasonWaiting,
+				"sink is not ready", mesh.Generation))
 		}
 	}
 
 	for sinkName, isOrphaned := range orphanedSinks {
 		if isOrphaned {
-			mesh.Status.SinkConditions[sinkName] = v1alpha1.CreateCondition(
-				v1alpha1.Orphaned,
-				metav1.ConditionTrue,
-				v1alpha1.Delete)
+			setMeshCondition(mesh.Status.SinkConditions, sinkName, v1alpha2.CreateCondition(
+				v1alpha2.Orphaned, metav1.ConditionTrue, v1alpha2.ReasonDeleting,
+				"sink is removed from the mesh", mesh.Generation))
 		}
 	}
 
@@ -226,7 +219,7 @@ func (r *FunctionMeshReconciler) observeSinks(ctx context.Context, mesh *v1alpha
 }
 
 func (r *FunctionMeshReconciler) UpdateFunctionMesh(ctx context.Context, req ctrl.Request,
-	mesh *v1alpha1.FunctionMesh) error {
+	mesh *v1alpha2.FunctionMesh) error {
 	defer func() {
 		err := r.Status().Update(ctx, mesh)
 		if err != nil {
@@ -234,146 +227,242 @@ func (r *FunctionMeshReconciler) UpdateFunctionMesh(ctx context.Context, req ctr
 		}
 	}()
 
-	for _, functionSpec := range mesh.Spec.Functions {
-		condition := mesh.Status.FunctionConditions[functionSpec.Name]
-		function := spec.MakeFunctionComponent(makeComponentName(mesh.Name, functionSpec.Name), mesh, &functionSpec)
-		if err := r.CreateOrUpdateFunction(ctx, function, function.Spec); err != nil {
-			r.Log.Error(err, "failed to handle function", "name", functionSpec.Name, "action", condition.Action)
-			return err
-		}
+	if err := r.rolloutComponents(ctx, mesh); err != nil {
+		return err
 	}
 
-	for _, sourceSpec := range mesh.Spec.Sources {
-		condition := mesh.Status.SourceConditions[sourceSpec.Name]
-		source := spec.MakeSourceComponent(makeComponentName(mesh.Name, sourceSpec.Name), mesh, &sourceSpec)
-		if err := r.CreateOrUpdateSource(ctx, source, source.Spec); err != nil {
-			r.Log.Error(err, "failed to handle soure", "name", sourceSpec.Name, "action", condition.Action)
-			return err
+	// handle logic for cleaning up orphaned subcomponents
+	if meshRolloutStrategy(mesh) == v1alpha1.OrderedMeshRollout {
+		// the removed components are not in the topic graph of the spec, the producers are deleted
+		// before the consumers one kind at a time, see OrderedMeshRollout
+		for _, deleteOrphans := range []func(context.Context, *v1alpha2.FunctionMesh) (bool, error){
+			r.deleteOrphanedSources, r.deleteOrphanedFunctions, r.deleteOrphanedSinks,
+		} {
+			if pending, err := deleteOrphans(ctx, mesh); err != nil || pending {
+				return err
+			}
 		}
+		return nil
 	}
 
-	for _, sinkSpec := range mesh.Spec.Sinks {
-		condition := mesh.Status.SinkConditions[sinkSpec.Name]
-		sink := spec.MakeSinkComponent(makeComponentName(mesh.Name, sinkSpec.Name), mesh, &sinkSpec)
-		if err := r.CreateOrUpdateSink(ctx, sink, sink.Spec); err != nil {
-			r.Log.Error(err, "failed to handle sink", "name", sinkSpec.Name, "action", condition.Action)
-			return err
-		}
+	if _, err := r.deleteOrphanedFunctions(ctx, mesh); err != nil {
+		return err
 	}
+	if _, err := r.deleteOrphanedSources(ctx, mesh); err != nil {
+		return err
+	}
+	_, err := r.deleteOrphanedSinks(ctx, mesh)
+	return err
+}
 
-	// handle logic for cleaning up orphaned subcomponents
-	if len(mesh.Spec.Functions) != len(mesh.Status.FunctionConditions) {
-		for functionName, functionCondition := range mesh.Status.FunctionConditions {
-			if functionCondition.Condition == v1alpha1.Orphaned {
-				// clean up the orphaned functions
-				function := &v1alpha1.Function{}
-				if err := r.Get(ctx, types.NamespacedName{
-					Namespace: mesh.Namespace,
-					Name:      makeComponentName(mesh.Name, functionName),
-				}, function); err != nil {
-					if errors.IsNotFound(err) {
-						delete(mesh.Status.FunctionConditions, functionName)
-						continue
-					}
-					r.Log.Error(err, "failed to get orphaned function", "name", functionName)
-					return err
-				}
-				if err := r.Delete(ctx, function); err != nil && !errors.IsNotFound(err) {
-					r.Log.Error(err, "failed to delete orphaned function", "name", functionName)
-					return err
-				}
+// deleteOrphanedFunctions deletes the functions removed from the mesh, it returns true while some of them still exist
+func (r *FunctionMeshReconciler) deleteOrphanedFunctions(ctx context.Context, mesh *v1alpha2.FunctionMesh) (bool, error) {
+	if len(mesh.Spec.Functions) == len(mesh.Status.FunctionConditions) {
+		return false, nil
+	}
+
+	pending := false
+	for functionName, functionCondition := range mesh.Status.FunctionConditions {
+		if functionCondition.Type != v1alpha2.Orphaned {
+			continue
+		}
+		// clean up the orphaned functions
+		function := &v1alpha2.Function{}
+		if err := r.Get(ctx, types.NamespacedName{
+			Namespace: mesh.Namespace,
+			Name:      makeComponentName(mesh.Name, functionName),
+		}, function); err != nil {
+			if errors.IsNotFound(err) {
 				delete(mesh.Status.FunctionConditions, functionName)
+				continue
 			}
+			r.Log.Error(err, "failed to get orphaned function", "name", functionName)
+			return false, err
+		}
+		// the condition is kept until the function is gone, which may wait for its finalizers
+		pending = true
+		if !function.DeletionTimestamp.IsZero() {
+			continue
 		}
+		if err := r.Delete(ctx, function); err != nil && !errors.IsNotFound(err) {
+			r.Log.Error(err, "failed to delete orphaned function", "name", functionName)
+			orphanDeletions.WithLabelValues(kindFunction, "failure").Inc()
+			r.Recorder.Eventf(mesh, corev1.EventTypeWarning, EventReasonFailedDelete,
+				"Failed to delete orphaned Function %s: %v", function.Name, err)
+			return false, err
+		}
+		orphanDeletions.WithLabelValues(kindFunction, "success").Inc()
+		r.Recorder.Eventf(mesh, corev1.EventTypeNormal, EventReasonDeleted,
+			"Deleted orphaned Function %s", function.Name)
+	}
+	return pending, nil
+}
+
+// deleteOrphanedSources deletes the sources removed from the mesh, it returns true while some of them still exist
+func (r *FunctionMeshReconciler) deleteOrphanedSources(ctx context.Context, mesh *v1alpha2.FunctionMesh) (bool, error) {
+	if len(mesh.Spec.Sources) == len(mesh.Status.SourceConditions) {
+		return false, nil
 	}
 
-	if len(mesh.Spec.Sources) != len(mesh.Status.SourceConditions) {
-		for sourceName, sourceCondition := range mesh.Status.SourceConditions {
-			if sourceCondition.Condition == v1alpha1.Orphaned {
-				// clean up the orphaned sources
-				source := &v1alpha1.Source{}
-				if err := r.Get(ctx, types.NamespacedName{
-					Namespace: mesh.Namespace,
-					Name:      makeComponentName(mesh.Name, sourceName),
-				}, source); err != nil {
-					if errors.IsNotFound(err) {
-						delete(mesh.Status.SourceConditions, sourceName)
-						continue
-					}
-					r.Log.Error(err, "failed to get orphaned source", "name", sourceName)
-					return err
-				}
-				if err := r.Delete(ctx, source); err != nil && !errors.IsNotFound(err) {
-					r.Log.Error(err, "failed to delete orphaned source", "name", sourceName)
-					return err
-				}
+	pending := false
+	for sourceName, sourceCondition := range mesh.Status.SourceConditions {
+		if sourceCondition.Type != v1alpha2.Orphaned {
+			continue
+		}
+		// clean up the orphaned sources
+		source := &v1alpha2.Source{}
+		if err := r.Get(ctx, types.NamespacedName{
+			Namespace: mesh.Namespace,
+			Name:      makeComponentName(mesh.Name, sourceName),
+		}, source); err != nil {
+			if errors.IsNotFound(err) {
 				delete(mesh.Status.SourceConditions, sourceName)
+				continue
 			}
+			r.Log.Error(err, "failed to get orphaned source", "name", sourceName)
+			return false, err
 		}
+		// the condition is kept until the source is gone, which may wait for its finalizers
+		pending = true
+		if !source.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := r.Delete(ctx, source); err != nil && !errors.IsNotFound(err) {
+			r.Log.Error(err, "failed to delete orphaned source", "name", sourceName)
+			orphanDeletions.WithLabelValues(kindSource, "failure").Inc()
+			r.Recorder.Eventf(mesh, corev1.EventTypeWarning, EventReasonFailedDelete,
+				"Failed to delete orphaned Source %s: %v", source.Name, err)
+			return false, err
+		}
+		orphanDeletions.WithLabelValues(kindSource, "success").Inc()
+		r.Recorder.Eventf(mesh, corev1.EventTypeNormal, EventReasonDeleted,
+			"Deleted orphaned Source %s", source.Name)
+	}
+	return pending, nil
+}
+
+// deleteOrphanedSinks deletes the sinks removed from the mesh, it returns true while some of them still exist
+func (r *FunctionMeshReconciler) deleteOrphanedSinks(ctx context.Context, mesh *v1alpha2.FunctionMesh) (bool, error) {
+	if len(mesh.Spec.Sinks) == len(mesh.Status.SinkConditions) {
+		return false, nil
 	}
 
-	if len(mesh.Spec.Sinks) != len(mesh.Status.SinkConditions) {
-		for sinkName, sinkCondition := range mesh.Status.SinkConditions {
-			if sinkCondition.Condition == v1alpha1.Orphaned {
-				// clean up the orphaned sinks
-				sink := &v1alpha1.Sink{}
-				if err := r.Get(ctx, types.NamespacedName{
-					Namespace: mesh.Namespace,
-					Name:      makeComponentName(mesh.Name, sinkName),
-				}, sink); err != nil {
-					if errors.IsNotFound(err) {
-						delete(mesh.Status.SinkConditions, sinkName)
-						continue
-					}
-					r.Log.Error(err, "failed to get orphaned sink", "name", sinkName)
-					return err
-				}
-				if err := r.Delete(ctx, sink); err != nil && !errors.IsNotFound(err) {
-					r.Log.Error(err, "failed to delete orphaned sink", "name", sinkName)
-					return err
-				}
+	pending := false
+	for sinkName, sinkCondition := range mesh.Status.SinkConditions {
+		if sinkCondition.Type != v1alpha2.Orphaned {
+			continue
+		}
+		// clean up the orphaned sinks
+		sink := &v1alpha2.Sink{}
+		if err := r.Get(ctx, types.NamespacedName{
+			Namespace: mesh.Namespace,
+			Name:      makeComponentName(mesh.Name, sinkName),
+		}, sink); err != nil {
+			if errors.IsNotFound(err) {
 				delete(mesh.Status.SinkConditions, sinkName)
+				continue
 			}
+			r.Log.Error(err, "failed to get orphaned sink", "name", sinkName)
+			return false, err
+		}
+		// the condition is kept until the sink is gone, which may wait for its finalizers
+		pending = true
+		if !sink.DeletionTimestamp.IsZero() {
+			continue
 		}
+		if err := r.Delete(ctx, sink); err != nil && !errors.IsNotFound(err) {
+			r.Log.Error(err, "failed to delete orphaned sink", "name", sinkName)
+			orphanDeletions.WithLabelValues(kindSink, "failure").Inc()
+			r.Recorder.Eventf(mesh, corev1.EventTypeWarning, EventReasonFailedDelete,
+				"Failed to delete orphaned Sink %s: %v", sink.Name, err)
+			return false, err
+		}
+		orphanDeletions.WithLabelValues(kindSink, "success").Inc()
+		r.Recorder.Eventf(mesh, corev1.EventTypeNormal, EventReasonDeleted,
+			"Deleted orphaned Sink %s", sink.Name)
 	}
+	return pending, nil
+}
 
-	return nil
+// propagateIdentityChangeAcknowledgement keeps the acknowledgement of the identity changes of the components
+// in sync with the mesh, the updates of the components are rejected by the webhooks otherwise
+func propagateIdentityChangeAcknowledgement(mesh *v1alpha2.FunctionMesh, obj metav1.Object) {
+	annotations := obj.GetAnnotations()
+	value, ok := mesh.Annotations[v1alpha1.AnnotationAcknowledgeIdentityChange]
+	if ok {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[v1alpha1.AnnotationAcknowledgeIdentityChange] = value
+	} else {
+		delete(annotations, v1alpha1.AnnotationAcknowledgeIdentityChange)
+	}
+	obj.SetAnnotations(annotations)
 }
 
-func (r *FunctionMeshReconciler) CreateOrUpdateFunction(ctx context.Context, function *v1alpha1.Function, functionSpec v1alpha1.FunctionSpec) error {
-	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, function, func() error {
+func (r *FunctionMeshReconciler) CreateOrUpdateFunction(ctx context.Context, mesh *v1alpha2.FunctionMesh, function *v1alpha2.Function, functionSpec v1alpha1.FunctionSpec) (controllerutil.OperationResult, error) {
+	result, err := ctrl.CreateOrUpdate(ctx, r.Client, function, func() error {
 		// function mutate logic
 		function.Spec = functionSpec
+		propagateIdentityChangeAcknowledgement(mesh, function)
 		return nil
-	}); err != nil {
+	})
+	if err != nil {
 		r.Log.Error(err, "error create or update function", "namespace", function.Namespace, "name", function.Name)
-		return err
+		r.Recorder.Eventf(mesh, corev1.EventTypeWarning, EventReasonFailedUpdate,
+			"Failed to create or update Function %s: %v", function.Name, err)
+		return result, err
 	}
-	return nil
+	if result == controllerutil.OperationResultCreated {
+		r.Recorder.Eventf(mesh, corev1.EventTypeNormal, EventReasonCreated, "Created Function %s", function.Name)
+	}
+	return result, nil
 }
 
-func (r *FunctionMeshReconciler) CreateOrUpdateSink(ctx context.Context, sink *v1alpha1.Sink, sinkSpec v1alpha1.SinkSpec) error {
-	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, sink, func() error {
+func (r *FunctionMeshReconciler) CreateOrUpdateSink(ctx context.Context, mesh *v1alpha2.FunctionMesh, sink *v1alpha2.Sink, sinkSpec v1alpha1.SinkSpec) (controllerutil.OperationResult, error) {
+	result, err := ctrl.CreateOrUpdate(ctx, r.Client, sink, func() error {
 		// sink mutate logic
 		sink.Spec = sinkSpec
+		propagateIdentityChangeAcknowledgement(mesh, sink)
 		return nil
-	}); err != nil {
+	})
+	if err != nil {
 		r.Log.Error(err, "error create or update sink", "namespace", sink.Namespace, "name", sink.Name)
-		return err
+		r.Recorder.Eventf(mesh, corev1.EventTypeWarning, EventReasonFailedUpdate,
+			"Failed to create or update Sink %s: %v", sink.Name, err)
+		return result, err
 	}
-	return nil
+	if result == controllerutil.OperationResultCreated {
+		r.Recorder.Eventf(mesh, corev1.EventTypeNormal, EventReasonCreated, "Created Sink %s", sink.Name)
+	}
+	return result, nil
 }
 
-func (r *FunctionMeshReconciler) CreateOrUpdateSource(ctx context.Context, source *v1alpha1.Source, sourceSpec v1alpha1.SourceSpec) error {
-	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, source, func() error {
+func (r *FunctionMeshReconciler) CreateOrUpdateSource(ctx context.Context, mesh *v1alpha2.FunctionMesh, source *v1alpha2.Source, sourceSpec v1alpha1.SourceSpec) (controllerutil.OperationResult, error) {
+	result, err := ctrl.CreateOrUpdate(ctx, r.Client, source, func() error {
 		// source mutate logic
 		source.Spec = sourceSpec
+		propagateIdentityChangeAcknowledgement(mesh, source)
 		return nil
-	}); err != nil {
+	})
+	if err != nil {
 		r.Log.Error(err, "error create or update source", "namespace", source.Namespace, "name", source.Name)
-		return err
+		r.Recorder.Eventf(mesh, corev1.EventTypeWarning, EventReasonFailedUpdate,
+			"Failed to create or update Source %s: %v", source.Name, err)
+		return result, err
 	}
-	return nil
+	if result == controllerutil.OperationResultCreated {
+		r.Recorder.Eventf(mesh, corev1.EventTypeNormal, EventReasonCreated, "Created Source %s", source.Name)
+	}
+	return result, nil
+}
+
+// setMeshCondition keeps the last transition time of the existing condition if the status is not changed
+func setMeshCondition(conditions map[string]v1alpha2.Condition, name string, condition v1alpha2.Condition) {
+	if existing, ok := conditions[name]; ok && existing.Type == condition.Type && existing.Status == condition.Status {
+		condition.LastTransitionTime = existing.LastTransitionTime
+	}
+	conditions[name] = condition
 }
 
 func makeComponentName(prefix, name string) string {
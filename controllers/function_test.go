@@ -0,0 +1,66 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestObserveFunctionHPABuiltinRules(t *testing.T) {
+	function := &v1alpha2.Function{
+		ObjectMeta: metav1.ObjectMeta{Name: "fn", Namespace: "default", Generation: 1},
+		Spec: v1alpha1.FunctionSpec{
+			Replicas:    pointer.Int32Ptr(1),
+			MaxReplicas: pointer.Int32Ptr(3),
+			Pod: v1alpha1.PodPolicy{
+				BuiltinAutoscaler: []v1alpha1.BuiltinHPARule{v1alpha1.AverageUtilizationCPUPercent80},
+			},
+		},
+	}
+	c := newFinalizerTestClient(t, spec.MakeFunctionHPA(function))
+	r := &FunctionReconciler{Client: c, Log: ctrl.Log.WithName("test"), Recorder: record.NewFakeRecorder(10)}
+	hpaReady := func() *v1alpha2.Condition {
+		return v1alpha2.FindCondition(function.Status.Conditions, v1alpha2.HPAReady)
+	}
+	function.Status.Conditions = []v1alpha2.Condition{v1alpha2.CreateCondition(v1alpha2.HPAReady,
+		metav1.ConditionFalse, v1alpha2.ReasonCreating, "", function.Generation)}
+
+	// the HPA with the metrics of the builtin rules is up to date
+	assert.NilError(t, r.ObserveFunctionHPA(context.TODO(), ctrl.Request{}, function))
+	assert.Equal(t, hpaReady().Status, metav1.ConditionTrue)
+
+	// a change of the rules updates the metrics of the HPA
+	function.Spec.Pod.BuiltinAutoscaler = []v1alpha1.BuiltinHPARule{v1alpha1.AverageUtilizationCPUPercent50}
+	function.Status.Conditions = []v1alpha2.Condition{v1alpha2.CreateCondition(v1alpha2.HPAReady,
+		metav1.ConditionFalse, v1alpha2.ReasonCreating, "", function.Generation)}
+	assert.NilError(t, r.ObserveFunctionHPA(context.TODO(), ctrl.Request{}, function))
+	assert.Equal(t, hpaReady().Reason, v1alpha2.ReasonUpdating)
+	assert.NilError(t, r.ApplyFunctionHPA(context.TODO(), ctrl.Request{}, function))
+	assert.NilError(t, r.ObserveFunctionHPA(context.TODO(), ctrl.Request{}, function))
+	assert.Equal(t, hpaReady().Status, metav1.ConditionTrue)
+}
@@ -24,8 +24,11 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -34,19 +37,21 @@ import (
 // FunctionMeshReconciler reconciles a FunctionMesh object
 type FunctionMeshReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=compute.functionmesh.io,resources=functionmeshes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=compute.functionmesh.io,resources=functionmeshes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 func (r *FunctionMeshReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := context.Background()
 	_ = r.Log.WithValues("functionMesh", req.NamespacedName)
 
 	// your logic here
-	mesh := &v1alpha1.FunctionMesh{}
+	mesh := &v1alpha2.FunctionMesh{}
 	err := r.Get(ctx, req.NamespacedName, mesh)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -62,18 +67,32 @@ func (r *FunctionMeshReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error
 		return reconcile.Result{}, nil
 	}
 
+	if deleting, err := r.reconcileOrderedDeletion(ctx, mesh); deleting || err != nil {
+		if err != nil {
+			r.Log.Error(err, "failed to delete the components of the mesh in order", "name", mesh.Name)
+		}
+		return reconcile.Result{}, err
+	}
+
 	// initialize component status map
 	if mesh.Status.FunctionConditions == nil {
-		mesh.Status.FunctionConditions = make(map[string]v1alpha1.ResourceCondition)
+		mesh.Status.FunctionConditions = make(map[string]v1alpha2.Condition)
 	}
 	if mesh.Status.SourceConditions == nil {
-		mesh.Status.SourceConditions = make(map[string]v1alpha1.ResourceCondition)
+		mesh.Status.SourceConditions = make(map[string]v1alpha2.Condition)
 	}
 	if mesh.Status.SinkConditions == nil {
-		mesh.Status.SinkConditions = make(map[string]v1alpha1.ResourceCondition)
+		mesh.Status.SinkConditions = make(map[string]v1alpha2.Condition)
 	}
 
-	// TODO validate function spec correctness such as no duplicated func name etc
+	// the specs of the components are validated by the webhooks, only the names are checked again
+	// since the components of a mesh created while the webhook is disabled would overwrite each other
+	if errs := v1alpha1.ValidateComponentNames(&mesh.Spec); len(errs) > 0 {
+		err = errs.ToAggregate()
+		r.Log.Error(err, "invalid function mesh", "name", mesh.Name)
+		r.Recorder.Event(mesh, corev1.EventTypeWarning, EventReasonInvalidSpec, err.Error())
+		return reconcile.Result{}, updateDegradedCondition(ctx, r.Client, mesh, &mesh.Status.Conditions, mesh.Generation, err)
+	}
 
 	// make observations
 	err = r.ObserveFunctionMesh(ctx, req, mesh)
@@ -81,6 +100,12 @@ func (r *FunctionMeshReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error
 		return reconcile.Result{}, err
 	}
 
+	topicGraphRequeue := r.ObserveTopicGraph(ctx, mesh)
+	mesh.Status.Topology = spec.MakeMeshTopology(mesh)
+	observeSuspend(&mesh.Status.Conditions, mesh.Spec.Suspend, mesh.Generation)
+	mesh.Status.ObservedGeneration = mesh.Generation
+	setAggregatedConditions(kindFunctionMesh, &mesh.Status.Conditions, meshComponentConditions(mesh), mesh.Generation)
+
 	err = r.Status().Update(ctx, mesh)
 	if err != nil {
 		r.Log.Error(err, "failed to update mesh status")
@@ -90,17 +115,40 @@ func (r *FunctionMeshReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error
 	// apply changes
 	err = r.UpdateFunctionMesh(ctx, req, mesh)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, updateDegradedCondition(ctx, r.Client, mesh, &mesh.Status.Conditions, mesh.Generation, err)
 	}
 
-	return ctrl.Result{}, nil
+	err = updateDegradedCondition(ctx, r.Client, mesh, &mesh.Status.Conditions, mesh.Generation, nil)
+	if err != nil {
+		r.Log.Error(err, "failed to update mesh status")
+		return ctrl.Result{}, err
+	}
+
+	// the external input topics are checked again without waiting for a change of the mesh
+	return ctrl.Result{RequeueAfter: topicGraphRequeue}, nil
+}
+
+// meshComponentConditions returns the conditions of all the functions, sources and sinks of the mesh
+func meshComponentConditions(mesh *v1alpha2.FunctionMesh) []v1alpha2.Condition {
+	conditions := make([]v1alpha2.Condition, 0,
+		len(mesh.Status.FunctionConditions)+len(mesh.Status.SourceConditions)+len(mesh.Status.SinkConditions))
+	for _, condition := range mesh.Status.FunctionConditions {
+		conditions = append(conditions, condition)
+	}
+	for _, condition := range mesh.Status.SourceConditions {
+		conditions = append(conditions, condition)
+	}
+	for _, condition := range mesh.Status.SinkConditions {
+		conditions = append(conditions, condition)
+	}
+	return conditions
 }
 
 func (r *FunctionMeshReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.FunctionMesh{}).
-		Owns(&v1alpha1.Function{}).
-		Owns(&v1alpha1.Source{}).
-		Owns(&v1alpha1.Sink{}).
+		For(&v1alpha2.FunctionMesh{}).
+		Owns(&v1alpha2.Function{}).
+		Owns(&v1alpha2.Source{}).
+		Owns(&v1alpha2.Sink{}).
 		Complete(r)
 }
@@ -22,7 +22,7 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
 	"github.com/streamnative/function-mesh/controllers/spec"
 )
 
@@ -31,13 +31,13 @@ var _ = Describe("FunctionMesh Controller", func() {
 		pulsarConfig := makeSamplePulsarConfig()
 		mesh := makeFunctionMeshSample()
 		if mesh.Status.FunctionConditions == nil {
-			mesh.Status.FunctionConditions = make(map[string]v1alpha1.ResourceCondition)
+			mesh.Status.FunctionConditions = make(map[string]v1alpha2.Condition)
 		}
 		if mesh.Status.SourceConditions == nil {
-			mesh.Status.SourceConditions = make(map[string]v1alpha1.ResourceCondition)
+			mesh.Status.SourceConditions = make(map[string]v1alpha2.Condition)
 		}
 		if mesh.Status.SinkConditions == nil {
-			mesh.Status.SinkConditions = make(map[string]v1alpha1.ResourceCondition)
+			mesh.Status.SinkConditions = make(map[string]v1alpha2.Condition)
 		}
 
 		It("Should create pulsar configmap successfully", func() {
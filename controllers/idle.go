@@ -0,0 +1,99 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// observeIdle checks the backlog of the input subscription when the last check is older than the check
+// interval of the idle policy, and sets the ScaledToZero condition once the subscription has no backlog
+// for the idle timeout. It returns the idle status and how long to wait before the next check, zero means
+// the idle policy is disabled.
+func observeIdle(log logr.Logger, recorder record.EventRecorder, obj runtime.Object, policy *v1alpha1.IdlePolicy,
+	getBacklog func() (int64, error), conditions *[]v1alpha2.Condition, current *v1alpha2.IdleStatus,
+	generation int64) (*v1alpha2.IdleStatus, time.Duration) {
+	if policy == nil {
+		v1alpha2.RemoveCondition(conditions, v1alpha2.ScaledToZero)
+		return nil, 0
+	}
+
+	interval := spec.DefaultIdleCheckInterval
+	if policy.CheckIntervalSeconds != nil {
+		interval = time.Duration(*policy.CheckIntervalSeconds) * time.Second
+	}
+	timeout := time.Duration(policy.IdleTimeoutSeconds) * time.Second
+
+	now := metav1.Now()
+	if current != nil {
+		if elapsed := now.Sub(current.LastCheckTime.Time); elapsed < interval {
+			return current, interval - elapsed
+		}
+	}
+
+	idle := &v1alpha2.IdleStatus{
+		LastCheckTime:  now,
+		LastActiveTime: now,
+	}
+	if current != nil {
+		idle.LastActiveTime = current.LastActiveTime
+		idle.Backlog = current.Backlog
+	}
+
+	backlog, err := getBacklog()
+	if err != nil {
+		// keep the current state until the backlog is available again
+		log.Error(err, "failed to get the backlog of the input subscription")
+		recorder.Eventf(obj, corev1.EventTypeWarning, EventReasonFailedGetBacklog,
+			"Failed to get the backlog of the input subscription: %v", err)
+		return idle, interval
+	}
+	idle.Backlog = backlog
+	if backlog > 0 {
+		idle.LastActiveTime = now
+	}
+
+	scaledToZero := v1alpha2.IsConditionTrue(*conditions, v1alpha2.ScaledToZero)
+	if now.Sub(idle.LastActiveTime.Time) >= timeout {
+		v1alpha2.SetCondition(conditions, v1alpha2.CreateCondition(
+			v1alpha2.ScaledToZero, metav1.ConditionTrue, v1alpha2.ReasonIdle,
+			fmt.Sprintf("no backlog since %s", idle.LastActiveTime.Format(time.RFC3339)), generation))
+		if !scaledToZero {
+			recorder.Eventf(obj, corev1.EventTypeNormal, EventReasonScaledToZero,
+				"Scaled to zero, no backlog since %s", idle.LastActiveTime.Format(time.RFC3339))
+		}
+	} else {
+		v1alpha2.SetCondition(conditions, v1alpha2.CreateCondition(
+			v1alpha2.ScaledToZero, metav1.ConditionFalse, v1alpha2.ReasonActive, "", generation))
+		if scaledToZero {
+			recorder.Eventf(obj, corev1.EventTypeNormal, EventReasonScaledUp,
+				"Scaled up, the input subscription has a backlog of %d", backlog)
+		}
+	}
+	return idle, interval
+}
@@ -0,0 +1,230 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"github.com/streamnative/function-mesh/controllers/proto"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// scrapeInstanceTimeout bounds the time spent on getting the status of a single instance
+const scrapeInstanceTimeout = 5 * time.Second
+
+// getInstanceFunctionStatus calls the GetFunctionStatus RPC of the instance control service
+var getInstanceFunctionStatus = func(ctx context.Context, address string) (*proto.FunctionStatus, error) {
+	conn, err := grpc.DialContext(ctx, address, grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return proto.NewInstanceControlClient(conn).GetFunctionStatus(ctx, &empty.Empty{})
+}
+
+// getInstanceMetrics calls the GetMetrics RPC of the instance control service
+var getInstanceMetrics = func(ctx context.Context, address string) (*proto.MetricsData, error) {
+	conn, err := grpc.DialContext(ctx, address, grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return proto.NewInstanceControlClient(conn).GetMetrics(ctx, &empty.Empty{})
+}
+
+// instanceScrapes holds the scrapes running outside of the reconciles and their results
+var instanceScrapes = &instanceScraper{scrapes: make(map[string]*instanceScrape)}
+
+type instanceScraper struct {
+	mu      sync.Mutex
+	scrapes map[string]*instanceScrape
+}
+
+// instanceScrape is a scrape of the instances of a statefulset, the result is nil until the scrape is done
+type instanceScrape struct {
+	result *v1alpha2.InstancesStatus
+}
+
+// observeInstances returns the runtime status of the instances and how long to wait before observing them
+// again, zero means scraping is disabled. The instances are scraped in the background when the last scrape
+// is older than the sync interval so the reconcile is never blocked by unreachable instances, the result is
+// picked up by the reconcile following the scrape.
+func observeInstances(objectMeta *metav1.ObjectMeta, replicas int32, conditions []v1alpha2.Condition,
+	current *v1alpha2.InstancesStatus) (*v1alpha2.InstancesStatus, time.Duration) {
+	interval := spec.Configs.InstanceStatusSyncInterval
+	if interval <= 0 {
+		return nil, 0
+	}
+
+	// the statefulset is not created yet, there is no instance to scrape
+	statefulSetCondition := v1alpha2.FindCondition(conditions, v1alpha2.StatefulSetReady)
+	if statefulSetCondition == nil || statefulSetCondition.Reason == v1alpha2.ReasonCreating {
+		return nil, interval
+	}
+
+	// a suspended, idle or scaled down component has no instance to scrape
+	if replicas == 0 {
+		instanceScrapes.forget(objectMeta)
+		return nil, interval
+	}
+
+	if current != nil && int32(len(current.Items)) == replicas {
+		if elapsed := time.Since(current.LastScrapeTime.Time); elapsed < interval {
+			return current, interval - elapsed
+		}
+	}
+
+	return instanceScrapes.observe(objectMeta, replicas, current, interval)
+}
+
+func (s *instanceScraper) observe(objectMeta *metav1.ObjectMeta, replicas int32,
+	current *v1alpha2.InstancesStatus, interval time.Duration) (*v1alpha2.InstancesStatus, time.Duration) {
+	key := objectMeta.Namespace + "/" + objectMeta.Name
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// the results which are not picked up within the interval belong to the components which are
+	// no longer observed, such as the deleted ones
+	for k, scrape := range s.scrapes {
+		if scrape.result != nil && time.Since(scrape.result.LastScrapeTime.Time) >= interval {
+			delete(s.scrapes, k)
+		}
+	}
+
+	scrape, ok := s.scrapes[key]
+	if ok && scrape.result == nil {
+		// the scrape is still running
+		return current, scrapeInstanceTimeout
+	}
+	if ok && int32(len(scrape.result.Items)) == replicas && time.Since(scrape.result.LastScrapeTime.Time) < interval {
+		delete(s.scrapes, key)
+		return scrape.result, interval
+	}
+
+	scrape = &instanceScrape{}
+	s.scrapes[key] = scrape
+	go func(objectMeta metav1.ObjectMeta) {
+		result := scrapeInstances(&objectMeta, replicas)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		scrape.result = result
+	}(*objectMeta.DeepCopy())
+	return current, scrapeInstanceTimeout
+}
+
+// forget drops the scrape of the instances of the statefulset, a running scrape is not picked up
+func (s *instanceScraper) forget(objectMeta *metav1.ObjectMeta) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.scrapes, objectMeta.Namespace+"/"+objectMeta.Name)
+}
+
+// scrapeInstances gets the status and the metrics of each instance of the statefulset
+func scrapeInstances(objectMeta *metav1.ObjectMeta, replicas int32) *v1alpha2.InstancesStatus {
+	instances := &v1alpha2.InstancesStatus{
+		LastScrapeTime: metav1.Now(),
+		Items:          make([]v1alpha2.InstanceStatus, replicas),
+	}
+	var wg sync.WaitGroup
+	for i := int32(0); i < replicas; i++ {
+		wg.Add(1)
+		go func(index int32) {
+			defer wg.Done()
+			instances.Items[index] = scrapeInstance(spec.MakeInstanceGRPCAddress(objectMeta, index), index)
+		}(i)
+	}
+	wg.Wait()
+	return instances
+}
+
+func scrapeInstance(address string, index int32) v1alpha2.InstanceStatus {
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeInstanceTimeout)
+	defer cancel()
+	status, err := getInstanceFunctionStatus(ctx, address)
+	if err != nil {
+		return v1alpha2.InstanceStatus{InstanceID: index, Error: err.Error()}
+	}
+	instance := makeInstanceStatus(index, status)
+	metrics, err := getInstanceMetrics(ctx, address)
+	if err != nil {
+		instance.Error = fmt.Sprintf("failed to get metrics: %v", err)
+		return instance
+	}
+	instance.Metrics = makeInstanceMetrics(metrics)
+	return instance
+}
+
+func makeInstanceStatus(index int32, status *proto.FunctionStatus) v1alpha2.InstanceStatus {
+	instance := v1alpha2.InstanceStatus{
+		InstanceID:               index,
+		Running:                  status.Running,
+		FailureException:         status.FailureException,
+		NumRestarts:              status.NumRestarts,
+		NumReceived:              status.NumReceived,
+		NumSuccessfullyProcessed: status.NumSuccessfullyProcessed,
+		NumUserExceptions:        status.NumUserExceptions,
+		NumSystemExceptions:      status.NumSystemExceptions,
+		NumSourceExceptions:      status.NumSourceExceptions,
+		NumSinkExceptions:        status.NumSinkExceptions,
+	}
+
+	if status.LastInvocationTime > 0 {
+		lastInvocationTime := metav1.NewTime(msToTime(status.LastInvocationTime))
+		instance.LastInvocationTime = &lastInvocationTime
+	}
+
+	var lastException *proto.FunctionStatus_ExceptionInformation
+	for _, exceptions := range [][]*proto.FunctionStatus_ExceptionInformation{
+		status.LatestUserExceptions, status.LatestSystemExceptions,
+		status.LatestSourceExceptions, status.LatestSinkExceptions} {
+		for _, exception := range exceptions {
+			if exception != nil && (lastException == nil || exception.MsSinceEpoch > lastException.MsSinceEpoch) {
+				lastException = exception
+			}
+		}
+	}
+	if lastException != nil {
+		lastExceptionTime := metav1.NewTime(msToTime(lastException.MsSinceEpoch))
+		instance.LastException = lastException.ExceptionString
+		instance.LastExceptionTime = &lastExceptionTime
+	}
+
+	return instance
+}
+
+func makeInstanceMetrics(metrics *proto.MetricsData) *v1alpha2.InstanceMetrics {
+	return &v1alpha2.InstanceMetrics{
+		ReceivedLastMinute:              metrics.ReceivedTotal_1Min,
+		ProcessedSuccessfullyLastMinute: metrics.ProcessedSuccessfullyTotal_1Min,
+		UserExceptionsLastMinute:        metrics.UserExceptionsTotal_1Min,
+		SystemExceptionsLastMinute:      metrics.SystemExceptionsTotal_1Min,
+		AvgProcessLatencyMs:             strconv.FormatFloat(metrics.AvgProcessLatency_1Min, 'f', 3, 64),
+	}
+}
+
+func msToTime(ms int64) time.Time {
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
@@ -0,0 +1,191 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"github.com/streamnative/function-mesh/controllers/proto"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func stubInstanceControl(t *testing.T, release <-chan struct{}) {
+	getFunctionStatus, getMetrics := getInstanceFunctionStatus, getInstanceMetrics
+	t.Cleanup(func() {
+		getInstanceFunctionStatus, getInstanceMetrics = getFunctionStatus, getMetrics
+	})
+	getInstanceFunctionStatus = func(ctx context.Context, address string) (*proto.FunctionStatus, error) {
+		if release != nil {
+			<-release
+		}
+		if strings.HasPrefix(address, "fn-function-1.") {
+			return nil, errors.New("connection refused")
+		}
+		return &proto.FunctionStatus{
+			Running:            true,
+			NumReceived:        10,
+			NumUserExceptions:  2,
+			LastInvocationTime: 1600000000000,
+			LatestUserExceptions: []*proto.FunctionStatus_ExceptionInformation{
+				{ExceptionString: "boom", MsSinceEpoch: 1600000000000},
+			},
+		}, nil
+	}
+	getInstanceMetrics = func(ctx context.Context, address string) (*proto.MetricsData, error) {
+		return &proto.MetricsData{ReceivedTotal_1Min: 5, UserExceptionsTotal_1Min: 1, AvgProcessLatency_1Min: 1.5}, nil
+	}
+}
+
+func waitForScrape(t *testing.T, key string) {
+	assert.Assert(t, waitFor(func() bool {
+		instanceScrapes.mu.Lock()
+		defer instanceScrapes.mu.Unlock()
+		scrape, ok := instanceScrapes.scrapes[key]
+		return ok && scrape.result != nil
+	}))
+}
+
+func waitFor(condition func() bool) bool {
+	for i := 0; i < 100; i++ {
+		if condition() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestObserveInstances(t *testing.T) {
+	stubInstanceControl(t, nil)
+	objectMeta := &metav1.ObjectMeta{Name: "fn-function", Namespace: "default"}
+	conditions := []v1alpha2.Condition{
+		v1alpha2.CreateCondition(v1alpha2.StatefulSetReady, metav1.ConditionTrue, v1alpha2.ReasonReconciled, "", 1)}
+
+	// the first observation starts the scrape in the background and keeps the current status
+	instances, requeueAfter := observeInstances(objectMeta, 2, conditions, nil)
+	assert.Assert(t, instances == nil)
+	assert.Equal(t, requeueAfter, scrapeInstanceTimeout)
+	waitForScrape(t, "default/fn-function")
+
+	instances, requeueAfter = observeInstances(objectMeta, 2, conditions, nil)
+	assert.Equal(t, requeueAfter, spec.Configs.InstanceStatusSyncInterval)
+	assert.Equal(t, len(instances.Items), 2)
+
+	running := instances.Items[0]
+	assert.Equal(t, running.InstanceID, int32(0))
+	assert.Assert(t, running.Running)
+	assert.Equal(t, running.NumReceived, int64(10))
+	assert.Equal(t, running.LastException, "boom")
+	assert.Assert(t, running.LastExceptionTime.Equal(&metav1.Time{Time: time.Unix(1600000000, 0)}))
+	assert.DeepEqual(t, running.Metrics, &v1alpha2.InstanceMetrics{
+		ReceivedLastMinute:       5,
+		UserExceptionsLastMinute: 1,
+		AvgProcessLatencyMs:      "1.500",
+	})
+
+	unreachable := instances.Items[1]
+	assert.Equal(t, unreachable.InstanceID, int32(1))
+	assert.Equal(t, unreachable.Error, "connection refused")
+	assert.Assert(t, unreachable.Metrics == nil)
+
+	// the result is handed over once, a recent status is kept without scraping again
+	instances, requeueAfter = observeInstances(objectMeta, 2, conditions, instances)
+	assert.Equal(t, len(instances.Items), 2)
+	assert.Assert(t, requeueAfter <= spec.Configs.InstanceStatusSyncInterval)
+	instanceScrapes.mu.Lock()
+	_, ok := instanceScrapes.scrapes["default/fn-function"]
+	instanceScrapes.mu.Unlock()
+	assert.Assert(t, !ok)
+}
+
+func TestObserveInstancesDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	stubInstanceControl(t, release)
+	objectMeta := &metav1.ObjectMeta{Name: "slow-function", Namespace: "default"}
+	conditions := []v1alpha2.Condition{
+		v1alpha2.CreateCondition(v1alpha2.StatefulSetReady, metav1.ConditionTrue, v1alpha2.ReasonReconciled, "", 1)}
+	current := &v1alpha2.InstancesStatus{
+		LastScrapeTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+		Items:          []v1alpha2.InstanceStatus{{InstanceID: 0}},
+	}
+
+	// the scrape of the instance is held until both reconciles are done
+	instances, requeueAfter := observeInstances(objectMeta, 1, conditions, current)
+	assert.Equal(t, instances, current)
+	assert.Equal(t, requeueAfter, scrapeInstanceTimeout)
+	instances, _ = observeInstances(objectMeta, 1, conditions, current)
+	assert.Equal(t, instances, current)
+	close(release)
+
+	waitForScrape(t, "default/slow-function")
+	instances, _ = observeInstances(objectMeta, 1, conditions, current)
+	assert.Assert(t, instances.LastScrapeTime.After(current.LastScrapeTime.Time))
+	assert.Assert(t, instances.Items[0].Running)
+}
+
+func TestObserveInstancesDisabled(t *testing.T) {
+	interval := spec.Configs.InstanceStatusSyncInterval
+	defer func() { spec.Configs.InstanceStatusSyncInterval = interval }()
+	spec.Configs.InstanceStatusSyncInterval = 0
+
+	objectMeta := &metav1.ObjectMeta{Name: "disabled-function", Namespace: "default"}
+	instances, requeueAfter := observeInstances(objectMeta, 1, nil, nil)
+	assert.Assert(t, instances == nil)
+	assert.Equal(t, requeueAfter, time.Duration(0))
+}
+
+func TestObserveInstancesEviction(t *testing.T) {
+	stubInstanceControl(t, nil)
+	conditions := []v1alpha2.Condition{
+		v1alpha2.CreateCondition(v1alpha2.StatefulSetReady, metav1.ConditionTrue, v1alpha2.ReasonReconciled, "", 1)}
+	scraped := func(key string) bool {
+		instanceScrapes.mu.Lock()
+		defer instanceScrapes.mu.Unlock()
+		_, ok := instanceScrapes.scrapes[key]
+		return ok
+	}
+
+	// the scrape of a component scaled to zero is dropped
+	scaled := &metav1.ObjectMeta{Name: "scaled-function", Namespace: "default"}
+	observeInstances(scaled, 1, conditions, nil)
+	waitForScrape(t, "default/scaled-function")
+	instances, requeueAfter := observeInstances(scaled, 0, conditions, nil)
+	assert.Assert(t, instances == nil)
+	assert.Equal(t, requeueAfter, spec.Configs.InstanceStatusSyncInterval)
+	assert.Assert(t, !scraped("default/scaled-function"))
+
+	// the result of a component which is no longer observed is dropped once older than the interval
+	deleted := &metav1.ObjectMeta{Name: "deleted-function", Namespace: "default"}
+	observeInstances(deleted, 1, conditions, nil)
+	waitForScrape(t, "default/deleted-function")
+	instanceScrapes.mu.Lock()
+	instanceScrapes.scrapes["default/deleted-function"].result.LastScrapeTime =
+		metav1.NewTime(time.Now().Add(-spec.Configs.InstanceStatusSyncInterval))
+	instanceScrapes.mu.Unlock()
+	observeInstances(&metav1.ObjectMeta{Name: "other-function", Namespace: "default"}, 1, conditions, nil)
+	assert.Assert(t, !scraped("default/deleted-function"))
+	waitForScrape(t, "default/other-function")
+	instanceScrapes.forget(&metav1.ObjectMeta{Name: "other-function", Namespace: "default"})
+}
@@ -0,0 +1,253 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const metricsNamespace = "function_mesh"
+
+// Kinds used as the kind label of the operator metrics
+const (
+	kindFunction     = "Function"
+	kindSource       = "Source"
+	kindSink         = "Sink"
+	kindFunctionMesh = "FunctionMesh"
+)
+
+var (
+	// rolloutDuration observes how long the resources take to become ready again after the controller
+	// started to roll out a change, i.e. while the Progressing condition was true
+	rolloutDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "rollout_duration_seconds",
+		Help:      "Time from the start of a rollout to the resource being ready.",
+		Buckets:   []float64{5, 10, 30, 60, 120, 300, 600, 1200, 1800, 3600},
+	}, []string{"kind"})
+
+	// statefulSetDriftUpdates counts the statefulset updates triggered by a difference between
+	// the desired and the observed statefulset
+	statefulSetDriftUpdates = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "statefulset_drift_updates_total",
+		Help:      "Number of StatefulSet updates triggered by a drift from the desired state.",
+	}, []string{"kind"})
+
+	// orphanDeletions counts the components deleted from a function mesh after they are removed from its spec
+	orphanDeletions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "mesh_orphan_deletions_total",
+		Help:      "Number of orphaned FunctionMesh components deleted.",
+	}, []string{"kind", "result"})
+
+	// webhookRejections counts the fields rejected by the validating webhooks
+	webhookRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "webhook_rejections_total",
+		Help:      "Number of fields rejected by the validating webhooks, by the rule that rejected them.",
+	}, []string{"kind", "rule"})
+)
+
+// the keys and indexes of the field paths are dropped to keep the cardinality of the rule label bounded
+var fieldPathSubscripts = regexp.MustCompile(`\[[^\]]*\]`)
+
+var (
+	managedResourcesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "managed_resources"),
+		"Number of resources managed by the operator.",
+		[]string{"kind", "runtime", "ready"}, nil)
+
+	rolloutInProgressDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "rollout_in_progress_seconds"),
+		"Time since the rollout of a resource started, only reported while the resource is progressing.",
+		[]string{"kind", "namespace", "name"}, nil)
+)
+
+func init() {
+	metrics.Registry.MustRegister(rolloutDuration, statefulSetDriftUpdates, orphanDeletions, webhookRejections)
+}
+
+// WebhookRejectionRecorder counts the fields rejected by the validating webhooks in the operator metrics,
+// the field path identifies the validation rule
+type WebhookRejectionRecorder struct{}
+
+var _ v1alpha1.RejectionRecorder = WebhookRejectionRecorder{}
+
+func (WebhookRejectionRecorder) RecordRejections(kind string, errs field.ErrorList) {
+	for _, err := range errs {
+		rule := fieldPathSubscripts.ReplaceAllString(err.Field, "")
+		webhookRejections.WithLabelValues(kind, rule).Inc()
+	}
+}
+
+// FleetCollector reports the state of the resources managed by the operator on every scrape,
+// the resources are listed from the cache of the manager.
+type FleetCollector struct {
+	client.Reader
+	Log logr.Logger
+}
+
+var _ prometheus.Collector = &FleetCollector{}
+
+// SetupFleetCollector registers the collector to the metrics registry of controller-runtime
+func SetupFleetCollector(reader client.Reader, log logr.Logger) error {
+	return metrics.Registry.Register(&FleetCollector{Reader: reader, Log: log})
+}
+
+func (c *FleetCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- managedResourcesDesc
+	ch <- rolloutInProgressDesc
+}
+
+func (c *FleetCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+	fleet := newFleetStats()
+
+	functions := &v1alpha2.FunctionList{}
+	if err := c.List(ctx, functions); err != nil {
+		c.Log.Error(err, "failed to list functions")
+	}
+	for i := range functions.Items {
+		function := &functions.Items[i]
+		fleet.add(kindFunction, runtimeName(function.Spec.Runtime), function.ObjectMeta, function.Status.Conditions)
+	}
+
+	sources := &v1alpha2.SourceList{}
+	if err := c.List(ctx, sources); err != nil {
+		c.Log.Error(err, "failed to list sources")
+	}
+	for i := range sources.Items {
+		source := &sources.Items[i]
+		fleet.add(kindSource, runtimeName(source.Spec.Runtime), source.ObjectMeta, source.Status.Conditions)
+	}
+
+	sinks := &v1alpha2.SinkList{}
+	if err := c.List(ctx, sinks); err != nil {
+		c.Log.Error(err, "failed to list sinks")
+	}
+	for i := range sinks.Items {
+		sink := &sinks.Items[i]
+		fleet.add(kindSink, runtimeName(sink.Spec.Runtime), sink.ObjectMeta, sink.Status.Conditions)
+	}
+
+	meshes := &v1alpha2.FunctionMeshList{}
+	if err := c.List(ctx, meshes); err != nil {
+		c.Log.Error(err, "failed to list function meshes")
+	}
+	for i := range meshes.Items {
+		mesh := &meshes.Items[i]
+		fleet.add(kindFunctionMesh, "", mesh.ObjectMeta, mesh.Status.Conditions)
+	}
+
+	for key, count := range fleet.managed {
+		ch <- prometheus.MustNewConstMetric(managedResourcesDesc, prometheus.GaugeValue, float64(count),
+			key.kind, key.runtime, key.ready)
+	}
+	for _, rollout := range fleet.rollouts {
+		ch <- prometheus.MustNewConstMetric(rolloutInProgressDesc, prometheus.GaugeValue, rollout.seconds,
+			rollout.kind, rollout.namespace, rollout.name)
+	}
+}
+
+type fleetKey struct {
+	kind    string
+	runtime string
+	ready   string
+}
+
+type rolloutStats struct {
+	kind      string
+	namespace string
+	name      string
+	seconds   float64
+}
+
+type fleetStats struct {
+	now      time.Time
+	managed  map[fleetKey]int
+	rollouts []rolloutStats
+}
+
+func newFleetStats() *fleetStats {
+	return &fleetStats{
+		now:     time.Now(),
+		managed: map[fleetKey]int{},
+	}
+}
+
+func (s *fleetStats) add(kind, runtime string, meta metav1.ObjectMeta, conditions []v1alpha2.Condition) {
+	ready := string(metav1.ConditionUnknown)
+	if condition := v1alpha2.FindCondition(conditions, v1alpha2.Ready); condition != nil {
+		ready = string(condition.Status)
+	}
+	s.managed[fleetKey{kind: kind, runtime: runtime, ready: ready}]++
+
+	if condition := v1alpha2.FindCondition(conditions, v1alpha2.Progressing); condition != nil &&
+		condition.Status == metav1.ConditionTrue {
+		s.rollouts = append(s.rollouts, rolloutStats{
+			kind:      kind,
+			namespace: meta.Namespace,
+			name:      meta.Name,
+			seconds:   s.now.Sub(condition.LastTransitionTime.Time).Seconds(),
+		})
+	}
+}
+
+func runtimeName(runtime v1alpha1.Runtime) string {
+	switch {
+	case runtime.Java != nil:
+		return "java"
+	case runtime.Python != nil:
+		return "python"
+	case runtime.Golang != nil:
+		return "go"
+	case runtime.Custom != nil:
+		return "custom"
+	}
+	return ""
+}
+
+// setAggregatedConditions computes the aggregated conditions of the resource,
+// and observes the rollout duration once the resource stops progressing and is ready
+func setAggregatedConditions(kind string, conditions *[]v1alpha2.Condition, components []v1alpha2.Condition,
+	generation int64) {
+	var rolloutStart *metav1.Time
+	if progressing := v1alpha2.FindCondition(*conditions, v1alpha2.Progressing); progressing != nil &&
+		progressing.Status == metav1.ConditionTrue {
+		rolloutStart = progressing.LastTransitionTime.DeepCopy()
+	}
+
+	v1alpha2.SetAggregatedConditions(conditions, components, generation)
+
+	if rolloutStart != nil && !v1alpha2.IsConditionTrue(*conditions, v1alpha2.Progressing) &&
+		v1alpha2.IsConditionTrue(*conditions, v1alpha2.Ready) {
+		rolloutDuration.WithLabelValues(kind).Observe(time.Since(rolloutStart.Time).Seconds())
+	}
+}
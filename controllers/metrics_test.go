@@ -0,0 +1,132 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func makeReadyCondition(status metav1.ConditionStatus) v1alpha2.Condition {
+	return v1alpha2.CreateCondition(v1alpha2.Ready, status, v1alpha2.ReasonReconciled, "", 1)
+}
+
+func TestFleetCollectorCollect(t *testing.T) {
+	progressing := v1alpha2.CreateCondition(v1alpha2.Progressing, metav1.ConditionTrue, v1alpha2.ReasonUpdating, "", 1)
+	progressing.LastTransitionTime = metav1.NewTime(time.Now().Add(-time.Minute))
+	c := newFinalizerTestClient(t,
+		&v1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{Name: "ready", Namespace: "default"},
+			Spec:       v1alpha1.FunctionSpec{Runtime: v1alpha1.Runtime{Java: &v1alpha1.JavaRuntime{}}},
+			Status:     v1alpha2.FunctionStatus{Conditions: []v1alpha2.Condition{makeReadyCondition(metav1.ConditionTrue)}},
+		},
+		&v1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{Name: "updating", Namespace: "default"},
+			Spec:       v1alpha1.FunctionSpec{Runtime: v1alpha1.Runtime{Java: &v1alpha1.JavaRuntime{}}},
+			Status: v1alpha2.FunctionStatus{Conditions: []v1alpha2.Condition{
+				makeReadyCondition(metav1.ConditionFalse), progressing}},
+		},
+		&v1alpha2.Sink{
+			ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "default"},
+			Spec:       v1alpha1.SinkSpec{Runtime: v1alpha1.Runtime{Python: &v1alpha1.PythonRuntime{}}},
+		},
+		&v1alpha2.FunctionMesh{
+			ObjectMeta: metav1.ObjectMeta{Name: "mesh", Namespace: "default"},
+			Status: v1alpha2.FunctionMeshStatus{Conditions: []v1alpha2.Condition{
+				makeReadyCondition(metav1.ConditionTrue)}},
+		})
+	collector := &FleetCollector{Reader: c, Log: ctrl.Log.WithName("test")}
+
+	assert.NilError(t, testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP function_mesh_managed_resources Number of resources managed by the operator.
+# TYPE function_mesh_managed_resources gauge
+function_mesh_managed_resources{kind="Function",ready="False",runtime="java"} 1
+function_mesh_managed_resources{kind="Function",ready="True",runtime="java"} 1
+function_mesh_managed_resources{kind="FunctionMesh",ready="True",runtime=""} 1
+function_mesh_managed_resources{kind="Sink",ready="Unknown",runtime="python"} 1
+`), "function_mesh_managed_resources"))
+
+	ch := make(chan prometheus.Metric, 10)
+	collector.Collect(ch)
+	close(ch)
+	var rollouts []*dto.Metric
+	for metric := range ch {
+		if strings.Contains(metric.Desc().String(), "rollout_in_progress_seconds") {
+			m := &dto.Metric{}
+			assert.NilError(t, metric.Write(m))
+			rollouts = append(rollouts, m)
+		}
+	}
+	assert.Equal(t, len(rollouts), 1)
+	assert.Assert(t, rollouts[0].GetGauge().GetValue() >= 60, rollouts[0].GetGauge().GetValue())
+	labels := map[string]string{}
+	for _, label := range rollouts[0].GetLabel() {
+		labels[label.GetName()] = label.GetValue()
+	}
+	assert.DeepEqual(t, labels, map[string]string{"kind": kindFunction, "namespace": "default", "name": "updating"})
+}
+
+func rolloutDurationSamples(t *testing.T, kind string) uint64 {
+	m := &dto.Metric{}
+	assert.NilError(t, rolloutDuration.WithLabelValues(kind).(prometheus.Metric).Write(m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestSetAggregatedConditionsObservesRollout(t *testing.T) {
+	const kind = "TestRollout"
+	ready := v1alpha2.CreateCondition(v1alpha2.StatefulSetReady, metav1.ConditionTrue, v1alpha2.ReasonReconciled, "", 1)
+	creating := v1alpha2.CreateCondition(v1alpha2.StatefulSetReady, metav1.ConditionFalse, v1alpha2.ReasonCreating, "", 1)
+
+	// the rollout starts, nothing is observed
+	var conditions []v1alpha2.Condition
+	setAggregatedConditions(kind, &conditions, []v1alpha2.Condition{creating}, 1)
+	assert.Assert(t, v1alpha2.IsConditionTrue(conditions, v1alpha2.Progressing))
+	assert.Equal(t, rolloutDurationSamples(t, kind), uint64(0))
+
+	// the rollout is observed once the resource is ready
+	setAggregatedConditions(kind, &conditions, []v1alpha2.Condition{ready}, 1)
+	assert.Assert(t, v1alpha2.IsConditionTrue(conditions, v1alpha2.Ready))
+	assert.Equal(t, rolloutDurationSamples(t, kind), uint64(1))
+
+	// a ready resource which is not progressing is not observed again
+	setAggregatedConditions(kind, &conditions, []v1alpha2.Condition{ready}, 1)
+	assert.Equal(t, rolloutDurationSamples(t, kind), uint64(1))
+}
+
+func TestWebhookRejectionRecorder(t *testing.T) {
+	const kind = "TestRejection"
+	WebhookRejectionRecorder{}.RecordRejections(kind, field.ErrorList{
+		field.Invalid(field.NewPath("spec", "input", "topics").Index(0), "", "invalid topic"),
+		field.Invalid(field.NewPath("spec", "input", "topics").Index(1), "", "invalid topic"),
+		field.Required(field.NewPath("spec", "pod", "env").Key("A"), "missing value"),
+	})
+
+	assert.Equal(t, testutil.ToFloat64(webhookRejections.WithLabelValues(kind, "spec.input.topics")), float64(2))
+	assert.Equal(t, testutil.ToFloat64(webhookRejections.WithLabelValues(kind, "spec.pod.env")), float64(1))
+}
@@ -0,0 +1,187 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors;servicemonitors,verbs=get;list;watch;create;update;patch;delete
+
+var monitorKinds = []v1alpha1.MonitorKind{v1alpha1.PodMonitor, v1alpha1.ServiceMonitor}
+
+// ownMonitors watches the monitors of the kinds served by the cluster, so that the drift of the monitors
+// is corrected. The Prometheus Operator is optional, the kinds are skipped when their CRDs are not installed.
+func ownMonitors(blder *builder.Builder, mapper meta.RESTMapper) *builder.Builder {
+	for _, kind := range monitorKinds {
+		gvk := spec.MonitoringGroupVersion.WithKind(string(kind))
+		if _, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+			continue
+		}
+		blder = blder.Owns(spec.MakeMonitorObject(kind))
+	}
+	return blder
+}
+
+// observeMonitor sets the MonitorReady condition by comparing the desired monitor with the existing one,
+// the desired monitor is nil when the monitor is disabled
+func observeMonitor(ctx context.Context, c client.Client, key types.NamespacedName,
+	desired *unstructured.Unstructured, conditions *[]v1alpha2.Condition, generation int64) error {
+	if desired == nil {
+		if v1alpha2.FindCondition(*conditions, v1alpha2.MonitorReady) == nil {
+			return nil
+		}
+		// the monitor was enabled, remove the condition once the monitors are deleted
+		for _, kind := range monitorKinds {
+			exists, err := monitorExists(ctx, c, key, kind)
+			if err != nil {
+				return err
+			}
+			if exists {
+				v1alpha2.SetCondition(conditions, v1alpha2.CreateCondition(
+					v1alpha2.MonitorReady, metav1.ConditionFalse, v1alpha2.ReasonDeleting,
+					"monitor is disabled", generation))
+				return nil
+			}
+		}
+		v1alpha2.RemoveCondition(conditions, v1alpha2.MonitorReady)
+		return nil
+	}
+
+	existing := spec.MakeMonitorObject(v1alpha1.MonitorKind(desired.GetKind()))
+	err := c.Get(ctx, key, existing)
+	if err != nil {
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			v1alpha2.SetCondition(conditions, v1alpha2.CreateCondition(
+				v1alpha2.MonitorReady, metav1.ConditionFalse, v1alpha2.ReasonCreating,
+				"monitor is not created", generation))
+			return nil
+		}
+		return err
+	}
+
+	if !equality.Semantic.DeepEqual(existing.Object["spec"], desired.Object["spec"]) ||
+		!equality.Semantic.DeepEqual(existing.GetLabels(), desired.GetLabels()) {
+		v1alpha2.SetCondition(conditions, v1alpha2.CreateCondition(
+			v1alpha2.MonitorReady, metav1.ConditionFalse, v1alpha2.ReasonUpdating,
+			"monitor is outdated", generation))
+		return nil
+	}
+
+	v1alpha2.SetCondition(conditions, v1alpha2.CreateCondition(
+		v1alpha2.MonitorReady, metav1.ConditionTrue, v1alpha2.ReasonReconciled, "", generation))
+	return nil
+}
+
+// applyMonitor creates, updates or deletes the monitor according to the MonitorReady condition
+func applyMonitor(ctx context.Context, c client.Client, recorder record.EventRecorder, obj runtime.Object,
+	key types.NamespacedName, desired *unstructured.Unstructured, conditions []v1alpha2.Condition) error {
+	condition := v1alpha2.FindCondition(conditions, v1alpha2.MonitorReady)
+	if condition == nil || condition.Status == metav1.ConditionTrue {
+		return nil
+	}
+
+	switch condition.Reason {
+	case v1alpha2.ReasonCreating:
+		if desired == nil {
+			return nil
+		}
+		// the monitor of the other kind is left behind when the kind is changed
+		for _, kind := range monitorKinds {
+			if string(kind) != desired.GetKind() {
+				if err := deleteMonitor(ctx, c, recorder, obj, key, kind); err != nil {
+					return err
+				}
+			}
+		}
+		if err := c.Create(ctx, desired); err != nil {
+			recorder.Eventf(obj, corev1.EventTypeWarning, EventReasonFailedCreate,
+				"Failed to create %s %s: %v", desired.GetKind(), key.Name, err)
+			return err
+		}
+		recorder.Eventf(obj, corev1.EventTypeNormal, EventReasonCreated, "Created %s %s", desired.GetKind(), key.Name)
+	case v1alpha2.ReasonUpdating:
+		if desired == nil {
+			return nil
+		}
+		existing := spec.MakeMonitorObject(v1alpha1.MonitorKind(desired.GetKind()))
+		if err := c.Get(ctx, key, existing); err != nil {
+			return err
+		}
+		existing.SetLabels(desired.GetLabels())
+		existing.Object["spec"] = desired.Object["spec"]
+		if err := c.Update(ctx, existing); err != nil {
+			recorder.Eventf(obj, corev1.EventTypeWarning, EventReasonFailedUpdate,
+				"Failed to update %s %s: %v", desired.GetKind(), key.Name, err)
+			return err
+		}
+		recorder.Eventf(obj, corev1.EventTypeNormal, EventReasonUpdated, "Updated %s %s", desired.GetKind(), key.Name)
+	case v1alpha2.ReasonDeleting:
+		for _, kind := range monitorKinds {
+			if err := deleteMonitor(ctx, c, recorder, obj, key, kind); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func monitorExists(ctx context.Context, c client.Client, key types.NamespacedName,
+	kind v1alpha1.MonitorKind) (bool, error) {
+	err := c.Get(ctx, key, spec.MakeMonitorObject(kind))
+	if err != nil {
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func deleteMonitor(ctx context.Context, c client.Client, recorder record.EventRecorder, obj runtime.Object,
+	key types.NamespacedName, kind v1alpha1.MonitorKind) error {
+	exists, err := monitorExists(ctx, c, key, kind)
+	if err != nil || !exists {
+		return err
+	}
+	monitor := spec.MakeMonitorObject(kind)
+	monitor.SetNamespace(key.Namespace)
+	monitor.SetName(key.Name)
+	if err := c.Delete(ctx, monitor); err != nil && !errors.IsNotFound(err) {
+		recorder.Eventf(obj, corev1.EventTypeWarning, EventReasonFailedDelete,
+			"Failed to delete %s %s: %v", kind, key.Name, err)
+		return err
+	}
+	recorder.Eventf(obj, corev1.EventTypeNormal, EventReasonDeleted, "Deleted %s %s", kind, key.Name)
+	return nil
+}
@@ -0,0 +1,97 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+
+// observePackageDownload reports the failures of the init containers downloading the package of a component
+// and installing its dependencies, the labels select the pods of the component
+func observePackageDownload(ctx context.Context, c client.Client, recorder record.EventRecorder, object runtime.Object,
+	namespace string, labels map[string]string, runtimeSpec v1alpha1.Runtime, conditions *[]v1alpha2.Condition,
+	generation int64) error {
+	if !spec.HasPackageDownload(runtimeSpec) && !spec.HasPythonDependenciesInstall(runtimeSpec) {
+		v1alpha2.RemoveCondition(conditions, v1alpha2.PackageReady)
+		return nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels(labels)); err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		reason, message, failed := getPackageDownloadFailure(&pods.Items[i])
+		if !failed {
+			continue
+		}
+		if condition := v1alpha2.FindCondition(*conditions, v1alpha2.PackageReady); condition == nil ||
+			condition.Status != metav1.ConditionFalse || condition.Reason != reason {
+			recorder.Eventf(object, corev1.EventTypeWarning, EventReasonPackageFailed, "%s", message)
+		}
+		v1alpha2.SetCondition(conditions, v1alpha2.CreateCondition(
+			v1alpha2.PackageReady, metav1.ConditionFalse, reason, message, generation))
+		return nil
+	}
+	v1alpha2.SetCondition(conditions, v1alpha2.CreateCondition(
+		v1alpha2.PackageReady, metav1.ConditionTrue, v1alpha2.ReasonReconciled, "", generation))
+	return nil
+}
+
+// getPackageDownloadFailure returns the reason and the message of the last failure of the init containers
+// downloading the package and installing its dependencies, as long as the init container has not succeeded since
+func getPackageDownloadFailure(pod *corev1.Pod) (string, string, bool) {
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.Name != spec.PackageDownloadContainerName && status.Name != spec.PythonDependenciesContainerName {
+			continue
+		}
+		terminated := status.State.Terminated
+		if terminated == nil && status.State.Waiting != nil {
+			terminated = status.LastTerminationState.Terminated
+		}
+		if terminated == nil || terminated.ExitCode == 0 {
+			continue
+		}
+		message := strings.TrimSpace(terminated.Message)
+		if message == "" {
+			message = fmt.Sprintf("exited with code %d", terminated.ExitCode)
+		}
+		message = fmt.Sprintf("pod %s: %s", pod.Name, message)
+		if status.Name == spec.PythonDependenciesContainerName {
+			return v1alpha2.ReasonDependencyInstallFailed, message, true
+		}
+		if terminated.ExitCode == spec.PackageChecksumMismatchExitCode {
+			return v1alpha2.ReasonChecksumMismatch, message, true
+		}
+		return v1alpha2.ReasonDownloadFailed, message, true
+	}
+	return "", "", false
+}
@@ -0,0 +1,218 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	"github.com/streamnative/pulsarctl/pkg/pulsar"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/common"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+
+const (
+	authPluginKey = "clientAuthenticationPlugin"
+	authParamsKey = "clientAuthenticationParameters"
+)
+
+// getPulsarConnection returns the spec of the connection referred by a component in the namespace,
+// and the namespace of the secrets referred by the connection without a namespace
+func getPulsarConnection(ctx context.Context, c client.Client, namespace string,
+	ref *v1alpha1.PulsarConnectionRef) (*v1alpha2.PulsarConnectionSpec, string, error) {
+	if ref.IsClusterScoped() {
+		connection := &v1alpha2.ClusterPulsarConnection{}
+		if err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, connection); err != nil {
+			return nil, "", err
+		}
+		return &connection.Spec, "", nil
+	}
+	connection := &v1alpha2.PulsarConnection{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, connection); err != nil {
+		return nil, "", err
+	}
+	return &connection.Spec, namespace, nil
+}
+
+// resolveSecretNamespace returns the namespace of a secret referred by a connection. The secrets of a PulsarConnection
+// must be in its own namespace, as they are copied into the namespaces of the components.
+func resolveSecretNamespace(ref corev1.SecretReference, namespace string) (string, error) {
+	if namespace == "" {
+		if ref.Namespace == "" {
+			return "", fmt.Errorf("the namespace of secret %s is required by a ClusterPulsarConnection", ref.Name)
+		}
+		return ref.Namespace, nil
+	}
+	if ref.Namespace != "" && ref.Namespace != namespace {
+		return "", fmt.Errorf("secret %s/%s is not in the namespace %s of the PulsarConnection",
+			ref.Namespace, ref.Name, namespace)
+	}
+	return namespace, nil
+}
+
+// getConnectionSecretData returns the values of the keys of a secret referred by a connection
+func getConnectionSecretData(ctx context.Context, c client.Client, ref corev1.SecretReference, namespace string,
+	keys ...string) (map[string][]byte, error) {
+	secretNamespace, err := resolveSecretNamespace(ref, namespace)
+	if err != nil {
+		return nil, err
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: secretNamespace, Name: ref.Name}, secret); err != nil {
+		return nil, err
+	}
+	data := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		value, ok := secret.Data[key]
+		if !ok {
+			return nil, fmt.Errorf("key %s not found in secret %s/%s", key, secretNamespace, ref.Name)
+		}
+		data[key] = value
+	}
+	return data, nil
+}
+
+// newPulsarConnectionAdmin creates the admin client with the settings of the connection
+func newPulsarConnectionAdmin(ctx context.Context, c client.Client, namespace string,
+	connection *v1alpha2.PulsarConnectionSpec) (pulsar.Client, error) {
+	config := &common.Config{
+		WebServiceURL:    connection.WebServiceURL,
+		PulsarAPIVersion: common.V2,
+	}
+
+	if connection.Auth != nil {
+		data, err := getConnectionSecretData(ctx, c, connection.Auth.SecretRef, namespace, authPluginKey, authParamsKey)
+		if err != nil {
+			return nil, err
+		}
+		config.AuthPlugin = string(data[authPluginKey])
+		config.AuthParams = string(data[authParamsKey])
+	}
+
+	if tls := connection.TLS; tls != nil && tls.Enabled {
+		config.TLSAllowInsecureConnection = tls.AllowInsecure
+		config.TLSEnableHostnameVerification = tls.HostnameVerification
+		if tls.CertSecretRef != nil && tls.CertSecretKey != "" {
+			secretNamespace, err := resolveSecretNamespace(*tls.CertSecretRef, namespace)
+			if err != nil {
+				return nil, err
+			}
+			certPath, err := writeTrustCertFromSecret(ctx, c, secretNamespace, tls.CertSecretRef.Name, tls.CertSecretKey)
+			if err != nil {
+				return nil, err
+			}
+			config.TLSTrustCertsFilePath = certPath
+		}
+	}
+
+	return pulsar.New(config)
+}
+
+// resolvePulsarMessaging returns the messaging settings the workloads of a component are generated with,
+// the connection referred by the component is resolved into its copies in the namespace of the component
+func resolvePulsarMessaging(ctx context.Context, c client.Client, namespace, jobName string,
+	messaging *v1alpha1.PulsarMessaging) (*v1alpha1.PulsarMessaging, error) {
+	if messaging == nil || messaging.ConnectionRef == nil {
+		return messaging, nil
+	}
+	connection, _, err := getPulsarConnection(ctx, c, namespace, messaging.ConnectionRef)
+	if err != nil {
+		return nil, err
+	}
+	return spec.MakePulsarConnectionMessaging(jobName, messaging.ConnectionRef, connection), nil
+}
+
+// applyPulsarConnection copies the connection referred by a component into the namespace of the component,
+// the object meta is the one of the statefulset of the component
+func applyPulsarConnection(ctx context.Context, c client.Client, objectMeta *metav1.ObjectMeta,
+	messaging *v1alpha1.PulsarMessaging) error {
+	if messaging == nil || messaging.ConnectionRef == nil {
+		return nil
+	}
+	connection, namespace, err := getPulsarConnection(ctx, c, objectMeta.Namespace, messaging.ConnectionRef)
+	if err != nil {
+		return err
+	}
+
+	if err := applyConfigMap(ctx, c, spec.MakePulsarConnectionConfigMap(objectMeta, connection)); err != nil {
+		return err
+	}
+
+	// only the keys read by the instances are copied from the secrets of the connection
+	if connection.Auth != nil {
+		data, err := getConnectionSecretData(ctx, c, connection.Auth.SecretRef, namespace, authPluginKey, authParamsKey)
+		if err != nil {
+			return err
+		}
+		if err := applyPulsarConnectionSecret(ctx, c, spec.MakePulsarConnectionSecret(objectMeta,
+			spec.MakePulsarConnectionObjectName(objectMeta.Name), data)); err != nil {
+			return err
+		}
+	}
+	if tls := connection.TLS; tls != nil && tls.CertSecretRef != nil && tls.CertSecretKey != "" {
+		data, err := getConnectionSecretData(ctx, c, *tls.CertSecretRef, namespace, tls.CertSecretKey)
+		if err != nil {
+			return err
+		}
+		if err := applyPulsarConnectionSecret(ctx, c, spec.MakePulsarConnectionSecret(objectMeta,
+			spec.MakePulsarConnectionTLSSecretName(objectMeta.Name), data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyPulsarConnectionSecret(ctx context.Context, c client.Client, secret *corev1.Secret) error {
+	desired := secret.DeepCopy()
+	_, err := ctrl.CreateOrUpdate(ctx, c, secret, func() error {
+		secret.Labels = desired.Labels
+		secret.OwnerReferences = desired.OwnerReferences
+		secret.Data = desired.Data
+		return nil
+	})
+	return err
+}
+
+// observePulsarConnection checks that the Pulsar cluster is reachable with the settings of the connection
+func observePulsarConnection(ctx context.Context, c client.Client, namespace string,
+	connection *v1alpha2.PulsarConnectionSpec, status *v1alpha2.PulsarConnectionStatus, generation int64) error {
+	admin, err := newPulsarConnectionAdmin(ctx, c, namespace, connection)
+	if err == nil {
+		_, err = admin.Clusters().List()
+	}
+
+	if err != nil {
+		v1alpha2.SetCondition(&status.Conditions, v1alpha2.CreateCondition(
+			v1alpha2.Reachable, metav1.ConditionFalse, v1alpha2.ReasonUnreachable, err.Error(), generation))
+	} else {
+		v1alpha2.SetCondition(&status.Conditions, v1alpha2.CreateCondition(
+			v1alpha2.Reachable, metav1.ConditionTrue, v1alpha2.ReasonReconciled, "", generation))
+	}
+	status.ObservedGeneration = generation
+	status.LastCheckTime = metav1.Now()
+	return err
+}
@@ -0,0 +1,157 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// pulsarConnectionCheckInterval is the interval of checking the connectivity of the connections
+const pulsarConnectionCheckInterval = time.Minute
+
+// nextPulsarConnectionCheck returns the time remaining before the connectivity of the connection is checked again,
+// or zero when the connection has changed since the last check or the check is due
+func nextPulsarConnectionCheck(status *v1alpha2.PulsarConnectionStatus, generation int64) time.Duration {
+	if status.ObservedGeneration != generation || status.LastCheckTime.IsZero() {
+		return 0
+	}
+	if remaining := pulsarConnectionCheckInterval - time.Since(status.LastCheckTime.Time); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// PulsarConnectionReconciler reconciles a PulsarConnection object
+type PulsarConnectionReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=compute.functionmesh.io,resources=pulsarconnections,verbs=get;list;watch
+// +kubebuilder:rbac:groups=compute.functionmesh.io,resources=pulsarconnections/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+func (r *PulsarConnectionReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+
+	connection := &v1alpha2.PulsarConnection{}
+	err := r.Get(ctx, req.NamespacedName, connection)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		r.Log.Error(err, "failed to get pulsar connection")
+		return ctrl.Result{}, err
+	}
+
+	// the status update of the last check triggers a reconcile, the connection is only checked again
+	// when it has changed or the interval has elapsed
+	if next := nextPulsarConnectionCheck(&connection.Status, connection.Generation); next > 0 {
+		return ctrl.Result{RequeueAfter: next}, nil
+	}
+
+	wasReachable := v1alpha2.FindCondition(connection.Status.Conditions, v1alpha2.Reachable)
+	checkErr := observePulsarConnection(ctx, r.Client, connection.Namespace, &connection.Spec, &connection.Status,
+		connection.Generation)
+	recordReachability(r.Recorder, connection, wasReachable, checkErr)
+
+	if err := r.Status().Update(ctx, connection); err != nil {
+		r.Log.Error(err, "failed to update pulsar connection status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: pulsarConnectionCheckInterval}, nil
+}
+
+func (r *PulsarConnectionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha2.PulsarConnection{}).
+		Complete(r)
+}
+
+// ClusterPulsarConnectionReconciler reconciles a ClusterPulsarConnection object
+type ClusterPulsarConnectionReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=compute.functionmesh.io,resources=clusterpulsarconnections,verbs=get;list;watch
+// +kubebuilder:rbac:groups=compute.functionmesh.io,resources=clusterpulsarconnections/status,verbs=get;update;patch
+
+func (r *ClusterPulsarConnectionReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+
+	connection := &v1alpha2.ClusterPulsarConnection{}
+	err := r.Get(ctx, req.NamespacedName, connection)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		r.Log.Error(err, "failed to get cluster pulsar connection")
+		return ctrl.Result{}, err
+	}
+
+	// the status update of the last check triggers a reconcile, the connection is only checked again
+	// when it has changed or the interval has elapsed
+	if next := nextPulsarConnectionCheck(&connection.Status, connection.Generation); next > 0 {
+		return ctrl.Result{RequeueAfter: next}, nil
+	}
+
+	wasReachable := v1alpha2.FindCondition(connection.Status.Conditions, v1alpha2.Reachable)
+	checkErr := observePulsarConnection(ctx, r.Client, "", &connection.Spec, &connection.Status, connection.Generation)
+	recordReachability(r.Recorder, connection, wasReachable, checkErr)
+
+	if err := r.Status().Update(ctx, connection); err != nil {
+		r.Log.Error(err, "failed to update cluster pulsar connection status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: pulsarConnectionCheckInterval}, nil
+}
+
+func (r *ClusterPulsarConnectionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha2.ClusterPulsarConnection{}).
+		Complete(r)
+}
+
+// recordReachability records an event when the reachability of the connection changes
+func recordReachability(recorder record.EventRecorder, obj runtime.Object, previous *v1alpha2.Condition, err error) {
+	if err != nil {
+		if previous == nil || previous.Status != metav1.ConditionFalse {
+			recorder.Eventf(obj, corev1.EventTypeWarning, EventReasonUnreachable, "Pulsar cluster is unreachable: %v", err)
+		}
+		return
+	}
+	if previous == nil || previous.Status != metav1.ConditionTrue {
+		recorder.Event(obj, corev1.EventTypeNormal, EventReasonReachable, "Pulsar cluster is reachable")
+	}
+}
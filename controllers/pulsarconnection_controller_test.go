@@ -0,0 +1,68 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNextPulsarConnectionCheck(t *testing.T) {
+	testCases := []struct {
+		name               string
+		observedGeneration int64
+		lastCheckTime      metav1.Time
+		min, max           time.Duration
+	}{
+		{
+			name:               "never checked",
+			observedGeneration: 1,
+		},
+		{
+			name:               "just checked",
+			observedGeneration: 1,
+			lastCheckTime:      metav1.Now(),
+			min:                pulsarConnectionCheckInterval - 10*time.Second,
+			max:                pulsarConnectionCheckInterval,
+		},
+		{
+			name:               "check overdue",
+			observedGeneration: 1,
+			lastCheckTime:      metav1.NewTime(time.Now().Add(-2 * pulsarConnectionCheckInterval)),
+		},
+		{
+			name:               "connection changed",
+			observedGeneration: 0,
+			lastCheckTime:      metav1.Now(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			next := nextPulsarConnectionCheck(&v1alpha2.PulsarConnectionStatus{
+				ObservedGeneration: tc.observedGeneration,
+				LastCheckTime:      tc.lastCheckTime,
+			}, 1)
+			assert.Assert(t, next >= tc.min && next <= tc.max, next)
+		})
+	}
+}
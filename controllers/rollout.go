@@ -0,0 +1,204 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func meshRolloutStrategy(mesh *v1alpha2.FunctionMesh) v1alpha1.MeshRolloutStrategyType {
+	if mesh.Spec.RolloutStrategy == nil || mesh.Spec.RolloutStrategy.Type == "" {
+		return v1alpha1.ParallelMeshRollout
+	}
+	return mesh.Spec.RolloutStrategy.Type
+}
+
+// makeRolloutStages returns the components of the mesh in the order they are created and updated,
+// the consumers of a topic are in an earlier stage than its producers with the ordered rollout
+func makeRolloutStages(mesh *v1alpha2.FunctionMesh) [][]*v1alpha1.TopicGraphNode {
+	graph := v1alpha1.NewTopicGraph(&mesh.Spec)
+	if meshRolloutStrategy(mesh) == v1alpha1.OrderedMeshRollout {
+		return graph.ConsumerFirstStages()
+	}
+	if len(graph.Nodes) == 0 {
+		return nil
+	}
+	return [][]*v1alpha1.TopicGraphNode{graph.Nodes}
+}
+
+// rolloutComponents creates or updates the components stage by stage, a stage is rolled out once
+// the components of the previous stages are ready, and reports the progress in the status of the mesh
+func (r *FunctionMeshReconciler) rolloutComponents(ctx context.Context, mesh *v1alpha2.FunctionMesh) error {
+	stages := makeRolloutStages(mesh)
+	status := &v1alpha2.MeshRolloutStatus{
+		Strategy:     meshRolloutStrategy(mesh),
+		CurrentStage: int32(len(stages)),
+	}
+	for _, stage := range stages {
+		ids := make([]string, 0, len(stage))
+		for _, node := range stage {
+			ids = append(ids, node.ID)
+		}
+		status.Stages = append(status.Stages, ids)
+	}
+	defer func() {
+		mesh.Status.Rollout = status
+	}()
+
+	for i, stage := range stages {
+		var waiting []string
+		for _, node := range stage {
+			result, err := r.applyMeshComponent(ctx, mesh, node)
+			if err != nil {
+				status.CurrentStage = int32(i)
+				return err
+			}
+			// the readiness is observed before the update, the component is ready again on a later reconcile
+			if result != controllerutil.OperationResultNone ||
+				spec.MeshComponentReadiness(mesh, node.Kind, node.Name) != metav1.ConditionTrue {
+				waiting = append(waiting, node.ID)
+			}
+		}
+		if len(waiting) > 0 {
+			status.CurrentStage = int32(i)
+			status.Waiting = waiting
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *FunctionMeshReconciler) applyMeshComponent(ctx context.Context, mesh *v1alpha2.FunctionMesh,
+	node *v1alpha1.TopicGraphNode) (controllerutil.OperationResult, error) {
+	name := makeComponentName(mesh.Name, node.Name)
+	// the desired spec is a copy, the stored component is decoded into the object and would overwrite
+	// the values it shares with the object
+	switch node.Kind {
+	case v1alpha1.FunctionComponent:
+		for i := range mesh.Spec.Functions {
+			if functionSpec := &mesh.Spec.Functions[i]; functionSpec.Name == node.Name {
+				function := spec.MakeFunctionComponent(name, mesh, functionSpec)
+				return r.CreateOrUpdateFunction(ctx, mesh, function, *function.Spec.DeepCopy())
+			}
+		}
+	case v1alpha1.SourceComponent:
+		for i := range mesh.Spec.Sources {
+			if sourceSpec := &mesh.Spec.Sources[i]; sourceSpec.Name == node.Name {
+				source := spec.MakeSourceComponent(name, mesh, sourceSpec)
+				return r.CreateOrUpdateSource(ctx, mesh, source, *source.Spec.DeepCopy())
+			}
+		}
+	case v1alpha1.SinkComponent:
+		for i := range mesh.Spec.Sinks {
+			if sinkSpec := &mesh.Spec.Sinks[i]; sinkSpec.Name == node.Name {
+				sink := spec.MakeSinkComponent(name, mesh, sinkSpec)
+				return r.CreateOrUpdateSink(ctx, mesh, sink, *sink.Spec.DeepCopy())
+			}
+		}
+	}
+	return controllerutil.OperationResultNone, nil
+}
+
+// reconcileOrderedDeletion keeps the ordered deletion finalizer in sync with the rollout strategy of the mesh,
+// and deletes the components of a deleted mesh in the reverse order of the rollout, the producers of a topic
+// before its consumers. It returns true if the mesh is being deleted.
+func (r *FunctionMeshReconciler) reconcileOrderedDeletion(ctx context.Context, mesh *v1alpha2.FunctionMesh) (bool, error) {
+	if mesh.DeletionTimestamp.IsZero() {
+		ordered := meshRolloutStrategy(mesh) == v1alpha1.OrderedMeshRollout
+		if ordered != controllerutil.ContainsFinalizer(mesh, spec.OrderedDeletionFinalizerName) {
+			if ordered {
+				controllerutil.AddFinalizer(mesh, spec.OrderedDeletionFinalizerName)
+			} else {
+				controllerutil.RemoveFinalizer(mesh, spec.OrderedDeletionFinalizerName)
+			}
+			return false, r.Update(ctx, mesh)
+		}
+		return false, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(mesh, spec.OrderedDeletionFinalizerName) {
+		return true, nil
+	}
+
+	stages := makeRolloutStages(mesh)
+	for i := len(stages) - 1; i >= 0; i-- {
+		pending := false
+		for _, node := range stages[i] {
+			exists, err := r.deleteMeshComponent(ctx, mesh, node)
+			if err != nil {
+				return true, err
+			}
+			pending = pending || exists
+		}
+		if pending {
+			// the mesh is reconciled again once the components are gone
+			return true, nil
+		}
+	}
+
+	controllerutil.RemoveFinalizer(mesh, spec.OrderedDeletionFinalizerName)
+	return true, r.Update(ctx, mesh)
+}
+
+// deleteMeshComponent deletes the component of the mesh, it returns true while the component still exists
+func (r *FunctionMeshReconciler) deleteMeshComponent(ctx context.Context, mesh *v1alpha2.FunctionMesh,
+	node *v1alpha1.TopicGraphNode) (bool, error) {
+	var obj interface {
+		runtime.Object
+		metav1.Object
+	}
+	var kind string
+	switch node.Kind {
+	case v1alpha1.FunctionComponent:
+		obj, kind = &v1alpha2.Function{}, "Function"
+	case v1alpha1.SourceComponent:
+		obj, kind = &v1alpha2.Source{}, "Source"
+	case v1alpha1.SinkComponent:
+		obj, kind = &v1alpha2.Sink{}, "Sink"
+	default:
+		return false, nil
+	}
+
+	name := makeComponentName(mesh.Name, node.Name)
+	if err := r.Get(ctx, types.NamespacedName{Namespace: mesh.Namespace, Name: name}, obj); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return true, nil
+	}
+	if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+		r.Recorder.Eventf(mesh, corev1.EventTypeWarning, EventReasonFailedDelete,
+			"Failed to delete %s %s: %v", kind, name, err)
+		return false, err
+	}
+	r.Recorder.Eventf(mesh, corev1.EventTypeNormal, EventReasonDeleted, "Deleted %s %s", kind, name)
+	return true, nil
+}
@@ -0,0 +1,263 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// makeRolloutTestMesh returns a mesh with a source producing the input of a function,
+// which produces the input of a sink
+func makeRolloutTestMesh(strategy v1alpha1.MeshRolloutStrategyType) *v1alpha2.FunctionMesh {
+	return &v1alpha2.FunctionMesh{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "compute.functionmesh.io/v1alpha2",
+			Kind:       "FunctionMesh",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mesh",
+			Namespace: "default",
+			UID:       types.UID("mesh"),
+		},
+		Spec: v1alpha1.FunctionMeshSpec{
+			Sources: []v1alpha1.SourceSpec{{Name: "src", Output: v1alpha1.OutputConf{Topic: "a"}}},
+			Functions: []v1alpha1.FunctionSpec{{Name: "fn", Input: v1alpha1.InputConf{Topics: []string{"a"}},
+				Output: v1alpha1.OutputConf{Topic: "b"}}},
+			Sinks:           []v1alpha1.SinkSpec{{Name: "sink", Input: v1alpha1.InputConf{Topics: []string{"b"}}}},
+			RolloutStrategy: &v1alpha1.MeshRolloutStrategy{Type: strategy},
+		},
+		Status: v1alpha2.FunctionMeshStatus{
+			SourceConditions:   map[string]v1alpha2.Condition{},
+			FunctionConditions: map[string]v1alpha2.Condition{},
+			SinkConditions:     map[string]v1alpha2.Condition{},
+		},
+	}
+}
+
+func newRolloutTestReconciler(c client.Client) *FunctionMeshReconciler {
+	return &FunctionMeshReconciler{
+		Client:   c,
+		Log:      ctrl.Log.WithName("test"),
+		Recorder: record.NewFakeRecorder(100),
+	}
+}
+
+func componentExists(t *testing.T, c client.Client, obj runtime.Object, name string) bool {
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: name}, obj)
+	if errors.IsNotFound(err) {
+		return false
+	}
+	assert.NilError(t, err)
+	return true
+}
+
+func setComponentsReady(mesh *v1alpha2.FunctionMesh) {
+	ready := func(conditionType string) v1alpha2.Condition {
+		return v1alpha2.CreateCondition(conditionType, metav1.ConditionTrue, v1alpha2.ReasonReconciled, "",
+			mesh.Generation)
+	}
+	mesh.Status.SourceConditions["src"] = ready(v1alpha2.SourceReady)
+	mesh.Status.FunctionConditions["fn"] = ready(v1alpha2.FunctionReady)
+	mesh.Status.SinkConditions["sink"] = ready(v1alpha2.SinkReady)
+}
+
+func TestRolloutComponentsOrdered(t *testing.T) {
+	mesh := makeRolloutTestMesh(v1alpha1.OrderedMeshRollout)
+	c := newFinalizerTestClient(t, mesh)
+	r := newRolloutTestReconciler(c)
+	stages := [][]string{{"sink/sink"}, {"function/fn"}, {"source/src"}}
+
+	// the consumers are created first, the next stage waits for them to be ready
+	assert.NilError(t, r.rolloutComponents(context.TODO(), mesh))
+	assert.DeepEqual(t, mesh.Status.Rollout, &v1alpha2.MeshRolloutStatus{
+		Strategy:     v1alpha1.OrderedMeshRollout,
+		Stages:       stages,
+		CurrentStage: 0,
+		Waiting:      []string{"sink/sink"},
+	})
+	assert.Assert(t, componentExists(t, c, &v1alpha2.Sink{}, "mesh-sink"))
+	assert.Assert(t, !componentExists(t, c, &v1alpha2.Function{}, "mesh-fn"))
+	assert.Assert(t, !componentExists(t, c, &v1alpha2.Source{}, "mesh-src"))
+
+	mesh.Status.SinkConditions["sink"] = v1alpha2.CreateCondition(v1alpha2.SinkReady, metav1.ConditionTrue,
+		v1alpha2.ReasonReconciled, "", mesh.Generation)
+	assert.NilError(t, r.rolloutComponents(context.TODO(), mesh))
+	assert.Equal(t, mesh.Status.Rollout.CurrentStage, int32(1))
+	assert.DeepEqual(t, mesh.Status.Rollout.Waiting, []string{"function/fn"})
+	assert.Assert(t, componentExists(t, c, &v1alpha2.Function{}, "mesh-fn"))
+	assert.Assert(t, !componentExists(t, c, &v1alpha2.Source{}, "mesh-src"))
+
+	// the components are ready once created and unchanged
+	setComponentsReady(mesh)
+	assert.NilError(t, r.rolloutComponents(context.TODO(), mesh))
+	assert.Equal(t, mesh.Status.Rollout.CurrentStage, int32(2))
+	assert.DeepEqual(t, mesh.Status.Rollout.Waiting, []string{"source/src"})
+	assert.Assert(t, componentExists(t, c, &v1alpha2.Source{}, "mesh-src"))
+
+	assert.NilError(t, r.rolloutComponents(context.TODO(), mesh))
+	assert.DeepEqual(t, mesh.Status.Rollout, &v1alpha2.MeshRolloutStatus{
+		Strategy:     v1alpha1.OrderedMeshRollout,
+		Stages:       stages,
+		CurrentStage: 3,
+	})
+
+	// an update of a component waits for it to be ready again
+	mesh.Spec.Functions[0].Replicas = pointer.Int32Ptr(2)
+	assert.NilError(t, r.rolloutComponents(context.TODO(), mesh))
+	assert.Equal(t, mesh.Status.Rollout.CurrentStage, int32(1))
+	assert.DeepEqual(t, mesh.Status.Rollout.Waiting, []string{"function/fn"})
+}
+
+func TestRolloutComponentsParallel(t *testing.T) {
+	mesh := makeRolloutTestMesh(v1alpha1.ParallelMeshRollout)
+	c := newFinalizerTestClient(t, mesh)
+	r := newRolloutTestReconciler(c)
+
+	assert.NilError(t, r.rolloutComponents(context.TODO(), mesh))
+	assert.Equal(t, mesh.Status.Rollout.Strategy, v1alpha1.ParallelMeshRollout)
+	assert.Equal(t, len(mesh.Status.Rollout.Stages), 1)
+	assert.Equal(t, mesh.Status.Rollout.CurrentStage, int32(0))
+	assert.Equal(t, len(mesh.Status.Rollout.Waiting), 3)
+	assert.Assert(t, componentExists(t, c, &v1alpha2.Source{}, "mesh-src"))
+	assert.Assert(t, componentExists(t, c, &v1alpha2.Function{}, "mesh-fn"))
+	assert.Assert(t, componentExists(t, c, &v1alpha2.Sink{}, "mesh-sink"))
+
+	setComponentsReady(mesh)
+	assert.NilError(t, r.rolloutComponents(context.TODO(), mesh))
+	assert.Equal(t, mesh.Status.Rollout.CurrentStage, int32(1))
+	assert.Equal(t, len(mesh.Status.Rollout.Waiting), 0)
+}
+
+// defaultStoredComponents applies the defaulting of the component webhooks to the stored components,
+// which the fake client does not call
+func defaultStoredComponents(t *testing.T, c client.Client) {
+	source := &v1alpha2.Source{}
+	assert.Assert(t, componentExists(t, c, source, "mesh-src"))
+	sourceHub := &v1alpha1.Source{ObjectMeta: source.ObjectMeta, Spec: source.Spec}
+	sourceHub.Default()
+	source.Spec = sourceHub.Spec
+	assert.NilError(t, c.Update(context.TODO(), source))
+
+	function := &v1alpha2.Function{}
+	assert.Assert(t, componentExists(t, c, function, "mesh-fn"))
+	functionHub := &v1alpha1.Function{ObjectMeta: function.ObjectMeta, Spec: function.Spec}
+	functionHub.Default()
+	function.Spec = functionHub.Spec
+	assert.NilError(t, c.Update(context.TODO(), function))
+
+	sink := &v1alpha2.Sink{}
+	assert.Assert(t, componentExists(t, c, sink, "mesh-sink"))
+	sinkHub := &v1alpha1.Sink{ObjectMeta: sink.ObjectMeta, Spec: sink.Spec}
+	sinkHub.Default()
+	sink.Spec = sinkHub.Spec
+	assert.NilError(t, c.Update(context.TODO(), sink))
+}
+
+func TestRolloutComponentsDefaulted(t *testing.T) {
+	mesh := makeRolloutTestMesh(v1alpha1.OrderedMeshRollout)
+	mesh.Spec.Defaults = &v1alpha1.MeshComponentDefaults{
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+		},
+	}
+	c := newFinalizerTestClient(t, mesh)
+	r := newRolloutTestReconciler(c)
+
+	for stage := int32(0); stage < 3; stage++ {
+		assert.NilError(t, r.rolloutComponents(context.TODO(), mesh))
+		assert.Equal(t, mesh.Status.Rollout.CurrentStage, stage)
+		setComponentsReady(mesh)
+	}
+	defaultStoredComponents(t, c)
+
+	// the components defaulted by their webhooks are unchanged, so that the rollout passes the ready stages
+	assert.NilError(t, r.rolloutComponents(context.TODO(), mesh))
+	assert.Equal(t, mesh.Status.Rollout.CurrentStage, int32(3))
+	assert.Equal(t, len(mesh.Status.Rollout.Waiting), 0)
+}
+
+func TestReconcileOrderedDeletionFinalizer(t *testing.T) {
+	mesh := makeRolloutTestMesh(v1alpha1.OrderedMeshRollout)
+	c := newFinalizerTestClient(t, mesh)
+	r := newRolloutTestReconciler(c)
+
+	deleting, err := r.reconcileOrderedDeletion(context.TODO(), mesh)
+	assert.NilError(t, err)
+	assert.Assert(t, !deleting)
+	stored := &v1alpha2.FunctionMesh{}
+	assert.NilError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "mesh"}, stored))
+	assert.Assert(t, controllerutil.ContainsFinalizer(stored, spec.OrderedDeletionFinalizerName))
+
+	mesh.Spec.RolloutStrategy = nil
+	deleting, err = r.reconcileOrderedDeletion(context.TODO(), mesh)
+	assert.NilError(t, err)
+	assert.Assert(t, !deleting)
+	stored = &v1alpha2.FunctionMesh{}
+	assert.NilError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "mesh"}, stored))
+	assert.Assert(t, !controllerutil.ContainsFinalizer(stored, spec.OrderedDeletionFinalizerName))
+}
+
+func TestReconcileOrderedDeletion(t *testing.T) {
+	mesh := makeRolloutTestMesh(v1alpha1.OrderedMeshRollout)
+	mesh.Finalizers = []string{spec.OrderedDeletionFinalizerName}
+	deletionTimestamp := metav1.Now()
+	mesh.DeletionTimestamp = &deletionTimestamp
+	c := newFinalizerTestClient(t, mesh,
+		spec.MakeSourceComponent("mesh-src", mesh, &mesh.Spec.Sources[0]),
+		spec.MakeFunctionComponent("mesh-fn", mesh, &mesh.Spec.Functions[0]),
+		spec.MakeSinkComponent("mesh-sink", mesh, &mesh.Spec.Sinks[0]))
+	r := newRolloutTestReconciler(c)
+
+	// the producers are deleted before their consumers, one stage per reconcile
+	for _, remaining := range [][]bool{{false, true, true}, {false, false, true}, {false, false, false}} {
+		deleting, err := r.reconcileOrderedDeletion(context.TODO(), mesh)
+		assert.NilError(t, err)
+		assert.Assert(t, deleting)
+		assert.DeepEqual(t, []bool{
+			componentExists(t, c, &v1alpha2.Source{}, "mesh-src"),
+			componentExists(t, c, &v1alpha2.Function{}, "mesh-fn"),
+			componentExists(t, c, &v1alpha2.Sink{}, "mesh-sink"),
+		}, remaining)
+		assert.Assert(t, controllerutil.ContainsFinalizer(mesh, spec.OrderedDeletionFinalizerName))
+	}
+
+	deleting, err := r.reconcileOrderedDeletion(context.TODO(), mesh)
+	assert.NilError(t, err)
+	assert.Assert(t, deleting)
+	assert.Assert(t, !controllerutil.ContainsFinalizer(mesh, spec.OrderedDeletionFinalizerName))
+}
@@ -19,9 +19,10 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 
-	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
 	"github.com/streamnative/function-mesh/controllers/spec"
 	appsv1 "k8s.io/api/apps/v1"
 	autov2beta2 "k8s.io/api/autoscaling/v2beta2"
@@ -33,12 +34,9 @@ import (
 )
 
 func (r *SinkReconciler) ObserveSinkStatefulSet(ctx context.Context, req ctrl.Request,
-	sink *v1alpha1.Sink) error {
-	condition := v1alpha1.ResourceCondition{
-		Condition: v1alpha1.StatefulSetReady,
-		Status:    metav1.ConditionFalse,
-		Action:    v1alpha1.NoAction,
-	}
+	sink *v1alpha2.Sink) error {
+	condition := v1alpha2.CreateCondition(v1alpha2.StatefulSetReady, metav1.ConditionFalse,
+		v1alpha2.ReasonReconciled, "", sink.Generation)
 
 	statefulSet := &appsv1.StatefulSet{}
 	err := r.Get(ctx, types.NamespacedName{
@@ -48,28 +46,39 @@ func (r *SinkReconciler) ObserveSinkStatefulSet(ctx context.Context, req ctrl.Re
 	if err != nil {
 		if errors.IsNotFound(err) {
 			r.Log.Info("sink statefulset is not found...")
-			condition.Action = v1alpha1.Create
-			sink.Status.Conditions[v1alpha1.StatefulSet] = condition
+			condition.Reason = v1alpha2.ReasonCreating
+			condition.Message = "statefulset is not created"
+			v1alpha2.SetCondition(&sink.Status.Conditions, condition)
 			return nil
 		}
 
-		sink.Status.Conditions[v1alpha1.StatefulSet] = condition
+		v1alpha2.SetCondition(&sink.Status.Conditions, condition)
 		return err
 	}
 
 	// statefulset created, waiting it to be ready
-	condition.Action = v1alpha1.Wait
+	desiredStatefulSet, err := r.makeSinkStatefulSet(ctx, sink)
+	if err != nil {
+		return err
+	}
+	condition.Reason = v1alpha2.ReasonWaiting
+	condition.Message = fmt.Sprintf("%d of %d replicas are ready", statefulSet.Status.ReadyReplicas, *desiredStatefulSet.Spec.Replicas)
 
-	if *statefulSet.Spec.Replicas != *sink.Spec.Replicas || !reflect.DeepEqual(statefulSet.Spec.Template, spec.MakeSinkStatefulSet(sink).Spec.Template) {
-		condition.Action = v1alpha1.Update
+	if *statefulSet.Spec.Replicas != *desiredStatefulSet.Spec.Replicas || !reflect.DeepEqual(statefulSet.Spec.Template, desiredStatefulSet.Spec.Template) {
+		condition.Reason = v1alpha2.ReasonUpdating
+		condition.Message = "statefulset is outdated"
 	}
 
-	if statefulSet.Status.ReadyReplicas == *sink.Spec.Replicas {
+	if statefulSet.Status.ReadyReplicas == *desiredStatefulSet.Spec.Replicas {
 		condition.Status = metav1.ConditionTrue
+		if condition.Reason == v1alpha2.ReasonWaiting {
+			condition.Reason = v1alpha2.ReasonReconciled
+			condition.Message = ""
+		}
 	}
 
 	sink.Status.Replicas = statefulSet.Status.Replicas
-	sink.Status.Conditions[v1alpha1.StatefulSet] = condition
+	v1alpha2.SetCondition(&sink.Status.Conditions, condition)
 
 	selector, err := metav1.LabelSelectorAsSelector(statefulSet.Spec.Selector)
 	if err != nil {
@@ -80,8 +89,25 @@ func (r *SinkReconciler) ObserveSinkStatefulSet(ctx context.Context, req ctrl.Re
 	return nil
 }
 
-func (r *SinkReconciler) ApplySinkStatefulSet(ctx context.Context, sink *v1alpha1.Sink) error {
-	desiredStatefulSet := spec.MakeSinkStatefulSet(sink)
+func (r *SinkReconciler) ApplySinkStatefulSet(ctx context.Context, sink *v1alpha2.Sink) error {
+	if err := applyPulsarConnection(ctx, r.Client, spec.MakeSinkObjectMeta(sink), sink.Spec.Pulsar); err != nil {
+		r.Log.Error(err, "error copying the pulsar connection", "namespace", sink.Namespace, "name", sink.Name)
+		r.Recorder.Eventf(sink, corev1.EventTypeWarning, EventReasonFailedUpdate,
+			"Failed to copy PulsarConnection %s: %v", sink.Spec.Pulsar.ConnectionRef.Name, err)
+		return err
+	}
+	// the details are applied before the statefulset, so that the pods started by the update read them
+	detailsConfigMap := spec.MakeSinkDetailsConfigMap(sink)
+	if err := applyConfigMap(ctx, r.Client, detailsConfigMap); err != nil {
+		r.Log.Error(err, "error create or update details configmap", "namespace", sink.Namespace, "name", detailsConfigMap.Name)
+		r.Recorder.Eventf(sink, corev1.EventTypeWarning, EventReasonFailedUpdate,
+			"Failed to create or update ConfigMap %s: %v", detailsConfigMap.Name, err)
+		return err
+	}
+	desiredStatefulSet, err := r.makeSinkStatefulSet(ctx, sink)
+	if err != nil {
+		return err
+	}
 	desiredStatefulSetSpec := desiredStatefulSet.Spec
 	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, desiredStatefulSet, func() error {
 		// sink statefulset mutate logic
@@ -89,17 +115,30 @@ func (r *SinkReconciler) ApplySinkStatefulSet(ctx context.Context, sink *v1alpha
 		return nil
 	}); err != nil {
 		r.Log.Error(err, "error create or update statefulSet workload", "namespace", desiredStatefulSet.Namespace, "name", desiredStatefulSet.Name)
+		r.Recorder.Eventf(sink, corev1.EventTypeWarning, EventReasonFailedUpdate,
+			"Failed to create or update StatefulSet %s: %v", desiredStatefulSet.Name, err)
 		return err
 	}
+
+	// the statefulset is updated on every reconciliation, the events are only recorded for observed changes
+	condition := v1alpha2.FindCondition(sink.Status.Conditions, v1alpha2.StatefulSetReady)
+	if condition != nil {
+		switch condition.Reason {
+		case v1alpha2.ReasonCreating:
+			r.Recorder.Eventf(sink, corev1.EventTypeNormal, EventReasonCreated,
+				"Created StatefulSet %s", desiredStatefulSet.Name)
+		case v1alpha2.ReasonUpdating:
+			statefulSetDriftUpdates.WithLabelValues(kindSink).Inc()
+			r.Recorder.Eventf(sink, corev1.EventTypeNormal, EventReasonUpdated,
+				"Updated StatefulSet %s", desiredStatefulSet.Name)
+		}
+	}
 	return nil
 }
 
-func (r *SinkReconciler) ObserveSinkService(ctx context.Context, req ctrl.Request, sink *v1alpha1.Sink) error {
-	condition := v1alpha1.ResourceCondition{
-		Condition: v1alpha1.ServiceReady,
-		Status:    metav1.ConditionFalse,
-		Action:    v1alpha1.NoAction,
-	}
+func (r *SinkReconciler) ObserveSinkService(ctx context.Context, req ctrl.Request, sink *v1alpha2.Sink) error {
+	condition := v1alpha2.CreateCondition(v1alpha2.ServiceReady, metav1.ConditionFalse,
+		v1alpha2.ReasonReconciled, "", sink.Generation)
 
 	svc := &corev1.Service{}
 	svcName := spec.MakeHeadlessServiceName(spec.MakeSinkObjectMeta(sink).Name)
@@ -107,10 +146,11 @@ func (r *SinkReconciler) ObserveSinkService(ctx context.Context, req ctrl.Reques
 		Name: svcName}, svc)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			condition.Action = v1alpha1.Create
+			condition.Reason = v1alpha2.ReasonCreating
+			condition.Message = "service is not created"
 			r.Log.Info("service is not created...", "Name", sink.Name, "ServiceName", svcName)
 		} else {
-			sink.Status.Conditions[v1alpha1.Service] = condition
+			v1alpha2.SetCondition(&sink.Status.Conditions, condition)
 			return err
 		}
 	} else {
@@ -118,44 +158,46 @@ func (r *SinkReconciler) ObserveSinkService(ctx context.Context, req ctrl.Reques
 		condition.Status = metav1.ConditionTrue
 	}
 
-	sink.Status.Conditions[v1alpha1.Service] = condition
+	v1alpha2.SetCondition(&sink.Status.Conditions, condition)
 	return nil
 }
 
-func (r *SinkReconciler) ApplySinkService(ctx context.Context, req ctrl.Request, sink *v1alpha1.Sink) error {
-	condition := sink.Status.Conditions[v1alpha1.Service]
+func (r *SinkReconciler) ApplySinkService(ctx context.Context, req ctrl.Request, sink *v1alpha2.Sink) error {
+	condition := v1alpha2.FindCondition(sink.Status.Conditions, v1alpha2.ServiceReady)
 
-	if condition.Status == metav1.ConditionTrue {
+	if condition == nil || condition.Status == metav1.ConditionTrue {
 		return nil
 	}
 
-	switch condition.Action {
-	case v1alpha1.Create:
+	switch condition.Reason {
+	case v1alpha2.ReasonCreating:
 		svc := spec.MakeSinkService(sink)
 		if err := r.Create(ctx, svc); err != nil {
 			r.Log.Error(err, "failed to expose service for sink", "name", sink.Name)
+			r.Recorder.Eventf(sink, corev1.EventTypeWarning, EventReasonFailedCreate,
+				"Failed to create Service %s: %v", svc.Name, err)
 			return err
 		}
-	case v1alpha1.Wait, v1alpha1.NoAction:
+		r.Recorder.Eventf(sink, corev1.EventTypeNormal, EventReasonCreated, "Created Service %s", svc.Name)
+	case v1alpha2.ReasonWaiting, v1alpha2.ReasonReconciled:
 		// do nothing
 	}
 
 	return nil
 }
 
-func (r *SinkReconciler) ObserveSinkHPA(ctx context.Context, req ctrl.Request, sink *v1alpha1.Sink) error {
-	if sink.Spec.MaxReplicas == nil {
-		// HPA not enabled, skip further action
+func (r *SinkReconciler) ObserveSinkHPA(ctx context.Context, req ctrl.Request, sink *v1alpha2.Sink) error {
+	if sink.Spec.MaxReplicas == nil || sink.Spec.Suspend {
+		// HPA not enabled or removed while suspended, skip further action
+		v1alpha2.RemoveCondition(&sink.Status.Conditions, v1alpha2.HPAReady)
 		return nil
 	}
 
-	condition, ok := sink.Status.Conditions[v1alpha1.HPA]
-	if !ok {
-		sink.Status.Conditions[v1alpha1.HPA] = v1alpha1.ResourceCondition{
-			Condition: v1alpha1.HPAReady,
-			Status:    metav1.ConditionFalse,
-			Action:    v1alpha1.Create,
-		}
+	condition := v1alpha2.FindCondition(sink.Status.Conditions, v1alpha2.HPAReady)
+	if condition == nil {
+		v1alpha2.SetCondition(&sink.Status.Conditions, v1alpha2.CreateCondition(
+			v1alpha2.HPAReady, metav1.ConditionFalse, v1alpha2.ReasonCreating,
+			"hpa is not created", sink.Generation))
 		return nil
 	}
 
@@ -174,43 +216,53 @@ func (r *SinkReconciler) ObserveSinkHPA(ctx context.Context, req ctrl.Request, s
 		return err
 	}
 
+	// the HPA takes the metrics of the builtin rules, or the default ones when no metrics are set
+	desired := spec.MakeSinkHPA(sink)
 	if hpa.Spec.MaxReplicas != *sink.Spec.MaxReplicas ||
-		!reflect.DeepEqual(hpa.Spec.Metrics, sink.Spec.Pod.AutoScalingMetrics) ||
+		!reflect.DeepEqual(hpa.Spec.Metrics, desired.Spec.Metrics) ||
 		(sink.Spec.Pod.AutoScalingBehavior != nil && hpa.Spec.Behavior == nil) ||
 		(sink.Spec.Pod.AutoScalingBehavior != nil && hpa.Spec.Behavior != nil &&
 			!reflect.DeepEqual(*hpa.Spec.Behavior, *sink.Spec.Pod.AutoScalingBehavior)) {
-		condition.Status = metav1.ConditionFalse
-		condition.Action = v1alpha1.Update
-		sink.Status.Conditions[v1alpha1.HPA] = condition
+		v1alpha2.SetCondition(&sink.Status.Conditions, v1alpha2.CreateCondition(
+			v1alpha2.HPAReady, metav1.ConditionFalse, v1alpha2.ReasonUpdating,
+			"hpa is outdated", sink.Generation))
 		return nil
 	}
 
-	condition.Action = v1alpha1.NoAction
-	condition.Status = metav1.ConditionTrue
-	sink.Status.Conditions[v1alpha1.HPA] = condition
+	v1alpha2.SetCondition(&sink.Status.Conditions, v1alpha2.CreateCondition(
+		v1alpha2.HPAReady, metav1.ConditionTrue, v1alpha2.ReasonReconciled, "", sink.Generation))
 	return nil
 }
 
-func (r *SinkReconciler) ApplySinkHPA(ctx context.Context, req ctrl.Request, sink *v1alpha1.Sink) error {
+func (r *SinkReconciler) ApplySinkHPA(ctx context.Context, req ctrl.Request, sink *v1alpha2.Sink) error {
 	if sink.Spec.MaxReplicas == nil {
 		// HPA not enabled, skip further action
 		return nil
 	}
 
-	condition := sink.Status.Conditions[v1alpha1.HPA]
+	if sink.Spec.Suspend {
+		// the HPA would scale the suspended statefulset up again, it is recreated once resumed
+		return r.deleteHPA(ctx, sink)
+	}
 
-	if condition.Status == metav1.ConditionTrue {
+	condition := v1alpha2.FindCondition(sink.Status.Conditions, v1alpha2.HPAReady)
+
+	if condition == nil || condition.Status == metav1.ConditionTrue {
 		return nil
 	}
 
-	switch condition.Action {
-	case v1alpha1.Create:
+	switch condition.Reason {
+	case v1alpha2.ReasonCreating:
 		hpa := spec.MakeSinkHPA(sink)
 		if err := r.Create(ctx, hpa); err != nil {
 			r.Log.Error(err, "failed to create pod autoscaler for sink", "name", sink.Name)
+			r.Recorder.Eventf(sink, corev1.EventTypeWarning, EventReasonFailedCreate,
+				"Failed to create HorizontalPodAutoscaler %s: %v", hpa.Name, err)
 			return err
 		}
-	case v1alpha1.Update:
+		r.Recorder.Eventf(sink, corev1.EventTypeNormal, EventReasonCreated,
+			"Created HorizontalPodAutoscaler %s", hpa.Name)
+	case v1alpha2.ReasonUpdating:
 		hpa := &autov2beta2.HorizontalPodAutoscaler{}
 		err := r.Get(ctx, types.NamespacedName{Namespace: sink.Namespace,
 			Name: spec.MakeSinkObjectMeta(sink).Name}, hpa)
@@ -221,25 +273,81 @@ func (r *SinkReconciler) ApplySinkHPA(ctx context.Context, req ctrl.Request, sin
 		if hpa.Spec.MaxReplicas != *sink.Spec.MaxReplicas {
 			hpa.Spec.MaxReplicas = *sink.Spec.MaxReplicas
 		}
-		if len(sink.Spec.Pod.AutoScalingMetrics) > 0 && !reflect.DeepEqual(hpa.Spec.Metrics, sink.Spec.Pod.AutoScalingMetrics) {
-			hpa.Spec.Metrics = sink.Spec.Pod.AutoScalingMetrics
+		if metrics := spec.MakeSinkHPA(sink).Spec.Metrics; !reflect.DeepEqual(hpa.Spec.Metrics, metrics) {
+			hpa.Spec.Metrics = metrics
 		}
 		if sink.Spec.Pod.AutoScalingBehavior != nil {
 			hpa.Spec.Behavior = sink.Spec.Pod.AutoScalingBehavior
 		}
-		if len(sink.Spec.Pod.BuiltinAutoscaler) > 0 {
-			metrics := spec.MakeMetricsFromBuiltinHPARules(sink.Spec.Pod.BuiltinAutoscaler)
-			if !reflect.DeepEqual(hpa.Spec.Metrics, metrics) {
-				hpa.Spec.Metrics = metrics
-			}
-		}
 		if err := r.Update(ctx, hpa); err != nil {
 			r.Log.Error(err, "failed to update pod autoscaler for sink", "name", sink.Name)
+			r.Recorder.Eventf(sink, corev1.EventTypeWarning, EventReasonFailedUpdate,
+				"Failed to update HorizontalPodAutoscaler %s: %v", hpa.Name, err)
 			return err
 		}
-	case v1alpha1.Wait, v1alpha1.NoAction:
+		r.Recorder.Eventf(sink, corev1.EventTypeNormal, EventReasonUpdated,
+			"Updated HorizontalPodAutoscaler %s", hpa.Name)
+	case v1alpha2.ReasonWaiting, v1alpha2.ReasonReconciled:
 		// do nothing
 	}
 
 	return nil
 }
+
+// deleteHPA deletes the HPA of the sink if it exists
+func (r *SinkReconciler) deleteHPA(ctx context.Context, sink *v1alpha2.Sink) error {
+	objectMeta := spec.MakeSinkObjectMeta(sink)
+	deleted, err := deleteHPA(ctx, r.Client, objectMeta)
+	if err != nil {
+		r.Log.Error(err, "failed to delete pod autoscaler for sink", "name", sink.Name)
+		r.Recorder.Eventf(sink, corev1.EventTypeWarning, EventReasonFailedDelete,
+			"Failed to delete HorizontalPodAutoscaler %s: %v", objectMeta.Name, err)
+		return err
+	}
+	if deleted {
+		r.Recorder.Eventf(sink, corev1.EventTypeNormal, EventReasonDeleted,
+			"Deleted HorizontalPodAutoscaler %s", objectMeta.Name)
+	}
+	return nil
+}
+
+func (r *SinkReconciler) ObserveSinkMonitor(ctx context.Context, sink *v1alpha2.Sink) error {
+	desired, err := spec.MakeSinkMonitor(sink)
+	if err != nil {
+		return err
+	}
+	return observeMonitor(ctx, r.Client, types.NamespacedName{Namespace: sink.Namespace,
+		Name: spec.MakeSinkObjectMeta(sink).Name}, desired, &sink.Status.Conditions, sink.Generation)
+}
+
+func (r *SinkReconciler) ApplySinkMonitor(ctx context.Context, sink *v1alpha2.Sink) error {
+	desired, err := spec.MakeSinkMonitor(sink)
+	if err != nil {
+		return err
+	}
+	err = applyMonitor(ctx, r.Client, r.Recorder, sink, types.NamespacedName{Namespace: sink.Namespace,
+		Name: spec.MakeSinkObjectMeta(sink).Name}, desired, sink.Status.Conditions)
+	if err != nil {
+		r.Log.Error(err, "failed to apply monitor for sink", "name", sink.Name)
+	}
+	return err
+}
+
+// makeSinkStatefulSet generates the statefulset of the sink, with the connection it refers to resolved
+// and the hash of the objects read by its pods in the pod template
+func (r *SinkReconciler) makeSinkStatefulSet(ctx context.Context, sink *v1alpha2.Sink) (*appsv1.StatefulSet, error) {
+	messaging, err := resolvePulsarMessaging(ctx, r.Client, sink.Namespace, spec.MakeSinkObjectMeta(sink).Name,
+		sink.Spec.Pulsar)
+	if err != nil {
+		return nil, err
+	}
+	resolved := sink.DeepCopy()
+	resolved.Spec.Pulsar = messaging
+	configMaps, secrets := spec.MakeSinkReferences(resolved)
+	hash, err := getConfigHash(ctx, r.Client, sink.Namespace, configMaps, secrets)
+	if err != nil {
+		return nil, err
+	}
+	setConfigHash(&resolved.Spec.Pod, hash)
+	return spec.MakeSinkStatefulSet(resolved), nil
+}
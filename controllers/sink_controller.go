@@ -23,12 +23,14 @@ import (
 	"github.com/streamnative/function-mesh/controllers/spec"
 
 	"github.com/go-logr/logr"
-	computev1alpha1 "github.com/streamnative/function-mesh/api/v1alpha1"
+	computev1alpha2 "github.com/streamnative/function-mesh/api/v1alpha2"
 	appsv1 "k8s.io/api/apps/v1"
 	autov2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -37,8 +39,9 @@ import (
 // SinkReconciler reconciles a Topic object
 type SinkReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=compute.functionmesh.io,resources=sinks,verbs=get;list;watch;create;update;patch;delete
@@ -46,13 +49,14 @@ type SinkReconciler struct {
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 func (r *SinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := context.Background()
 	_ = r.Log.WithValues("sink", req.NamespacedName)
 
 	// your logic here
-	sink := &computev1alpha1.Sink{}
+	sink := &computev1alpha2.Sink{}
 	err := r.Get(ctx, req.NamespacedName, sink)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -67,14 +71,31 @@ func (r *SinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return reconcile.Result{}, nil
 	}
 
-	if sink.Status.Conditions == nil {
-		sink.Status.Conditions = make(map[computev1alpha1.Component]computev1alpha1.ResourceCondition)
+	// the pulsar resources of the sink are cleaned up by the finalizer on deletion
+	deleted, result, err := reconcileFinalizer(ctx, r.Client, r.Log, r.Recorder, sink, spec.MakeSinkObjectMeta(sink).Name,
+		makeCleanupResources(sink, sink.Spec.Pulsar, sink.Spec.Runtime, sink.Spec.CleanupSubscription, &sink.Spec.Input, makeSinkSubscriptionName(sink)))
+	if deleted || err != nil {
+		return result, err
 	}
 
+	// the suspend setting and the idle policy decide the desired replicas of the statefulset,
+	// so they are observed first
+	observeSuspend(&sink.Status.Conditions, sink.Spec.Suspend, sink.Generation)
+	idle, idleRequeueAfter := observeIdle(r.Log, r.Recorder, sink, sink.Spec.IdlePolicy, func() (int64, error) {
+		return getInputBacklog(ctx, r.Client, sink.Namespace, sink.Spec.Input, sink.Spec.Pulsar,
+			makeSinkSubscriptionName(sink))
+	}, &sink.Status.Conditions, sink.Status.Idle, sink.Generation)
+	sink.Status.Idle = idle
+
 	err = r.ObserveSinkStatefulSet(ctx, req, sink)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	err = observePackageDownload(ctx, r.Client, r.Recorder, sink, sink.Namespace, spec.MakeSinkLabels(sink),
+		sink.Spec.Runtime, &sink.Status.Conditions, sink.Generation)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
 	err = r.ObserveSinkService(ctx, req, sink)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -83,6 +104,19 @@ func (r *SinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	err = r.ObserveSinkMonitor(ctx, sink)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// the runtime status of the instances is scraped periodically in the background
+	instances, requeueAfter := observeInstances(spec.MakeSinkObjectMeta(sink), sink.Status.Replicas,
+		sink.Status.Conditions, sink.Status.Instances)
+	sink.Status.Instances = instances
+
+	sink.Status.ObservedGeneration = sink.Generation
+	setAggregatedConditions(kindSink, &sink.Status.Conditions,
+		computev1alpha2.ComponentConditions(sink.Status.Conditions), sink.Generation)
 
 	err = r.Status().Update(ctx, sink)
 	if err != nil {
@@ -92,25 +126,61 @@ func (r *SinkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	err = r.ApplySinkStatefulSet(ctx, sink)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, updateDegradedCondition(ctx, r.Client, sink, &sink.Status.Conditions, sink.Generation, err)
 	}
 	err = r.ApplySinkService(ctx, req, sink)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, updateDegradedCondition(ctx, r.Client, sink, &sink.Status.Conditions, sink.Generation, err)
 	}
 	err = r.ApplySinkHPA(ctx, req, sink)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, updateDegradedCondition(ctx, r.Client, sink, &sink.Status.Conditions, sink.Generation, err)
+	}
+	err = r.ApplySinkMonitor(ctx, sink)
+	if err != nil {
+		return reconcile.Result{}, updateDegradedCondition(ctx, r.Client, sink, &sink.Status.Conditions, sink.Generation, err)
 	}
 
-	return ctrl.Result{}, nil
+	err = updateDegradedCondition(ctx, r.Client, sink, &sink.Status.Conditions, sink.Generation, nil)
+	if err != nil {
+		r.Log.Error(err, "failed to update sink status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: minRequeueAfter(requeueAfter, idleRequeueAfter)}, nil
 }
 
 func (r *SinkReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&computev1alpha1.Sink{}).
+	blder := ctrl.NewControllerManagedBy(mgr).
+		For(&computev1alpha2.Sink{}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&corev1.Service{}).
 		Owns(&autov2beta2.HorizontalPodAutoscaler{}).
-		Complete(r)
+		Owns(&corev1.ConfigMap{})
+	// the pods are rolled when the objects they read change
+	blder, err := watchReferences(mgr, blder, r.Client, r.Log, &computev1alpha2.Sink{}, sinkReferences, r.listSinkRequests)
+	if err != nil {
+		return err
+	}
+	// the monitors are watched only when the Prometheus Operator is installed
+	return ownMonitors(blder, mgr.GetRESTMapper()).Complete(r)
+}
+
+func sinkReferences(object runtime.Object) componentReferences {
+	sink := object.(*computev1alpha2.Sink)
+	configMaps, secrets := spec.MakeSinkReferences(sink)
+	return makeComponentReferences(configMaps, secrets, sink.Spec.Pulsar)
+}
+
+func (r *SinkReconciler) listSinkRequests(ctx context.Context, opts ...client.ListOption) ([]reconcile.Request, error) {
+	sinks := &computev1alpha2.SinkList{}
+	if err := r.List(ctx, sinks, opts...); err != nil {
+		return nil, err
+	}
+	requests := make([]reconcile.Request, 0, len(sinks.Items))
+	for i := range sinks.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: sinks.Items[i].Namespace, Name: sinks.Items[i].Name}})
+	}
+	return requests, nil
 }
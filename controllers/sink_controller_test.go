@@ -31,16 +31,12 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/streamnative/function-mesh/api/v1alpha1"
 )
 
 var _ = Describe("Sink Controller", func() {
 	Context("Simple Sink Item", func() {
 		pulsarConfig := makeSamplePulsarConfig()
 		sink := makeSinkSample()
-		if sink.Status.Conditions == nil {
-			sink.Status.Conditions = make(map[v1alpha1.Component]v1alpha1.ResourceCondition)
-		}
 		statefulSet := spec.MakeSinkStatefulSet(sink)
 
 		It("Should create pulsar configmap successfully", func() {
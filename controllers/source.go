@@ -19,9 +19,10 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 
-	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
 	"github.com/streamnative/function-mesh/controllers/spec"
 	appsv1 "k8s.io/api/apps/v1"
 	autov2beta2 "k8s.io/api/autoscaling/v2beta2"
@@ -33,12 +34,9 @@ import (
 )
 
 func (r *SourceReconciler) ObserveSourceStatefulSet(ctx context.Context, req ctrl.Request,
-	source *v1alpha1.Source) error {
-	condition := v1alpha1.ResourceCondition{
-		Condition: v1alpha1.StatefulSetReady,
-		Status:    metav1.ConditionFalse,
-		Action:    v1alpha1.NoAction,
-	}
+	source *v1alpha2.Source) error {
+	condition := v1alpha2.CreateCondition(v1alpha2.StatefulSetReady, metav1.ConditionFalse,
+		v1alpha2.ReasonReconciled, "", source.Generation)
 
 	statefulSet := &appsv1.StatefulSet{}
 	err := r.Get(ctx, types.NamespacedName{
@@ -48,28 +46,39 @@ func (r *SourceReconciler) ObserveSourceStatefulSet(ctx context.Context, req ctr
 	if err != nil {
 		if errors.IsNotFound(err) {
 			r.Log.Info("source statefulset is not found...")
-			condition.Action = v1alpha1.Create
-			source.Status.Conditions[v1alpha1.StatefulSet] = condition
+			condition.Reason = v1alpha2.ReasonCreating
+			condition.Message = "statefulset is not created"
+			v1alpha2.SetCondition(&source.Status.Conditions, condition)
 			return nil
 		}
 
-		source.Status.Conditions[v1alpha1.StatefulSet] = condition
+		v1alpha2.SetCondition(&source.Status.Conditions, condition)
 		return err
 	}
 
 	// statefulset created, waiting it to be ready
-	condition.Action = v1alpha1.Wait
+	desiredStatefulSet, err := r.makeSourceStatefulSet(ctx, source)
+	if err != nil {
+		return err
+	}
+	condition.Reason = v1alpha2.ReasonWaiting
+	condition.Message = fmt.Sprintf("%d of %d replicas are ready", statefulSet.Status.ReadyReplicas, *desiredStatefulSet.Spec.Replicas)
 
-	if *statefulSet.Spec.Replicas != *source.Spec.Replicas || !reflect.DeepEqual(statefulSet.Spec.Template, spec.MakeSourceStatefulSet(source).Spec.Template) {
-		condition.Action = v1alpha1.Update
+	if *statefulSet.Spec.Replicas != *desiredStatefulSet.Spec.Replicas || !reflect.DeepEqual(statefulSet.Spec.Template, desiredStatefulSet.Spec.Template) {
+		condition.Reason = v1alpha2.ReasonUpdating
+		condition.Message = "statefulset is outdated"
 	}
 
-	if statefulSet.Status.ReadyReplicas == *source.Spec.Replicas {
+	if statefulSet.Status.ReadyReplicas == *desiredStatefulSet.Spec.Replicas {
 		condition.Status = metav1.ConditionTrue
+		if condition.Reason == v1alpha2.ReasonWaiting {
+			condition.Reason = v1alpha2.ReasonReconciled
+			condition.Message = ""
+		}
 	}
 
 	source.Status.Replicas = statefulSet.Status.Replicas
-	source.Status.Conditions[v1alpha1.StatefulSet] = condition
+	v1alpha2.SetCondition(&source.Status.Conditions, condition)
 
 	selector, err := metav1.LabelSelectorAsSelector(statefulSet.Spec.Selector)
 	if err != nil {
@@ -80,8 +89,25 @@ func (r *SourceReconciler) ObserveSourceStatefulSet(ctx context.Context, req ctr
 	return nil
 }
 
-func (r *SourceReconciler) ApplySourceStatefulSet(ctx context.Context, source *v1alpha1.Source) error {
-	desiredStatefulSet := spec.MakeSourceStatefulSet(source)
+func (r *SourceReconciler) ApplySourceStatefulSet(ctx context.Context, source *v1alpha2.Source) error {
+	if err := applyPulsarConnection(ctx, r.Client, spec.MakeSourceObjectMeta(source), source.Spec.Pulsar); err != nil {
+		r.Log.Error(err, "error copying the pulsar connection", "namespace", source.Namespace, "name", source.Name)
+		r.Recorder.Eventf(source, corev1.EventTypeWarning, EventReasonFailedUpdate,
+			"Failed to copy PulsarConnection %s: %v", source.Spec.Pulsar.ConnectionRef.Name, err)
+		return err
+	}
+	// the details are applied before the statefulset, so that the pods started by the update read them
+	detailsConfigMap := spec.MakeSourceDetailsConfigMap(source)
+	if err := applyConfigMap(ctx, r.Client, detailsConfigMap); err != nil {
+		r.Log.Error(err, "error create or update details configmap", "namespace", source.Namespace, "name", detailsConfigMap.Name)
+		r.Recorder.Eventf(source, corev1.EventTypeWarning, EventReasonFailedUpdate,
+			"Failed to create or update ConfigMap %s: %v", detailsConfigMap.Name, err)
+		return err
+	}
+	desiredStatefulSet, err := r.makeSourceStatefulSet(ctx, source)
+	if err != nil {
+		return err
+	}
 	desiredStatefulSetSpec := desiredStatefulSet.Spec
 	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, desiredStatefulSet, func() error {
 		// source statefulset mutate logic
@@ -89,17 +115,30 @@ func (r *SourceReconciler) ApplySourceStatefulSet(ctx context.Context, source *v
 		return nil
 	}); err != nil {
 		r.Log.Error(err, "error create or update statefulSet workload", "namespace", desiredStatefulSet.Namespace, "name", desiredStatefulSet.Name)
+		r.Recorder.Eventf(source, corev1.EventTypeWarning, EventReasonFailedUpdate,
+			"Failed to create or update StatefulSet %s: %v", desiredStatefulSet.Name, err)
 		return err
 	}
+
+	// the statefulset is updated on every reconciliation, the events are only recorded for observed changes
+	condition := v1alpha2.FindCondition(source.Status.Conditions, v1alpha2.StatefulSetReady)
+	if condition != nil {
+		switch condition.Reason {
+		case v1alpha2.ReasonCreating:
+			r.Recorder.Eventf(source, corev1.EventTypeNormal, EventReasonCreated,
+				"Created StatefulSet %s", desiredStatefulSet.Name)
+		case v1alpha2.ReasonUpdating:
+			statefulSetDriftUpdates.WithLabelValues(kindSource).Inc()
+			r.Recorder.Eventf(source, corev1.EventTypeNormal, EventReasonUpdated,
+				"Updated StatefulSet %s", desiredStatefulSet.Name)
+		}
+	}
 	return nil
 }
 
-func (r *SourceReconciler) ObserveSourceService(ctx context.Context, req ctrl.Request, source *v1alpha1.Source) error {
-	condition := v1alpha1.ResourceCondition{
-		Condition: v1alpha1.ServiceReady,
-		Status:    metav1.ConditionFalse,
-		Action:    v1alpha1.NoAction,
-	}
+func (r *SourceReconciler) ObserveSourceService(ctx context.Context, req ctrl.Request, source *v1alpha2.Source) error {
+	condition := v1alpha2.CreateCondition(v1alpha2.ServiceReady, metav1.ConditionFalse,
+		v1alpha2.ReasonReconciled, "", source.Generation)
 
 	svc := &corev1.Service{}
 	svcName := spec.MakeHeadlessServiceName(spec.MakeSourceObjectMeta(source).Name)
@@ -107,10 +146,11 @@ func (r *SourceReconciler) ObserveSourceService(ctx context.Context, req ctrl.Re
 		Name: svcName}, svc)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			condition.Action = v1alpha1.Create
+			condition.Reason = v1alpha2.ReasonCreating
+			condition.Message = "service is not created"
 			r.Log.Info("service is not created...", "Name", source.Name, "ServiceName", svcName)
 		} else {
-			source.Status.Conditions[v1alpha1.Service] = condition
+			v1alpha2.SetCondition(&source.Status.Conditions, condition)
 			return err
 		}
 	} else {
@@ -118,44 +158,46 @@ func (r *SourceReconciler) ObserveSourceService(ctx context.Context, req ctrl.Re
 		condition.Status = metav1.ConditionTrue
 	}
 
-	source.Status.Conditions[v1alpha1.Service] = condition
+	v1alpha2.SetCondition(&source.Status.Conditions, condition)
 	return nil
 }
 
-func (r *SourceReconciler) ApplySourceService(ctx context.Context, req ctrl.Request, source *v1alpha1.Source) error {
-	condition := source.Status.Conditions[v1alpha1.Service]
+func (r *SourceReconciler) ApplySourceService(ctx context.Context, req ctrl.Request, source *v1alpha2.Source) error {
+	condition := v1alpha2.FindCondition(source.Status.Conditions, v1alpha2.ServiceReady)
 
-	if condition.Status == metav1.ConditionTrue {
+	if condition == nil || condition.Status == metav1.ConditionTrue {
 		return nil
 	}
 
-	switch condition.Action {
-	case v1alpha1.Create:
+	switch condition.Reason {
+	case v1alpha2.ReasonCreating:
 		svc := spec.MakeSourceService(source)
 		if err := r.Create(ctx, svc); err != nil {
 			r.Log.Error(err, "failed to expose service for source", "name", source.Name)
+			r.Recorder.Eventf(source, corev1.EventTypeWarning, EventReasonFailedCreate,
+				"Failed to create Service %s: %v", svc.Name, err)
 			return err
 		}
-	case v1alpha1.Wait, v1alpha1.NoAction:
+		r.Recorder.Eventf(source, corev1.EventTypeNormal, EventReasonCreated, "Created Service %s", svc.Name)
+	case v1alpha2.ReasonWaiting, v1alpha2.ReasonReconciled:
 		// do nothing
 	}
 
 	return nil
 }
 
-func (r *SourceReconciler) ObserveSourceHPA(ctx context.Context, req ctrl.Request, source *v1alpha1.Source) error {
-	if source.Spec.MaxReplicas == nil {
-		// HPA not enabled, skip further action
+func (r *SourceReconciler) ObserveSourceHPA(ctx context.Context, req ctrl.Request, source *v1alpha2.Source) error {
+	if source.Spec.MaxReplicas == nil || source.Spec.Suspend {
+		// HPA not enabled or removed while suspended, skip further action
+		v1alpha2.RemoveCondition(&source.Status.Conditions, v1alpha2.HPAReady)
 		return nil
 	}
 
-	condition, ok := source.Status.Conditions[v1alpha1.HPA]
-	if !ok {
-		source.Status.Conditions[v1alpha1.HPA] = v1alpha1.ResourceCondition{
-			Condition: v1alpha1.HPAReady,
-			Status:    metav1.ConditionFalse,
-			Action:    v1alpha1.Create,
-		}
+	condition := v1alpha2.FindCondition(source.Status.Conditions, v1alpha2.HPAReady)
+	if condition == nil {
+		v1alpha2.SetCondition(&source.Status.Conditions, v1alpha2.CreateCondition(
+			v1alpha2.HPAReady, metav1.ConditionFalse, v1alpha2.ReasonCreating,
+			"hpa is not created", source.Generation))
 		return nil
 	}
 
@@ -170,43 +212,53 @@ func (r *SourceReconciler) ObserveSourceHPA(ctx context.Context, req ctrl.Reques
 		return err
 	}
 
+	// the HPA takes the metrics of the builtin rules, or the default ones when no metrics are set
+	desired := spec.MakeSourceHPA(source)
 	if hpa.Spec.MaxReplicas != *source.Spec.MaxReplicas ||
-		!reflect.DeepEqual(hpa.Spec.Metrics, source.Spec.Pod.AutoScalingMetrics) ||
+		!reflect.DeepEqual(hpa.Spec.Metrics, desired.Spec.Metrics) ||
 		(source.Spec.Pod.AutoScalingBehavior != nil && hpa.Spec.Behavior == nil) ||
 		(source.Spec.Pod.AutoScalingBehavior != nil && hpa.Spec.Behavior != nil &&
 			!reflect.DeepEqual(*hpa.Spec.Behavior, *source.Spec.Pod.AutoScalingBehavior)) {
-		condition.Status = metav1.ConditionFalse
-		condition.Action = v1alpha1.Update
-		source.Status.Conditions[v1alpha1.HPA] = condition
+		v1alpha2.SetCondition(&source.Status.Conditions, v1alpha2.CreateCondition(
+			v1alpha2.HPAReady, metav1.ConditionFalse, v1alpha2.ReasonUpdating,
+			"hpa is outdated", source.Generation))
 		return nil
 	}
 
-	condition.Action = v1alpha1.NoAction
-	condition.Status = metav1.ConditionTrue
-	source.Status.Conditions[v1alpha1.HPA] = condition
+	v1alpha2.SetCondition(&source.Status.Conditions, v1alpha2.CreateCondition(
+		v1alpha2.HPAReady, metav1.ConditionTrue, v1alpha2.ReasonReconciled, "", source.Generation))
 	return nil
 }
 
-func (r *SourceReconciler) ApplySourceHPA(ctx context.Context, req ctrl.Request, source *v1alpha1.Source) error {
+func (r *SourceReconciler) ApplySourceHPA(ctx context.Context, req ctrl.Request, source *v1alpha2.Source) error {
 	if source.Spec.MaxReplicas == nil {
 		// HPA not enabled, skip further action
 		return nil
 	}
 
-	condition := source.Status.Conditions[v1alpha1.HPA]
+	if source.Spec.Suspend {
+		// the HPA would scale the suspended statefulset up again, it is recreated once resumed
+		return r.deleteHPA(ctx, source)
+	}
+
+	condition := v1alpha2.FindCondition(source.Status.Conditions, v1alpha2.HPAReady)
 
-	if condition.Status == metav1.ConditionTrue {
+	if condition == nil || condition.Status == metav1.ConditionTrue {
 		return nil
 	}
 
-	switch condition.Action {
-	case v1alpha1.Create:
+	switch condition.Reason {
+	case v1alpha2.ReasonCreating:
 		hpa := spec.MakeSourceHPA(source)
 		if err := r.Create(ctx, hpa); err != nil {
 			r.Log.Error(err, "failed to create pod autoscaler for source", "name", source.Name)
+			r.Recorder.Eventf(source, corev1.EventTypeWarning, EventReasonFailedCreate,
+				"Failed to create HorizontalPodAutoscaler %s: %v", hpa.Name, err)
 			return err
 		}
-	case v1alpha1.Update:
+		r.Recorder.Eventf(source, corev1.EventTypeNormal, EventReasonCreated,
+			"Created HorizontalPodAutoscaler %s", hpa.Name)
+	case v1alpha2.ReasonUpdating:
 		hpa := &autov2beta2.HorizontalPodAutoscaler{}
 		err := r.Get(ctx, types.NamespacedName{Namespace: source.Namespace,
 			Name: spec.MakeSourceObjectMeta(source).Name}, hpa)
@@ -217,25 +269,81 @@ func (r *SourceReconciler) ApplySourceHPA(ctx context.Context, req ctrl.Request,
 		if hpa.Spec.MaxReplicas != *source.Spec.MaxReplicas {
 			hpa.Spec.MaxReplicas = *source.Spec.MaxReplicas
 		}
-		if len(source.Spec.Pod.AutoScalingMetrics) > 0 && !reflect.DeepEqual(hpa.Spec.Metrics, source.Spec.Pod.AutoScalingMetrics) {
-			hpa.Spec.Metrics = source.Spec.Pod.AutoScalingMetrics
+		if metrics := spec.MakeSourceHPA(source).Spec.Metrics; !reflect.DeepEqual(hpa.Spec.Metrics, metrics) {
+			hpa.Spec.Metrics = metrics
 		}
 		if source.Spec.Pod.AutoScalingBehavior != nil {
 			hpa.Spec.Behavior = source.Spec.Pod.AutoScalingBehavior
 		}
-		if len(source.Spec.Pod.BuiltinAutoscaler) > 0 {
-			metrics := spec.MakeMetricsFromBuiltinHPARules(source.Spec.Pod.BuiltinAutoscaler)
-			if !reflect.DeepEqual(hpa.Spec.Metrics, metrics) {
-				hpa.Spec.Metrics = metrics
-			}
-		}
 		if err := r.Update(ctx, hpa); err != nil {
 			r.Log.Error(err, "failed to update pod autoscaler for source", "name", source.Name)
+			r.Recorder.Eventf(source, corev1.EventTypeWarning, EventReasonFailedUpdate,
+				"Failed to update HorizontalPodAutoscaler %s: %v", hpa.Name, err)
 			return err
 		}
-	case v1alpha1.Wait, v1alpha1.NoAction:
+		r.Recorder.Eventf(source, corev1.EventTypeNormal, EventReasonUpdated,
+			"Updated HorizontalPodAutoscaler %s", hpa.Name)
+	case v1alpha2.ReasonWaiting, v1alpha2.ReasonReconciled:
 		// do nothing
 	}
 
 	return nil
 }
+
+// deleteHPA deletes the HPA of the source if it exists
+func (r *SourceReconciler) deleteHPA(ctx context.Context, source *v1alpha2.Source) error {
+	objectMeta := spec.MakeSourceObjectMeta(source)
+	deleted, err := deleteHPA(ctx, r.Client, objectMeta)
+	if err != nil {
+		r.Log.Error(err, "failed to delete pod autoscaler for source", "name", source.Name)
+		r.Recorder.Eventf(source, corev1.EventTypeWarning, EventReasonFailedDelete,
+			"Failed to delete HorizontalPodAutoscaler %s: %v", objectMeta.Name, err)
+		return err
+	}
+	if deleted {
+		r.Recorder.Eventf(source, corev1.EventTypeNormal, EventReasonDeleted,
+			"Deleted HorizontalPodAutoscaler %s", objectMeta.Name)
+	}
+	return nil
+}
+
+func (r *SourceReconciler) ObserveSourceMonitor(ctx context.Context, source *v1alpha2.Source) error {
+	desired, err := spec.MakeSourceMonitor(source)
+	if err != nil {
+		return err
+	}
+	return observeMonitor(ctx, r.Client, types.NamespacedName{Namespace: source.Namespace,
+		Name: spec.MakeSourceObjectMeta(source).Name}, desired, &source.Status.Conditions, source.Generation)
+}
+
+func (r *SourceReconciler) ApplySourceMonitor(ctx context.Context, source *v1alpha2.Source) error {
+	desired, err := spec.MakeSourceMonitor(source)
+	if err != nil {
+		return err
+	}
+	err = applyMonitor(ctx, r.Client, r.Recorder, source, types.NamespacedName{Namespace: source.Namespace,
+		Name: spec.MakeSourceObjectMeta(source).Name}, desired, source.Status.Conditions)
+	if err != nil {
+		r.Log.Error(err, "failed to apply monitor for source", "name", source.Name)
+	}
+	return err
+}
+
+// makeSourceStatefulSet generates the statefulset of the source, with the connection it refers to resolved
+// and the hash of the objects read by its pods in the pod template
+func (r *SourceReconciler) makeSourceStatefulSet(ctx context.Context, source *v1alpha2.Source) (*appsv1.StatefulSet, error) {
+	messaging, err := resolvePulsarMessaging(ctx, r.Client, source.Namespace, spec.MakeSourceObjectMeta(source).Name,
+		source.Spec.Pulsar)
+	if err != nil {
+		return nil, err
+	}
+	resolved := source.DeepCopy()
+	resolved.Spec.Pulsar = messaging
+	configMaps, secrets := spec.MakeSourceReferences(resolved)
+	hash, err := getConfigHash(ctx, r.Client, source.Namespace, configMaps, secrets)
+	if err != nil {
+		return nil, err
+	}
+	setConfigHash(&resolved.Spec.Pod, hash)
+	return spec.MakeSourceStatefulSet(resolved), nil
+}
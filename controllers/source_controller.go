@@ -23,12 +23,14 @@ import (
 	"github.com/streamnative/function-mesh/controllers/spec"
 
 	"github.com/go-logr/logr"
-	computev1alpha1 "github.com/streamnative/function-mesh/api/v1alpha1"
+	computev1alpha2 "github.com/streamnative/function-mesh/api/v1alpha2"
 	appsv1 "k8s.io/api/apps/v1"
 	autov2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -37,8 +39,9 @@ import (
 // SourceReconciler reconciles a Source object
 type SourceReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=compute.functionmesh.io,resources=sources,verbs=get;list;watch;create;update;patch;delete
@@ -46,13 +49,14 @@ type SourceReconciler struct {
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 func (r *SourceReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := context.Background()
 	_ = r.Log.WithValues("source", req.NamespacedName)
 
 	// your logic here
-	source := &computev1alpha1.Source{}
+	source := &computev1alpha2.Source{}
 	err := r.Get(ctx, req.NamespacedName, source)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -67,14 +71,25 @@ func (r *SourceReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return reconcile.Result{}, nil
 	}
 
-	if source.Status.Conditions == nil {
-		source.Status.Conditions = make(map[computev1alpha1.Component]computev1alpha1.ResourceCondition)
+	// the pulsar resources of the source are cleaned up by the finalizer on deletion
+	deleted, result, err := reconcileFinalizer(ctx, r.Client, r.Log, r.Recorder, source, spec.MakeSourceObjectMeta(source).Name,
+		makeCleanupResources(source, source.Spec.Pulsar, source.Spec.Runtime, false, nil, ""))
+	if deleted || err != nil {
+		return result, err
 	}
 
+	// the suspend setting decides the desired replicas of the statefulset, so it is observed first
+	observeSuspend(&source.Status.Conditions, source.Spec.Suspend, source.Generation)
+
 	err = r.ObserveSourceStatefulSet(ctx, req, source)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	err = observePackageDownload(ctx, r.Client, r.Recorder, source, source.Namespace, spec.MakeSourceLabels(source),
+		source.Spec.Runtime, &source.Status.Conditions, source.Generation)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
 	err = r.ObserveSourceService(ctx, req, source)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -83,6 +98,19 @@ func (r *SourceReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	err = r.ObserveSourceMonitor(ctx, source)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// the runtime status of the instances is scraped periodically in the background
+	instances, requeueAfter := observeInstances(spec.MakeSourceObjectMeta(source), source.Status.Replicas,
+		source.Status.Conditions, source.Status.Instances)
+	source.Status.Instances = instances
+
+	source.Status.ObservedGeneration = source.Generation
+	setAggregatedConditions(kindSource, &source.Status.Conditions,
+		computev1alpha2.ComponentConditions(source.Status.Conditions), source.Generation)
 
 	err = r.Status().Update(ctx, source)
 	if err != nil {
@@ -92,25 +120,61 @@ func (r *SourceReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	err = r.ApplySourceStatefulSet(ctx, source)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, updateDegradedCondition(ctx, r.Client, source, &source.Status.Conditions, source.Generation, err)
 	}
 	err = r.ApplySourceService(ctx, req, source)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, updateDegradedCondition(ctx, r.Client, source, &source.Status.Conditions, source.Generation, err)
 	}
 	err = r.ApplySourceHPA(ctx, req, source)
 	if err != nil {
-		return reconcile.Result{}, err
+		return reconcile.Result{}, updateDegradedCondition(ctx, r.Client, source, &source.Status.Conditions, source.Generation, err)
+	}
+	err = r.ApplySourceMonitor(ctx, source)
+	if err != nil {
+		return reconcile.Result{}, updateDegradedCondition(ctx, r.Client, source, &source.Status.Conditions, source.Generation, err)
 	}
 
-	return ctrl.Result{}, nil
+	err = updateDegradedCondition(ctx, r.Client, source, &source.Status.Conditions, source.Generation, nil)
+	if err != nil {
+		r.Log.Error(err, "failed to update source status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *SourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&computev1alpha1.Source{}).
+	blder := ctrl.NewControllerManagedBy(mgr).
+		For(&computev1alpha2.Source{}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&corev1.Service{}).
 		Owns(&autov2beta2.HorizontalPodAutoscaler{}).
-		Complete(r)
+		Owns(&corev1.ConfigMap{})
+	// the pods are rolled when the objects they read change
+	blder, err := watchReferences(mgr, blder, r.Client, r.Log, &computev1alpha2.Source{}, sourceReferences, r.listSourceRequests)
+	if err != nil {
+		return err
+	}
+	// the monitors are watched only when the Prometheus Operator is installed
+	return ownMonitors(blder, mgr.GetRESTMapper()).Complete(r)
+}
+
+func sourceReferences(object runtime.Object) componentReferences {
+	source := object.(*computev1alpha2.Source)
+	configMaps, secrets := spec.MakeSourceReferences(source)
+	return makeComponentReferences(configMaps, secrets, source.Spec.Pulsar)
+}
+
+func (r *SourceReconciler) listSourceRequests(ctx context.Context, opts ...client.ListOption) ([]reconcile.Request, error) {
+	sources := &computev1alpha2.SourceList{}
+	if err := r.List(ctx, sources, opts...); err != nil {
+		return nil, err
+	}
+	requests := make([]reconcile.Request, 0, len(sources.Items))
+	for i := range sources.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: sources.Items[i].Namespace, Name: sources.Items[i].Name}})
+	}
+	return requests, nil
 }
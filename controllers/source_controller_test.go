@@ -26,7 +26,6 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/streamnative/function-mesh/api/v1alpha1"
 	"github.com/streamnative/function-mesh/controllers/spec"
 	appv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -37,9 +36,6 @@ var _ = Describe("Source Controller", func() {
 	Context("Simple Source Item", func() {
 		pulsarConfig := makeSamplePulsarConfig()
 		source := makeSourceSample()
-		if source.Status.Conditions == nil {
-			source.Status.Conditions = make(map[v1alpha1.Component]v1alpha1.ResourceCondition)
-		}
 		statefulSet := spec.MakeSourceStatefulSet(source)
 
 		It("Should create pulsar configmap successfully", func() {
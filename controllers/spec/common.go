@@ -23,18 +23,21 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
 	"github.com/streamnative/function-mesh/controllers/proto"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
-	EnvShardID                 = "SHARD_ID"
+	EnvShardID = "SHARD_ID"
+	// LabelPodIndex is the label with the index of the pods of a statefulset, set by Kubernetes 1.28 and later
+	LabelPodIndex              = "apps.kubernetes.io/pod-index"
 	FunctionsInstanceClasspath = "pulsar.functions.instance.classpath"
 	DefaultRunnerTag           = "2.10.0.0-rc10"
 	DefaultRunnerPrefix        = "streamnative/"
@@ -59,6 +62,13 @@ const (
 	AnnotationPrometheusScrape = "prometheus.io/scrape"
 	AnnotationPrometheusPort   = "prometheus.io/port"
 	AnnotationManaged          = "compute.functionmesh.io/managed"
+	AnnotationCleanupPackages  = "compute.functionmesh.io/cleanup-packages"
+	AnnotationSkipCleanup      = "compute.functionmesh.io/skip-cleanup"
+	AnnotationConfigHash       = "compute.functionmesh.io/config-hash"
+	AnnotationDetailsHash      = "compute.functionmesh.io/details-hash"
+
+	CleanupFinalizerName         = "compute.functionmesh.io/cleanup"
+	OrderedDeletionFinalizerName = "compute.functionmesh.io/ordered-deletion"
 
 	EnvGoFunctionConfigs = "GO_FUNCTION_CONF"
 
@@ -74,6 +84,12 @@ const (
 
 	EnvGoFunctionLogLevel = "LOGGING_LEVEL"
 
+	WindowFunctionExecutorClass = "org.apache.pulsar.functions.windowing.WindowFunctionExecutor"
+	WindowConfigKey             = "__WINDOWCONFIGS__"
+
+	DefaultInstanceStatusSyncInterval = 30 * time.Second
+	DefaultIdleCheckInterval          = 30 * time.Second
+
 	defaultJavaInstanceLog4jXML = `<Configuration>
     <name>pulsar-functions-kubernetes-instance</name>
     <monitorInterval>30</monitorInterval>
@@ -186,6 +202,22 @@ func MakeHeadlessServiceName(serviceName string) string {
 	return fmt.Sprintf("%s-headless", serviceName)
 }
 
+// MakeInstanceGRPCAddress returns the address of the instance control port of the instance with the given index,
+// the instance pod is resolved through the headless service of the statefulset
+func MakeInstanceGRPCAddress(objectMeta *metav1.ObjectMeta, index int32) string {
+	return fmt.Sprintf("%s-%d.%s.%s.svc:%d", objectMeta.Name, index, MakeHeadlessServiceName(objectMeta.Name),
+		objectMeta.Namespace, GRPCPort.ContainerPort)
+}
+
+// makeDesiredReplicas returns zero when the component is suspended or scaled to zero by its idle policy
+func makeDesiredReplicas(replicas *int32, suspend bool, conditions []v1alpha2.Condition) *int32 {
+	if suspend || v1alpha2.IsConditionTrue(conditions, v1alpha2.ScaledToZero) {
+		zero := int32(0)
+		return &zero
+	}
+	return replicas
+}
+
 func MakeStatefulSet(objectMeta *metav1.ObjectMeta, replicas *int32, container *corev1.Container,
 	volumes []corev1.Volume, labels map[string]string, policy v1alpha1.PodPolicy) *appsv1.StatefulSet {
 	return &appsv1.StatefulSet{
@@ -242,44 +274,53 @@ func MakePodTemplate(container *corev1.Container, volumes []corev1.Volume,
 	}
 }
 
-func MakeJavaFunctionCommand(downloadPath, packageFile, name, clusterName, generateLogConfigCommand, logLevel, details, memory, extraDependenciesDir, uid string,
-	authProvided, tlsProvided bool, secretMaps map[string]v1alpha1.SecretRef, state *v1alpha1.Stateful, tlsConfig TLSConfig) []string {
+func MakeJavaFunctionCommand(downloadPath, packageFile, name, clusterName, generateLogConfigCommand, logLevel, details, heapSize, extraDependenciesDir, uid string,
+	authProvided, tlsProvided bool, secretMaps map[string]v1alpha1.SecretRef, state *v1alpha1.Stateful, tlsConfig TLSConfig,
+	tuning *v1alpha1.RuntimeTuning) []string {
+	// the package is downloaded by the init container into the volume shared with the instance
+	packageFile = makePackagePath(downloadPath, packageFile)
 	processCommand := setShardIDEnvironmentVariableCommand() + " && " + generateLogConfigCommand +
 		strings.Join(getProcessJavaRuntimeArgs(name, packageFile, clusterName, logLevel, details,
-			memory, extraDependenciesDir, uid, authProvided, tlsProvided, secretMaps, state, tlsConfig), " ")
-	if downloadPath != "" {
-		// prepend download command if the downPath is provided
-		downloadCommand := strings.Join(getDownloadCommand(downloadPath, packageFile, authProvided, tlsProvided, tlsConfig), " ")
-		processCommand = downloadCommand + " && " + processCommand
-	}
+			heapSize, extraDependenciesDir, uid, authProvided, tlsProvided, secretMaps, state, tlsConfig,
+			makeRuntimeTuning(tuning)), " ")
 	return []string{"sh", "-c", processCommand}
 }
 
 func MakePythonFunctionCommand(downloadPath, packageFile, name, clusterName, generateLogConfigCommand, details, uid string,
-	authProvided, tlsProvided bool, secretMaps map[string]v1alpha1.SecretRef, state *v1alpha1.Stateful, tlsConfig TLSConfig) []string {
-	processCommand := setShardIDEnvironmentVariableCommand() + " && " + generateLogConfigCommand +
+	authProvided, tlsProvided bool, secretMaps map[string]v1alpha1.SecretRef, state *v1alpha1.Stateful, tlsConfig TLSConfig,
+	tuning *v1alpha1.RuntimeTuning, dependencies *v1alpha1.PythonDependencies) []string {
+	// the package is downloaded by the init container into the volume shared with the instance
+	packageFile = makePackagePath(downloadPath, packageFile)
+	// the instance installing the dependencies on start authenticates to the index itself
+	indexNetrcCommand := ""
+	if !installsPythonDependencies(dependencies) {
+		indexNetrcCommand = makePythonIndexNetrcCommand(dependencies)
+	}
+	processCommand := setShardIDEnvironmentVariableCommand() + " && " + indexNetrcCommand +
+		makePythonPathCommand(dependencies) + generateLogConfigCommand +
 		strings.Join(getProcessPythonRuntimeArgs(name, packageFile, clusterName,
-			details, uid, authProvided, tlsProvided, secretMaps, state, tlsConfig), " ")
-	if downloadPath != "" {
-		// prepend download command if the downPath is provided
-		downloadCommand := strings.Join(getDownloadCommand(downloadPath, packageFile, authProvided, tlsProvided, tlsConfig), " ")
-		processCommand = downloadCommand + " && " + processCommand
-	}
+			details, uid, authProvided, tlsProvided, secretMaps, state, tlsConfig, makeRuntimeTuning(tuning),
+			dependencies), " ")
 	return []string{"sh", "-c", processCommand}
 }
 
-func MakeGoFunctionCommand(downloadPath, goExecFilePath string, function *v1alpha1.Function) []string {
+func MakeGoFunctionCommand(downloadPath, goExecFilePath string) []string {
+	// the executable read in place is expected to be executable already
+	executable := v1alpha1.IsFilePackageLocation(downloadPath)
+	goExecFilePath = makePackagePath(downloadPath, goExecFilePath)
 	processCommand := setShardIDEnvironmentVariableCommand() + " && " +
-		strings.Join(getProcessGoRuntimeArgs(goExecFilePath, function), " ")
-	if downloadPath != "" {
-		// prepend download command if the downPath is provided
-		downloadCommand := strings.Join(getDownloadCommand(downloadPath, goExecFilePath,
-			function.Spec.Pulsar.AuthSecret != "", function.Spec.Pulsar.TLSSecret != "", function.Spec.Pulsar.TLSConfig), " ")
-		processCommand = downloadCommand + " && ls -al && pwd &&" + processCommand
-	}
+		strings.Join(getProcessGoRuntimeArgs(goExecFilePath, executable), " ")
 	return []string{"sh", "-c", processCommand}
 }
 
+// MakeCustomFunctionCommand starts the command of the custom runtime with the arguments of the runners, the command
+// is not started by a shell and the arguments refer to the environment variables with the syntax of Kubernetes
+func MakeCustomFunctionCommand(custom *v1alpha1.CustomRuntime, clusterName, uid string, authProvided, tlsProvided bool,
+	state *v1alpha1.Stateful, tlsConfig TLSConfig, tuning *v1alpha1.RuntimeTuning) []string {
+	return getProcessCustomRuntimeArgs(custom, clusterName, uid, authProvided, tlsProvided, state, tlsConfig,
+		makeRuntimeTuning(tuning))
+}
+
 func getDownloadCommand(downloadPath, componentPackage string, authProvided, tlsProvided bool, tlsConfig TLSConfig) []string {
 	// The download path is the path that the package saved in the pulsar.
 	// By default, it's the path that the package saved in the pulsar, we can use package name
@@ -436,6 +477,28 @@ func parseGolangLogLevel(runtime *v1alpha1.GoRuntime) string {
 	return levelMap[v1alpha1.LogLevelInfo]
 }
 
+// GetPackageURLs returns the packages of the runtime which are stored in the Pulsar package management service
+func GetPackageURLs(runtime v1alpha1.Runtime) []string {
+	var locations []string
+	if runtime.Java != nil {
+		locations = append(locations, runtime.Java.JarLocation)
+	}
+	if runtime.Python != nil {
+		locations = append(locations, runtime.Python.PyLocation)
+	}
+	if runtime.Golang != nil {
+		locations = append(locations, runtime.Golang.GoLocation)
+	}
+
+	packages := []string{}
+	for _, location := range locations {
+		if hasPackageNamePrefix(location) {
+			packages = append(packages, location)
+		}
+	}
+	return packages
+}
+
 // TODO: do a more strict check for the package name https://github.com/streamnative/function-mesh/issues/49
 func hasPackageNamePrefix(packagesName string) bool {
 	return strings.HasPrefix(packagesName, PackageNameFunctionPrefix) ||
@@ -447,8 +510,9 @@ func setShardIDEnvironmentVariableCommand() string {
 	return fmt.Sprintf("%s=${POD_NAME##*-} && echo shardId=${%s}", EnvShardID, EnvShardID)
 }
 
-func getProcessJavaRuntimeArgs(name, packageName, clusterName, logLevel, details, memory, extraDependenciesDir, uid string,
-	authProvided, tlsProvided bool, secretMaps map[string]v1alpha1.SecretRef, state *v1alpha1.Stateful, tlsConfig TLSConfig) []string {
+func getProcessJavaRuntimeArgs(name, packageName, clusterName, logLevel, details, heapSize, extraDependenciesDir, uid string,
+	authProvided, tlsProvided bool, secretMaps map[string]v1alpha1.SecretRef, state *v1alpha1.Stateful, tlsConfig TLSConfig,
+	tuning runtimeTuning) []string {
 	classPath := "/pulsar/instances/java-instance.jar"
 	if extraDependenciesDir != "" {
 		classPath = fmt.Sprintf("%s:%s/*", classPath, extraDependenciesDir)
@@ -472,12 +536,17 @@ func getProcessJavaRuntimeArgs(name, packageName, clusterName, logLevel, details
 		"-Dpulsar.function.log.dir=logs/functions",
 		"-Dpulsar.function.log.file=" + fmt.Sprintf("%s-${%s}", name, EnvShardID),
 		setLogLevel,
-		"-Xmx" + memory,
+	}
+	if heapSize != "" {
+		args = append(args, "-Xmx"+heapSize)
+	}
+	args = append(args, quoteShellArgs(tuning.jvmOptions)...)
+	args = append(args,
 		"org.apache.pulsar.functions.instance.JavaInstanceMain",
 		"--jar",
 		packageName,
-	}
-	sharedArgs := getSharedArgs(details, clusterName, uid, authProvided, tlsProvided, tlsConfig)
+	)
+	sharedArgs := getSharedArgs(details, clusterName, uid, authProvided, tlsProvided, tlsConfig, tuning)
 	args = append(args, sharedArgs...)
 	if len(secretMaps) > 0 {
 		secretProviderArgs := getJavaSecretProviderArgs(secretMaps)
@@ -497,10 +566,16 @@ func getProcessJavaRuntimeArgs(name, packageName, clusterName, logLevel, details
 }
 
 func getProcessPythonRuntimeArgs(name, packageName, clusterName, details, uid string, authProvided, tlsProvided bool,
-	secretMaps map[string]v1alpha1.SecretRef, state *v1alpha1.Stateful, tlsConfig TLSConfig) []string {
+	secretMaps map[string]v1alpha1.SecretRef, state *v1alpha1.Stateful, tlsConfig TLSConfig, tuning runtimeTuning,
+	dependencies *v1alpha1.PythonDependencies) []string {
 	args := []string{
 		"exec",
 		"python",
+	}
+	args = append(args, quoteShellArgs(tuning.pythonInterpreterFlags)...)
+	args = append(args, "-c")
+	args = append(args, quoteShellArgs([]string{pythonInstanceLauncher})...)
+	args = append(args,
 		"/pulsar/instances/python-instance/python_instance_main.py",
 		"--py",
 		packageName,
@@ -510,11 +585,9 @@ func getProcessPythonRuntimeArgs(name, packageName, clusterName, details, uid st
 		fmt.Sprintf("%s-${%s}", name, EnvShardID),
 		"--logging_config_file",
 		DefaultPythonLogConfigPath,
-		"--install_usercode_dependencies",
-		"true",
-		// TODO: Maybe we don't need installUserCodeDependencies, dependency_repository, and pythonExtraDependencyRepository
-	}
-	sharedArgs := getSharedArgs(details, clusterName, uid, authProvided, tlsProvided, tlsConfig)
+	)
+	args = append(args, getPythonDependenciesArgs(dependencies)...)
+	sharedArgs := getSharedArgs(details, clusterName, uid, authProvided, tlsProvided, tlsConfig, tuning)
 	args = append(args, sharedArgs...)
 	if len(secretMaps) > 0 {
 		secretProviderArgs := getPythonSecretProviderArgs(secretMaps)
@@ -531,7 +604,8 @@ func getProcessPythonRuntimeArgs(name, packageName, clusterName, details, uid st
 }
 
 // This method is suitable for Java and Python runtime, not include Go runtime.
-func getSharedArgs(details, clusterName, uid string, authProvided bool, tlsProvided bool, tlsConfig TLSConfig) []string {
+func getSharedArgs(details, clusterName, uid string, authProvided bool, tlsProvided bool, tlsConfig TLSConfig,
+	tuning runtimeTuning) []string {
 	args := []string{
 		"--instance_id",
 		"${" + EnvShardID + "}",
@@ -540,17 +614,17 @@ func getSharedArgs(details, clusterName, uid string, authProvided bool, tlsProvi
 		"--function_version",
 		"0",
 		"--function_details",
-		"'" + details + "'", //in json format
+		details,
 		"--pulsar_serviceurl",
 		"$brokerServiceURL",
 		"--max_buffered_tuples",
-		"100", // TODO
+		strconv.Itoa(int(tuning.maxBufferedTuples)),
 		"--port",
 		strconv.Itoa(int(GRPCPort.ContainerPort)),
 		"--metrics_port",
 		strconv.Itoa(int(MetricsPort.ContainerPort)),
 		"--expected_healthcheck_interval",
-		"-1", // TurnOff BuiltIn HealthCheck to avoid instance exit
+		strconv.Itoa(int(tuning.expectedHealthCheckInterval)),
 		"--cluster_name",
 		clusterName,
 	}
@@ -608,7 +682,7 @@ func getSharedArgs(details, clusterName, uid string, authProvided bool, tlsProvi
 	return args
 }
 
-func generateGoFunctionConf(function *v1alpha1.Function) string {
+func generateGoFunctionConf(function *v1alpha2.Function) string {
 	goFunctionConfs := convertGoFunctionConfs(function)
 	j, err := json.Marshal(goFunctionConfs)
 	if err != nil {
@@ -620,31 +694,93 @@ func generateGoFunctionConf(function *v1alpha1.Function) string {
 	return ret
 }
 
-func getProcessGoRuntimeArgs(goExecFilePath string, function *v1alpha1.Function) []string {
-	str := generateGoFunctionConf(function)
-	str = strings.ReplaceAll(str, "\"", "\\\"")
+func getProcessGoRuntimeArgs(goExecFilePath string, executable bool) []string {
 	args := []string{
-		fmt.Sprintf("%s=%s", EnvGoFunctionConfigs, str),
-		"&&",
-		fmt.Sprintf("goFunctionConfigs=${%s}", EnvGoFunctionConfigs),
+		makeGoInstanceConfCommand(),
 		"&&",
-		"echo goFunctionConfigs=\"'${goFunctionConfigs}'\"",
+		"cat",
+		GoInstanceConfPath,
 		"&&",
 		"ls -l",
 		goExecFilePath,
 		"&&",
-		"chmod +x",
-		goExecFilePath,
-		"&&",
+	}
+	if !executable {
+		args = append(args, "chmod +x", goExecFilePath, "&&")
+	}
+	args = append(args,
 		"exec",
 		goExecFilePath,
-		"-instance-conf",
-		"${goFunctionConfigs}",
-	}
+		"-instance-conf-path",
+		GoInstanceConfPath,
+	)
 
 	return args
 }
 
+func getProcessCustomRuntimeArgs(custom *v1alpha1.CustomRuntime, clusterName, uid string, authProvided, tlsProvided bool,
+	state *v1alpha1.Stateful, tlsConfig TLSConfig, tuning runtimeTuning) []string {
+	args := append(append([]string{}, custom.Command...), custom.Args...)
+	args = append(args,
+		"--instance_id",
+		envArg(EnvShardID),
+		"--function_id",
+		fmt.Sprintf("%s-%s", envArg(EnvShardID), uid),
+		"--function_version",
+		"0",
+		"--function_details",
+		FunctionDetailsDirectory+FunctionDetailsFile,
+		"--pulsar_serviceurl",
+		envArg("brokerServiceURL"),
+		"--max_buffered_tuples",
+		strconv.Itoa(int(tuning.maxBufferedTuples)),
+		"--port",
+		strconv.Itoa(int(GRPCPort.ContainerPort)),
+		"--metrics_port",
+		strconv.Itoa(int(MetricsPort.ContainerPort)),
+		"--expected_healthcheck_interval",
+		strconv.Itoa(int(tuning.expectedHealthCheckInterval)),
+		"--cluster_name",
+		clusterName,
+	)
+	if authProvided {
+		args = append(args,
+			"--client_auth_plugin",
+			envArg("clientAuthenticationPlugin"),
+			"--client_auth_params",
+			envArg("clientAuthenticationParameters"))
+	}
+	if reflect.ValueOf(tlsConfig).IsNil() {
+		// the other settings of the TLS secret are read from the environment, they have no default in the arguments
+		if tlsProvided {
+			args = append(args, "--use_tls", "true", "--tls_trust_cert_path", envArg("tlsTrustCertsFilePath"))
+		} else {
+			args = append(args, "--use_tls", "false")
+		}
+	} else {
+		args = append(args, "--use_tls", strconv.FormatBool(tlsConfig.IsEnabled()))
+		if tlsConfig.IsEnabled() {
+			args = append(args,
+				"--tls_allow_insecure",
+				tlsConfig.AllowInsecureConnection(),
+				"--hostname_verification_enabled",
+				tlsConfig.EnableHostnameVerification())
+			if tlsConfig.HasSecretVolume() {
+				args = append(args, "--tls_trust_cert_path", getTLSTrustCertPath(tlsConfig, tlsConfig.SecretKey()))
+			}
+		}
+	}
+	if state != nil && state.Pulsar != nil && state.Pulsar.ServiceURL != "" {
+		args = append(args, "--state_storage_serviceurl", state.Pulsar.ServiceURL)
+	}
+	return args
+}
+
+// envArg returns the reference to the environment variable which Kubernetes expands in the command of a container
+func envArg(name string) string {
+	return "$(" + name + ")"
+}
+
 func convertProcessingGuarantee(input v1alpha1.ProcessGuarantee) proto.ProcessingGuarantees {
 	switch input {
 	case v1alpha1.AtmostOnce:
@@ -697,9 +833,21 @@ func getUserConfig(configs *v1alpha1.Config) string {
 	return string(bytes)
 }
 
-func generateContainerEnv(function *v1alpha1.Function) []corev1.EnvVar {
+func generateContainerEnv(function *v1alpha2.Function) []corev1.EnvVar {
 	envs := generateBasicContainerEnv(function.Spec.SecretsMap, function.Spec.Pod.Env)
 
+	envs = append(envs, makePythonDependenciesEnv(function.Spec.Runtime)...)
+
+	// the custom runtime is not started by a shell which would set the shard id from the pod name
+	if function.Spec.Custom != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name: EnvShardID,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.labels['" + LabelPodIndex + "']"},
+			},
+		})
+	}
+
 	// add env to set logging level for Go runtime
 	if level := parseGolangLogLevel(function.Spec.Golang); level != "" {
 		envs = append(envs, corev1.EnvVar{
@@ -1052,13 +1200,6 @@ func getPythonSecretProviderArgs(secretMaps map[string]v1alpha1.SecretRef) []str
 }
 
 // Java command requires memory values in resource.DecimalSI format
-func getDecimalSIMemory(quantity *resource.Quantity) string {
-	if quantity.Format == resource.DecimalSI {
-		return quantity.String()
-	}
-	return resource.NewQuantity(quantity.Value(), resource.DecimalSI).String()
-}
-
 func getTLSTrustCertPath(tlsVolume TLSConfig, path string) string {
 	return fmt.Sprintf("%s/%s", tlsVolume.GetMountPath(), path)
 }
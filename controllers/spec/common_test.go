@@ -26,6 +26,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -94,6 +95,17 @@ func TestGetDownloadCommand(t *testing.T) {
 	}
 }
 
+func TestMakeInstanceGRPCAddress(t *testing.T) {
+	objectMeta := &metav1.ObjectMeta{
+		Name:      "java-function-sample-function",
+		Namespace: "default",
+	}
+	assert.Equal(t, "java-function-sample-function-0.java-function-sample-function-headless.default.svc:9093",
+		MakeInstanceGRPCAddress(objectMeta, 0))
+	assert.Equal(t, "java-function-sample-function-2.java-function-sample-function-headless.default.svc:9093",
+		MakeInstanceGRPCAddress(objectMeta, 2))
+}
+
 func TestGetFunctionRunnerImage(t *testing.T) {
 	javaRuntime := v1alpha1.Runtime{Java: &v1alpha1.JavaRuntime{
 		Jar:         "test.jar",
@@ -178,20 +190,38 @@ func TestGetSourceRunnerImage(t *testing.T) {
 }
 
 func TestMakeGoFunctionCommand(t *testing.T) {
-	function := makeGoFunctionSample(TestFunctionName)
-	commands := MakeGoFunctionCommand("", "/pulsar/go-func", function)
+	commands := MakeGoFunctionCommand("", "/pulsar/go-func")
 	assert.Equal(t, commands[0], "sh")
 	assert.Equal(t, commands[1], "-c")
 	assert.True(t, strings.HasPrefix(commands[2], "SHARD_ID=${POD_NAME##*-} && echo shardId=${SHARD_ID}"))
 	innerCommands := strings.Split(commands[2], "&&")
 	assert.Equal(t, innerCommands[0], "SHARD_ID=${POD_NAME##*-} ")
 	assert.Equal(t, innerCommands[1], " echo shardId=${SHARD_ID} ")
-	assert.True(t, strings.HasPrefix(innerCommands[2], " GO_FUNCTION_CONF"))
-	assert.Equal(t, innerCommands[3], " goFunctionConfigs=${GO_FUNCTION_CONF} ")
-	assert.Equal(t, innerCommands[4], " echo goFunctionConfigs=\"'${goFunctionConfigs}'\" ")
-	assert.Equal(t, innerCommands[5], " ls -l /pulsar/go-func ")
-	assert.Equal(t, innerCommands[6], " chmod +x /pulsar/go-func ")
-	assert.Equal(t, innerCommands[7], " exec /pulsar/go-func -instance-conf ${goFunctionConfigs}")
+	assert.Equal(t, innerCommands[2], " sed -e 's|${SHARD_ID}|'\"${SHARD_ID}\"'|g' "+
+		"-e 's|${brokerServiceURL}|'\"${brokerServiceURL}\"'|g' "+
+		"/pulsar/function-details/go-instance-conf.json > /tmp/go-instance-conf.json ")
+	assert.Equal(t, innerCommands[3], " cat /tmp/go-instance-conf.json ")
+	assert.Equal(t, innerCommands[4], " ls -l /pulsar/go-func ")
+	assert.Equal(t, innerCommands[5], " chmod +x /pulsar/go-func ")
+	assert.Equal(t, innerCommands[6], " exec /pulsar/go-func -instance-conf-path /tmp/go-instance-conf.json")
+}
+
+func TestMakeFunctionCommandWithFilePackage(t *testing.T) {
+	fnc := makeFunctionSample(TestFunctionName)
+	fnc.Spec.Java.JarLocation = "file:///pulsar/functions/exclamation.jar"
+	commands := makeFunctionCommand(fnc)
+	assert.False(t, strings.Contains(commands[2], PulsarAdminExecutableFile))
+	assert.True(t, strings.Contains(commands[2], "--jar /pulsar/functions/exclamation.jar"))
+
+	// the package named in the runtime is read in place when the location has no path
+	fnc.Spec.Java.JarLocation = "file://"
+	commands = makeFunctionCommand(fnc)
+	assert.False(t, strings.Contains(commands[2], PulsarAdminExecutableFile))
+	assert.True(t, strings.Contains(commands[2], "--jar "+fnc.Spec.Java.Jar))
+
+	commands = MakeGoFunctionCommand("file:///pulsar/functions/go-func", "go-func")
+	assert.False(t, strings.Contains(commands[2], "chmod"))
+	assert.True(t, strings.HasSuffix(commands[2], "exec /pulsar/functions/go-func -instance-conf-path /tmp/go-instance-conf.json"))
 }
 
 const TestClusterName string = "test-pulsar"
@@ -206,15 +236,15 @@ func makeSampleObjectMeta(name string) *metav1.ObjectMeta {
 	}
 }
 
-func makeGoFunctionSample(functionName string) *v1alpha1.Function {
+func makeGoFunctionSample(functionName string) *v1alpha2.Function {
 	maxPending := int32(1000)
 	replicas := int32(1)
 	maxReplicas := int32(5)
 	trueVal := true
-	return &v1alpha1.Function{
+	return &v1alpha2.Function{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Function",
-			APIVersion: "compute.functionmesh.io/v1alpha1",
+			APIVersion: "compute.functionmesh.io/v1alpha2",
 		},
 		ObjectMeta: *makeSampleObjectMeta(functionName),
 		Spec: v1alpha1.FunctionSpec{
@@ -666,3 +696,16 @@ func TestGenerateContainerVolumeMounts(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPackageURLs(t *testing.T) {
+	assert.Equal(t, []string{}, GetPackageURLs(v1alpha1.Runtime{}))
+	assert.Equal(t, []string{}, GetPackageURLs(v1alpha1.Runtime{
+		Java: &v1alpha1.JavaRuntime{JarLocation: "https://example.com/function.jar"},
+	}))
+	assert.Equal(t, []string{"function://public/default/test@v1"}, GetPackageURLs(v1alpha1.Runtime{
+		Java: &v1alpha1.JavaRuntime{JarLocation: "function://public/default/test@v1"},
+	}))
+	assert.Equal(t, []string{"sink://public/default/test@v1"}, GetPackageURLs(v1alpha1.Runtime{
+		Python: &v1alpha1.PythonRuntime{PyLocation: "sink://public/default/test@v1"},
+	}))
+}
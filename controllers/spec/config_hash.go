@@ -0,0 +1,120 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"sort"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The pods of a component read the ConfigMaps and the Secrets it refers to only on start, so the content of
+// those objects is hashed into an annotation of the pod template, and a change of the content rolls the pods.
+
+// makeReferences returns the sorted names of the ConfigMaps and the Secrets read by the pods of a component
+func makeReferences(jobName string, messaging *v1alpha1.PulsarMessaging, secretsMap map[string]v1alpha1.SecretRef,
+	runtime v1alpha1.Runtime) (configMaps []string, secrets []string) {
+	configMapSet := map[string]bool{}
+	secretSet := map[string]bool{}
+	if messaging != nil {
+		configMapSet[messaging.PulsarConfig] = true
+		secretSet[messaging.AuthSecret] = true
+		secretSet[messaging.TLSSecret] = true
+		if messaging.TLSConfig != nil {
+			secretSet[messaging.TLSConfig.CertSecretName] = true
+		}
+		// the copies of the connection are referred to once the connection is resolved
+		if messaging.ConnectionRef != nil {
+			configMapSet[MakePulsarConnectionObjectName(jobName)] = true
+			secretSet[MakePulsarConnectionObjectName(jobName)] = true
+			secretSet[MakePulsarConnectionTLSSecretName(jobName)] = true
+		}
+	}
+	for _, ref := range secretsMap {
+		secretSet[ref.Path] = true
+	}
+	for _, logConfig := range getRuntimeLogConfigNames(runtime.Java, runtime.Python, runtime.Golang) {
+		configMapSet[logConfig.Name] = true
+	}
+	if dependencies := getPythonDependencies(runtime); dependencies != nil {
+		if ref := dependencies.RequirementsConfigMap; ref != nil && installsPythonDependencies(dependencies) {
+			configMapSet[ref.Name] = true
+		}
+		secretSet[dependencies.IndexSecret] = true
+	}
+	return sortedNames(configMapSet), sortedNames(secretSet)
+}
+
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MakeConfigHash returns the hash of the content of the ConfigMaps and the Secrets read by the pods of a component,
+// it is empty when the component refers to none of them
+func MakeConfigHash(configMaps []corev1.ConfigMap, secrets []corev1.Secret) string {
+	if len(configMaps) == 0 && len(secrets) == 0 {
+		return ""
+	}
+	h := sha256.New()
+	for i := range configMaps {
+		writeHashEntry(h, "configmap", configMaps[i].Name)
+		binaryData := make(map[string]string, len(configMaps[i].BinaryData))
+		for key, value := range configMaps[i].BinaryData {
+			binaryData[key] = string(value)
+		}
+		writeHashData(h, configMaps[i].Data)
+		writeHashData(h, binaryData)
+	}
+	for i := range secrets {
+		writeHashEntry(h, "secret", secrets[i].Name)
+		data := make(map[string]string, len(secrets[i].Data))
+		for key, value := range secrets[i].Data {
+			data[key] = string(value)
+		}
+		writeHashData(h, data)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func writeHashData(h hash.Hash, data map[string]string) {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		writeHashEntry(h, key, data[key])
+	}
+}
+
+// writeHashEntry writes the lengths along with the values, so that the entries cannot be confused with each other
+func writeHashEntry(h hash.Hash, key, value string) {
+	fmt.Fprintf(h, "%d:%s%d:%s", len(key), key, len(value), value)
+}
@@ -0,0 +1,64 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMakeFunctionReferences(t *testing.T) {
+	fnc := makeFunctionSample("test")
+	fnc.Spec.Pulsar.AuthSecret = "pulsar-auth"
+	fnc.Spec.Pulsar.TLSConfig = &v1alpha1.PulsarTLSConfig{
+		TLSConfig: v1alpha1.TLSConfig{Enabled: true, CertSecretName: "pulsar-ca", CertSecretKey: "ca.crt"},
+	}
+	fnc.Spec.SecretsMap = map[string]v1alpha1.SecretRef{
+		"username": {Path: "credentials", Key: "username"},
+		"password": {Path: "credentials", Key: "password"},
+	}
+	fnc.Spec.Java.Log = &v1alpha1.RuntimeLogConfig{LogConfig: &v1alpha1.LogConfig{Name: "java-log", Key: "log4j.xml"}}
+
+	configMaps, secrets := MakeFunctionReferences(fnc)
+	assert.DeepEqual(t, configMaps, []string{"java-log", TestClusterName})
+	assert.DeepEqual(t, secrets, []string{"credentials", "pulsar-auth", "pulsar-ca"})
+}
+
+func TestMakeConfigHash(t *testing.T) {
+	assert.Equal(t, MakeConfigHash(nil, nil), "")
+
+	configMaps := []corev1.ConfigMap{{
+		ObjectMeta: metav1.ObjectMeta{Name: "pulsar"},
+		Data:       map[string]string{"webServiceURL": "http://pulsar:8080", "brokerServiceURL": "pulsar://pulsar:6650"},
+	}}
+	secrets := []corev1.Secret{{
+		ObjectMeta: metav1.ObjectMeta{Name: "pulsar-auth"},
+		Data:       map[string][]byte{"clientAuthenticationParameters": []byte("token:old")},
+	}}
+	hash := MakeConfigHash(configMaps, secrets)
+	assert.Assert(t, hash != "")
+	assert.Equal(t, MakeConfigHash(configMaps, secrets), hash)
+
+	// a rotated token changes the hash
+	secrets[0].Data["clientAuthenticationParameters"] = []byte("token:new")
+	assert.Assert(t, MakeConfigHash(configMaps, secrets) != hash)
+}
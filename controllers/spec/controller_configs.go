@@ -19,6 +19,7 @@ package spec
 
 import (
 	"io/ioutil"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -29,10 +30,44 @@ type RunnerImages struct {
 	Go     string `yaml:"go,omitempty"`
 }
 
+// MonitorConfigs are the defaults of the monitors created for the components
+type MonitorConfigs struct {
+	// Enabled creates a monitor for the components without a monitor policy
+	Enabled  bool              `yaml:"enabled,omitempty"`
+	Kind     string            `yaml:"kind,omitempty"`
+	Interval string            `yaml:"interval,omitempty"`
+	Labels   map[string]string `yaml:"labels,omitempty"`
+}
+
 type ControllerConfigs struct {
 	RunnerImages        RunnerImages      `yaml:"runnerImages,omitempty"`
 	ResourceLabels      map[string]string `yaml:"resourceLabels,omitempty"`
 	ResourceAnnotations map[string]string `yaml:"resourceAnnotations,omitempty"`
+	// InstanceStatusSyncInterval is the interval to scrape the runtime status of the instances,
+	// scraping is disabled when it is set to zero
+	InstanceStatusSyncInterval time.Duration  `yaml:"instanceStatusSyncInterval,omitempty"`
+	Monitor                    MonitorConfigs `yaml:"monitor,omitempty"`
+	// PackageDownload configures the init container downloading the packages of the components
+	PackageDownload PackageDownloadConfigs `yaml:"packageDownload,omitempty"`
+	// RuntimeTuning is the tuning of the instances of the components without their own tuning
+	RuntimeTuning RuntimeTuningConfigs `yaml:"runtimeTuning,omitempty"`
+}
+
+// PackageDownloadConfigs are the retries of the package downloads, the backoff grows with each attempt
+type PackageDownloadConfigs struct {
+	Retries int32         `yaml:"retries,omitempty"`
+	Backoff time.Duration `yaml:"backoff,omitempty"`
+}
+
+// RuntimeTuningConfigs are the defaults of the runtime tuning of the components
+type RuntimeTuningConfigs struct {
+	MaxBufferedTuples int32 `yaml:"maxBufferedTuples,omitempty"`
+	// ExpectedHealthCheckIntervalSeconds is -1 by default, which turns the health checks of the instances off
+	ExpectedHealthCheckIntervalSeconds int32 `yaml:"expectedHealthCheckIntervalSeconds,omitempty"`
+	// JVMHeapPercentage is the maximum heap of the Java instances as a percentage of the container memory
+	JVMHeapPercentage      int32    `yaml:"jvmHeapPercentage,omitempty"`
+	JVMOptions             []string `yaml:"jvmOptions,omitempty"`
+	PythonInterpreterFlags []string `yaml:"pythonInterpreterFlags,omitempty"`
 }
 
 var Configs = DefaultConfigs()
@@ -44,6 +79,16 @@ func DefaultConfigs() *ControllerConfigs {
 			Python: DefaultPythonRunnerImage,
 			Go:     DefaultGoRunnerImage,
 		},
+		InstanceStatusSyncInterval: DefaultInstanceStatusSyncInterval,
+		PackageDownload: PackageDownloadConfigs{
+			Retries: DefaultPackageDownloadRetries,
+			Backoff: DefaultPackageDownloadBackoff,
+		},
+		RuntimeTuning: RuntimeTuningConfigs{
+			MaxBufferedTuples:                  DefaultMaxBufferedTuples,
+			ExpectedHealthCheckIntervalSeconds: DefaultExpectedHealthCheckInterval,
+			JVMHeapPercentage:                  DefaultJVMHeapPercentage,
+		},
 	}
 }
 
@@ -19,6 +19,7 @@ package spec
 
 import (
 	"testing"
+	"time"
 
 	"gotest.tools/assert"
 )
@@ -38,6 +39,11 @@ func TestParseConfigFiles(t *testing.T) {
 	assert.Assert(t, Configs.ResourceLabels["functionmesh.io/managedBy"] == "function-mesh")
 	assert.Assert(t, Configs.ResourceLabels["foo"] == "bar")
 	assert.Assert(t, Configs.ResourceAnnotations["fooAnnotation"] == "barAnnotation")
+	assert.Assert(t, Configs.InstanceStatusSyncInterval == time.Minute)
+	assert.Assert(t, Configs.Monitor.Enabled)
+	assert.Assert(t, Configs.Monitor.Kind == "ServiceMonitor")
+	assert.Assert(t, Configs.Monitor.Interval == "15s")
+	assert.Assert(t, Configs.Monitor.Labels["release"] == "prometheus")
 }
 
 func TestParseEmptyConfigFiles(t *testing.T) {
@@ -53,4 +59,6 @@ func TestParseEmptyConfigFiles(t *testing.T) {
 	assert.Assert(t, Configs.RunnerImages.Go == DefaultGoRunnerImage)
 	assert.Assert(t, len(Configs.ResourceLabels) == 0)
 	assert.Assert(t, len(Configs.ResourceAnnotations) == 0)
+	assert.Assert(t, Configs.InstanceStatusSyncInterval == DefaultInstanceStatusSyncInterval)
+	assert.Assert(t, !Configs.Monitor.Enabled)
 }
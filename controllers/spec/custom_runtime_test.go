@@ -0,0 +1,69 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestMakeCustomFunctionStatefulSet(t *testing.T) {
+	fnc := makeFunctionSample(TestFunctionName)
+	fnc.Spec.Java = nil
+	fnc.Spec.Image = "example/nodejs-function-instance:latest"
+	fnc.Spec.Custom = &v1alpha1.CustomRuntime{
+		Command: []string{"node", "/app/instance.js"},
+		Args:    []string{"--log-format=json"},
+	}
+
+	statefulSet := MakeFunctionStatefulSet(fnc)
+	podSpec := statefulSet.Spec.Template.Spec
+	assert.Equal(t, len(podSpec.InitContainers), 0)
+	instance := podSpec.Containers[len(podSpec.Containers)-1]
+	assert.Equal(t, instance.Image, fnc.Spec.Image)
+	assert.DeepEqual(t, instance.Ports, []corev1.ContainerPort{GRPCPort, MetricsPort})
+	assert.Assert(t, hasVolumeMount(instance.VolumeMounts, FunctionDetailsVolumeName))
+
+	// the command is not started by a shell and gets the arguments of the runners after its own arguments
+	assert.DeepEqual(t, instance.Command[:5], []string{"node", "/app/instance.js", "--log-format=json",
+		"--instance_id", "$(SHARD_ID)"})
+	command := strings.Join(instance.Command, " ")
+	assert.Assert(t, strings.Contains(command, "--function_id $(SHARD_ID)-"+string(fnc.UID)))
+	assert.Assert(t, strings.Contains(command, "--function_details /pulsar/function-details/function-details.json"))
+	assert.Assert(t, strings.Contains(command, "--pulsar_serviceurl $(brokerServiceURL)"))
+	assert.Assert(t, strings.Contains(command, "--port 9093 --metrics_port 9094"))
+	assert.Assert(t, strings.Contains(command, "--state_storage_serviceurl bk://localhost:4181"))
+	assert.Assert(t, !strings.Contains(command, "${"))
+	assert.DeepEqual(t, instance.Env[len(instance.Env)-1], corev1.EnvVar{
+		Name: EnvShardID,
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.labels['apps.kubernetes.io/pod-index']"},
+		},
+	})
+
+	configMap := MakeFunctionDetailsConfigMap(fnc)
+	assert.Assert(t, strings.Contains(configMap.Data[FunctionDetailsFile], fnc.Spec.ClassName))
+
+	// the custom functions are exposed and scaled in the same way
+	assert.Assert(t, MakeFunctionService(fnc) != nil)
+	assert.Assert(t, MakeFunctionHPA(fnc) != nil)
+}
@@ -20,17 +20,14 @@ package spec
 import (
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
 	appsv1 "k8s.io/api/apps/v1"
 	autov2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-// log is for logging in this package.
-var log = logf.Log.WithName("function-resource")
-
-func MakeFunctionHPA(function *v1alpha1.Function) *autov2beta2.HorizontalPodAutoscaler {
+func MakeFunctionHPA(function *v1alpha2.Function) *autov2beta2.HorizontalPodAutoscaler {
 	objectMeta := MakeFunctionObjectMeta(function)
 	targetRef := autov2beta2.CrossVersionObjectReference{
 		Kind:       function.Kind,
@@ -39,53 +36,85 @@ func MakeFunctionHPA(function *v1alpha1.Function) *autov2beta2.HorizontalPodAuto
 	}
 	if isBuiltinHPAEnabled(function.Spec.Replicas, function.Spec.MaxReplicas, function.Spec.Pod) {
 		return makeBuiltinHPA(objectMeta, *function.Spec.Replicas, *function.Spec.MaxReplicas, targetRef,
-			function.Spec.Pod.BuiltinAutoscaler)
+			function.Spec.Pod.BuiltinAutoscaler, MakeFunctionLabels(function))
 	} else if !isDefaultHPAEnabled(function.Spec.Replicas, function.Spec.MaxReplicas, function.Spec.Pod) {
 		return makeHPA(objectMeta, *function.Spec.Replicas, *function.Spec.MaxReplicas, function.Spec.Pod, targetRef)
 	}
 	return makeDefaultHPA(objectMeta, *function.Spec.Replicas, *function.Spec.MaxReplicas, targetRef)
 }
 
-func MakeFunctionService(function *v1alpha1.Function) *corev1.Service {
-	labels := makeFunctionLabels(function)
+func MakeFunctionService(function *v1alpha2.Function) *corev1.Service {
+	labels := MakeFunctionLabels(function)
 	objectMeta := MakeFunctionObjectMeta(function)
 	return MakeService(objectMeta, labels)
 }
 
-func MakeFunctionStatefulSet(function *v1alpha1.Function) *appsv1.StatefulSet {
+func MakeFunctionStatefulSet(function *v1alpha2.Function) *appsv1.StatefulSet {
 	objectMeta := MakeFunctionObjectMeta(function)
-	return MakeStatefulSet(objectMeta, function.Spec.Replicas,
-		MakeFunctionContainer(function), makeFunctionVolumes(function), makeFunctionLabels(function), function.Spec.Pod)
+	container := MakeFunctionContainer(function)
+	downloadContainer := makePackageDownloadContainer(makePackageDownload(function.Spec.Runtime), container,
+		function.Spec.Pulsar.AuthSecret != "", function.Spec.Pulsar.TLSSecret != "", function.Spec.Pulsar.TLSConfig)
+	// the package is downloaded before the dependencies are installed
+	policy := withPythonDependencies(function.Spec.Pod, makePythonDependenciesContainer(function.Spec.Runtime, container))
+	policy = withDetailsHash(withPackageDownload(policy, downloadContainer), MakeFunctionDetailsConfigMap(function))
+	return MakeStatefulSet(objectMeta, makeDesiredReplicas(function.Spec.Replicas, function.Spec.Suspend, function.Status.Conditions),
+		container, makeFunctionVolumes(function), MakeFunctionLabels(function), policy)
+}
+
+// MakeFunctionDetailsConfigMap returns the ConfigMap with the details read by the instances of the function,
+// the Go instances read their own conf instead of the function details
+func MakeFunctionDetailsConfigMap(function *v1alpha2.Function) *corev1.ConfigMap {
+	data := map[string]string{}
+	if function.Spec.Java == nil && function.Spec.Python == nil && function.Spec.Golang != nil {
+		data[GoInstanceConfFile] = generateGoFunctionConf(function)
+	} else {
+		data[FunctionDetailsFile] = makeFunctionDetailsFile(generateFunctionDetailsInJSON(function))
+	}
+	if function.Spec.Python != nil && function.Spec.WindowConfig != nil {
+		data[PythonWindowFunctionExecutorFile] = pythonWindowFunctionExecutor
+	}
+	return makeDetailsConfigMap(MakeFunctionObjectMeta(function), data)
+}
+
+// MakeFunctionReferences returns the names of the ConfigMaps and the Secrets read by the pods of the function
+func MakeFunctionReferences(function *v1alpha2.Function) (configMaps []string, secrets []string) {
+	return makeReferences(MakeFunctionObjectMeta(function).Name, function.Spec.Pulsar, function.Spec.SecretsMap,
+		function.Spec.Runtime)
 }
 
-func MakeFunctionObjectMeta(function *v1alpha1.Function) *metav1.ObjectMeta {
+func MakeFunctionObjectMeta(function *v1alpha2.Function) *metav1.ObjectMeta {
 	return &metav1.ObjectMeta{
 		Name:      makeJobName(function.Name, v1alpha1.FunctionComponent),
 		Namespace: function.Namespace,
-		Labels:    makeFunctionLabels(function),
+		Labels:    MakeFunctionLabels(function),
 		OwnerReferences: []metav1.OwnerReference{
 			*metav1.NewControllerRef(function, function.GroupVersionKind()),
 		},
 	}
 }
 
-func makeFunctionVolumes(function *v1alpha1.Function) []corev1.Volume {
-	return generatePodVolumes(function.Spec.Pod.Volumes,
+func makeFunctionVolumes(function *v1alpha2.Function) []corev1.Volume {
+	volumes := generatePodVolumes(function.Spec.Pod.Volumes,
 		function.Spec.Output.ProducerConf,
 		function.Spec.Input.SourceSpecs,
 		function.Spec.Pulsar.TLSConfig,
 		getRuntimeLogConfigNames(function.Spec.Java, function.Spec.Python, function.Spec.Golang))
+	volumes = appendDetailsVolume(volumes, MakeFunctionObjectMeta(function).Name)
+	volumes = appendPythonDependenciesVolumes(volumes, function.Spec.Runtime)
+	return appendPackageVolume(volumes, function.Spec.Runtime)
 }
 
-func makeFunctionVolumeMounts(function *v1alpha1.Function) []corev1.VolumeMount {
-	return generateContainerVolumeMounts(function.Spec.VolumeMounts,
+func makeFunctionVolumeMounts(function *v1alpha2.Function) []corev1.VolumeMount {
+	mounts := generateContainerVolumeMounts(function.Spec.VolumeMounts,
 		function.Spec.Output.ProducerConf,
 		function.Spec.Input.SourceSpecs,
 		function.Spec.Pulsar.TLSConfig,
 		getRuntimeLogConfigNames(function.Spec.Java, function.Spec.Python, function.Spec.Golang))
+	mounts = appendPythonDependenciesVolumeMounts(appendDetailsVolumeMount(mounts), function.Spec.Runtime)
+	return appendPackageVolumeMount(mounts, function.Spec.Runtime)
 }
 
-func MakeFunctionContainer(function *v1alpha1.Function) *corev1.Container {
+func MakeFunctionContainer(function *v1alpha2.Function) *corev1.Container {
 	imagePullPolicy := function.Spec.ImagePullPolicy
 	if imagePullPolicy == "" {
 		imagePullPolicy = corev1.PullIfNotPresent
@@ -105,7 +134,7 @@ func MakeFunctionContainer(function *v1alpha1.Function) *corev1.Container {
 	}
 }
 
-func makeFunctionLabels(function *v1alpha1.Function) map[string]string {
+func MakeFunctionLabels(function *v1alpha2.Function) map[string]string {
 	labels := make(map[string]string)
 	labels["app"] = AppFunctionMesh
 	labels["component"] = ComponentFunction
@@ -115,7 +144,7 @@ func makeFunctionLabels(function *v1alpha1.Function) map[string]string {
 	return labels
 }
 
-func makeFunctionCommand(function *v1alpha1.Function) []string {
+func makeFunctionCommand(function *v1alpha2.Function) []string {
 	spec := function.Spec
 
 	if spec.Java != nil {
@@ -124,31 +153,35 @@ func makeFunctionCommand(function *v1alpha1.Function) []string {
 				spec.Name, spec.ClusterName,
 				generateJavaLogConfigCommand(function.Spec.Java),
 				parseJavaLogLevel(function.Spec.Java),
-				generateFunctionDetailsInJSON(function),
-				getDecimalSIMemory(spec.Resources.Requests.Memory()), spec.Java.ExtraDependenciesDir, string(function.UID),
+				makeFunctionDetailsArg(),
+				makeJavaHeapSize(spec.Resources, spec.Tuning), spec.Java.ExtraDependenciesDir, string(function.UID),
 				spec.Pulsar.AuthSecret != "", spec.Pulsar.TLSSecret != "", function.Spec.SecretsMap,
-				function.Spec.StateConfig, function.Spec.Pulsar.TLSConfig)
+				function.Spec.StateConfig, function.Spec.Pulsar.TLSConfig, function.Spec.Tuning)
 		}
 	} else if spec.Python != nil {
 		if spec.Python.Py != "" {
 			return MakePythonFunctionCommand(spec.Python.PyLocation, spec.Python.Py,
 				spec.Name, spec.ClusterName,
 				generatePythonLogConfigCommand(function.Spec.Python),
-				generateFunctionDetailsInJSON(function), string(function.UID),
+				makeFunctionDetailsArg(), string(function.UID),
 				spec.Pulsar.AuthSecret != "", spec.Pulsar.TLSSecret != "", function.Spec.SecretsMap,
-				function.Spec.StateConfig, function.Spec.Pulsar.TLSConfig)
+				function.Spec.StateConfig, function.Spec.Pulsar.TLSConfig, function.Spec.Tuning,
+				function.Spec.Python.Dependencies)
 		}
 	} else if spec.Golang != nil {
 		if spec.Golang.Go != "" {
-			return MakeGoFunctionCommand(spec.Golang.GoLocation, spec.Golang.Go,
-				function)
+			return MakeGoFunctionCommand(spec.Golang.GoLocation, spec.Golang.Go)
 		}
+	} else if spec.Custom != nil {
+		return MakeCustomFunctionCommand(spec.Custom, spec.ClusterName, string(function.UID),
+			spec.Pulsar.AuthSecret != "", spec.Pulsar.TLSSecret != "", function.Spec.StateConfig,
+			function.Spec.Pulsar.TLSConfig, function.Spec.Tuning)
 	}
 
 	return nil
 }
 
-func generateFunctionDetailsInJSON(function *v1alpha1.Function) string {
+func generateFunctionDetailsInJSON(function *v1alpha2.Function) string {
 	functionDetails := convertFunctionDetails(function)
 	marshaler := &jsonpb.Marshaler{}
 	json, err := marshaler.MarshalToString(functionDetails)
@@ -156,6 +189,5 @@ func generateFunctionDetailsInJSON(function *v1alpha1.Function) string {
 		// TODO
 		panic(err)
 	}
-	log.Info(json)
 	return json
 }
@@ -0,0 +1,128 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The details of a component are rendered into a ConfigMap owned by the component and mounted into its pods,
+// so that the content is never parsed or expanded by the shell. The Go instances read their conf from a file,
+// the Java runner expands the argument of the details file with the @ prefix, and the Python runner is started
+// by a launcher which replaces the argument with the content of the file.
+
+const (
+	FunctionDetailsVolumeName = "function-details"
+	FunctionDetailsDirectory  = "/pulsar/function-details/"
+	FunctionDetailsFile       = "function-details.json"
+	GoInstanceConfFile        = "go-instance-conf.json"
+	// GoInstanceConfPath is the path of the Go instance conf with the placeholders replaced
+	GoInstanceConfPath = "/tmp/go-instance-conf.json"
+)
+
+// MakeFunctionDetailsConfigMapName returns the name of the ConfigMap with the details of the component
+func MakeFunctionDetailsConfigMapName(jobName string) string {
+	return jobName + "-details"
+}
+
+// makeDetailsConfigMap returns the ConfigMap with the details, the object meta is the one of the statefulset
+func makeDetailsConfigMap(objectMeta *metav1.ObjectMeta, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            MakeFunctionDetailsConfigMapName(objectMeta.Name),
+			Namespace:       objectMeta.Namespace,
+			Labels:          objectMeta.Labels,
+			OwnerReferences: objectMeta.OwnerReferences,
+		},
+		Data: data,
+	}
+}
+
+// pythonInstanceLauncher runs the Python instance given as the first argument, with the argument of the details
+// file with the @ prefix replaced with the content of the file. The modules shipped in the details directory,
+// such as the window function executor, are importable after the ones of the instance and the package.
+const pythonInstanceLauncher = `import os, runpy, sys
+script, args = sys.argv[1], sys.argv[2:]
+for i in range(1, len(args)):
+    if args[i - 1] == "--function_details" and args[i].startswith("@"):
+        with open(args[i][1:]) as details:
+            args[i] = details.read()
+sys.argv = [script] + args
+sys.path[0] = os.path.dirname(script)
+sys.path.append("` + FunctionDetailsDirectory + `")
+runpy.run_path(script, run_name="__main__")`
+
+// makeFunctionDetailsArg returns the argument passing the details file to the runners
+func makeFunctionDetailsArg() string {
+	return "@" + FunctionDetailsDirectory + FunctionDetailsFile
+}
+
+// makeFunctionDetailsFile returns the content of the details file, the spaces in the strings of the JSON are escaped,
+// since the lines of a file with the @ prefix are split on the whitespaces by some versions of JCommander
+func makeFunctionDetailsFile(details string) string {
+	return strings.ReplaceAll(details, " ", `\u0020`)
+}
+
+// makeGoInstanceConfCommand returns the command writing the Go instance conf read by the instance,
+// only the placeholders of the shard id and the broker service url are replaced with the environment variables
+func makeGoInstanceConfCommand() string {
+	replacements := ""
+	for _, env := range []string{EnvShardID, "brokerServiceURL"} {
+		replacements += fmt.Sprintf("-e 's|${%s}|'\"${%s}\"'|g' ", env, env)
+	}
+	return fmt.Sprintf("sed %s%s%s > %s", replacements, FunctionDetailsDirectory, GoInstanceConfFile,
+		GoInstanceConfPath)
+}
+
+// withDetailsHash sets the hash of the details into the annotations of the pod template generated with the policy,
+// so that a change of the details rolls the pods
+func withDetailsHash(policy v1alpha1.PodPolicy, configMap *corev1.ConfigMap) v1alpha1.PodPolicy {
+	annotations := make(map[string]string, len(policy.Annotations)+1)
+	for key, value := range policy.Annotations {
+		annotations[key] = value
+	}
+	annotations[AnnotationDetailsHash] = MakeConfigHash([]corev1.ConfigMap{*configMap}, nil)
+	policy.Annotations = annotations
+	return policy
+}
+
+// appendDetailsVolume adds the volume of the ConfigMap with the details
+func appendDetailsVolume(volumes []corev1.Volume, jobName string) []corev1.Volume {
+	return append(volumes, corev1.Volume{
+		Name: FunctionDetailsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: MakeFunctionDetailsConfigMapName(jobName)},
+			},
+		},
+	})
+}
+
+// appendDetailsVolumeMount mounts the ConfigMap with the details into the instance
+func appendDetailsVolumeMount(mounts []corev1.VolumeMount) []corev1.VolumeMount {
+	return append(mounts, corev1.VolumeMount{
+		Name:      FunctionDetailsVolumeName,
+		MountPath: FunctionDetailsDirectory,
+		ReadOnly:  true,
+	})
+}
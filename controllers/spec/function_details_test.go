@@ -0,0 +1,101 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"gotest.tools/assert"
+)
+
+func TestMakeFunctionDetailsConfigMap(t *testing.T) {
+	fnc := makeFunctionSample(TestFunctionName)
+	fnc.Spec.FuncConfig = &v1alpha1.Config{Data: map[string]interface{}{"quote": "it's $HOME"}}
+
+	configMap := MakeFunctionDetailsConfigMap(fnc)
+	assert.Equal(t, configMap.Name, MakeFunctionObjectMeta(fnc).Name+"-details")
+	assert.Equal(t, configMap.Namespace, fnc.Namespace)
+	assert.DeepEqual(t, configMap.OwnerReferences, MakeFunctionObjectMeta(fnc).OwnerReferences)
+	// the details are on a single line without spaces, which the Java runner reads as a single argument
+	details := configMap.Data[FunctionDetailsFile]
+	assert.Assert(t, !strings.ContainsAny(details, " \t\n"))
+	var parsed struct {
+		UserConfig string `json:"userConfig"`
+	}
+	assert.NilError(t, json.Unmarshal([]byte(details), &parsed))
+	assert.Assert(t, strings.Contains(parsed.UserConfig, "it's $HOME"))
+
+	// the runner reads the details from the mounted file instead of the command line
+	commands := makeFunctionCommand(fnc)
+	assert.Assert(t, strings.Contains(commands[2],
+		"--function_details @/pulsar/function-details/function-details.json"))
+	assert.Assert(t, !strings.Contains(commands[2], "it's $HOME"))
+
+	statefulSet := MakeFunctionStatefulSet(fnc)
+	podSpec := statefulSet.Spec.Template.Spec
+	var mounted bool
+	for _, volume := range podSpec.Volumes {
+		if volume.Name == FunctionDetailsVolumeName {
+			assert.Equal(t, volume.ConfigMap.Name, configMap.Name)
+			mounted = true
+		}
+	}
+	assert.Assert(t, mounted, "the details should be mounted into the pods")
+	var mount bool
+	for _, volumeMount := range podSpec.Containers[len(podSpec.Containers)-1].VolumeMounts {
+		if volumeMount.Name == FunctionDetailsVolumeName {
+			assert.Equal(t, volumeMount.MountPath, FunctionDetailsDirectory)
+			mount = true
+		}
+	}
+	assert.Assert(t, mount, "the details should be mounted into the instance")
+
+	// a change of the details rolls the pods
+	hash := statefulSet.Spec.Template.Annotations[AnnotationDetailsHash]
+	assert.Assert(t, hash != "")
+	fnc.Spec.FuncConfig = &v1alpha1.Config{Data: map[string]interface{}{"quote": "changed"}}
+	assert.Assert(t, MakeFunctionStatefulSet(fnc).Spec.Template.Annotations[AnnotationDetailsHash] != hash)
+}
+
+func TestMakeGoFunctionDetailsConfigMap(t *testing.T) {
+	fnc := makeGoFunctionSample(TestFunctionName)
+	configMap := MakeFunctionDetailsConfigMap(fnc)
+	_, ok := configMap.Data[FunctionDetailsFile]
+	assert.Assert(t, !ok, "the Go instances only read the instance conf")
+	conf := configMap.Data[GoInstanceConfFile]
+	assert.Assert(t, strings.Contains(conf, "\"instanceID\":${SHARD_ID}"))
+	assert.Assert(t, strings.Contains(conf, "\"pulsarServiceURL\":\"${brokerServiceURL}\""))
+
+	// the instance reads its conf from a file instead of the command line
+	commands := makeFunctionCommand(fnc)
+	assert.Assert(t, strings.Contains(commands[2], "/pulsar/function-details/go-instance-conf.json > "+GoInstanceConfPath))
+	assert.Assert(t, strings.HasSuffix(commands[2], "-instance-conf-path "+GoInstanceConfPath))
+}
+
+func TestMakePythonFunctionDetailsArg(t *testing.T) {
+	commands := makeFunctionCommand(makePythonFunctionSample(nil))
+	// the launcher replaces the details file with its content before running the instance
+	assert.Assert(t, strings.Contains(commands[2],
+		"-c "+quoteShellArgs([]string{pythonInstanceLauncher})[0]+
+			" /pulsar/instances/python-instance/python_instance_main.py --py "))
+	assert.Assert(t, strings.Contains(commands[2],
+		"--function_details @/pulsar/function-details/function-details.json"))
+}
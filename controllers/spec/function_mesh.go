@@ -19,14 +19,18 @@ package spec
 
 import (
 	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func MakeFunctionComponent(functionName string, mesh *v1alpha1.FunctionMesh,
-	spec *v1alpha1.FunctionSpec) *v1alpha1.Function {
-	return &v1alpha1.Function{
+// The components generated from a mesh are defaulted by their own webhooks when they are stored, the same defaulting
+// is applied to the generated specs so that they compare equal to the stored ones while the mesh does not change.
+
+func MakeFunctionComponent(functionName string, mesh *v1alpha2.FunctionMesh,
+	spec *v1alpha1.FunctionSpec) *v1alpha2.Function {
+	function := &v1alpha2.Function{
 		TypeMeta: metav1.TypeMeta{
-			APIVersion: "compute.functionmesh.io/v1alpha1",
+			APIVersion: "compute.functionmesh.io/v1alpha2",
 			Kind:       "Function",
 		},
 		ObjectMeta: metav1.ObjectMeta{
@@ -36,14 +40,22 @@ func MakeFunctionComponent(functionName string, mesh *v1alpha1.FunctionMesh,
 				*metav1.NewControllerRef(mesh, mesh.GroupVersionKind()),
 			},
 		},
-		Spec: *spec,
+		Spec: *spec.DeepCopy(),
+	}
+	mesh.Spec.Defaults.ApplyToFunction(&function.Spec)
+	if mesh.Spec.Suspend {
+		function.Spec.Suspend = true
 	}
+	hub := &v1alpha1.Function{ObjectMeta: function.ObjectMeta, Spec: function.Spec}
+	hub.Default()
+	function.Spec = hub.Spec
+	return function
 }
 
-func MakeSourceComponent(sourceName string, mesh *v1alpha1.FunctionMesh, spec *v1alpha1.SourceSpec) *v1alpha1.Source {
-	return &v1alpha1.Source{
+func MakeSourceComponent(sourceName string, mesh *v1alpha2.FunctionMesh, spec *v1alpha1.SourceSpec) *v1alpha2.Source {
+	source := &v1alpha2.Source{
 		TypeMeta: metav1.TypeMeta{
-			APIVersion: "compute.functionmesh.io/v1alpha1",
+			APIVersion: "compute.functionmesh.io/v1alpha2",
 			Kind:       "Source",
 		},
 		ObjectMeta: metav1.ObjectMeta{
@@ -53,14 +65,22 @@ func MakeSourceComponent(sourceName string, mesh *v1alpha1.FunctionMesh, spec *v
 				*metav1.NewControllerRef(mesh, mesh.GroupVersionKind()),
 			},
 		},
-		Spec: *spec,
+		Spec: *spec.DeepCopy(),
 	}
+	mesh.Spec.Defaults.ApplyToSource(&source.Spec)
+	if mesh.Spec.Suspend {
+		source.Spec.Suspend = true
+	}
+	hub := &v1alpha1.Source{ObjectMeta: source.ObjectMeta, Spec: source.Spec}
+	hub.Default()
+	source.Spec = hub.Spec
+	return source
 }
 
-func MakeSinkComponent(sinkName string, mesh *v1alpha1.FunctionMesh, spec *v1alpha1.SinkSpec) *v1alpha1.Sink {
-	return &v1alpha1.Sink{
+func MakeSinkComponent(sinkName string, mesh *v1alpha2.FunctionMesh, spec *v1alpha1.SinkSpec) *v1alpha2.Sink {
+	sink := &v1alpha2.Sink{
 		TypeMeta: metav1.TypeMeta{
-			APIVersion: "compute.functionmesh.io/v1alpha1",
+			APIVersion: "compute.functionmesh.io/v1alpha2",
 			Kind:       "Sink",
 		},
 		ObjectMeta: metav1.ObjectMeta{
@@ -70,6 +90,14 @@ func MakeSinkComponent(sinkName string, mesh *v1alpha1.FunctionMesh, spec *v1alp
 				*metav1.NewControllerRef(mesh, mesh.GroupVersionKind()),
 			},
 		},
-		Spec: *spec,
+		Spec: *spec.DeepCopy(),
+	}
+	mesh.Spec.Defaults.ApplyToSink(&sink.Spec)
+	if mesh.Spec.Suspend {
+		sink.Spec.Suspend = true
 	}
+	hub := &v1alpha1.Sink{ObjectMeta: sink.ObjectMeta, Spec: sink.Spec}
+	hub.Default()
+	sink.Spec = hub.Spec
+	return sink
 }
@@ -0,0 +1,95 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMakeFunctionComponentWithMeshDefaults(t *testing.T) {
+	mesh := &v1alpha2.FunctionMesh{
+		ObjectMeta: metav1.ObjectMeta{Name: "mesh", Namespace: "default"},
+		Spec: v1alpha1.FunctionMeshSpec{
+			Defaults: &v1alpha1.MeshComponentDefaults{
+				Tenant:    "team",
+				Namespace: "pipelines",
+				Messaging: v1alpha1.Messaging{Pulsar: &v1alpha1.PulsarMessaging{
+					PulsarConfig: "pulsar-config",
+					AuthSecret:   "pulsar-auth",
+				}},
+				Image: "streamnative/pulsar-functions-java-runner:2.10.0",
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1"),
+						corev1.ResourceMemory: resource.MustParse("1G"),
+					},
+				},
+				Pod: &v1alpha1.PodPolicy{
+					Labels:      map[string]string{"team": "data", "tier": "default"},
+					Tolerations: []corev1.Toleration{{Key: "dedicated", Value: "functions"}},
+					Env: []corev1.EnvVar{
+						{Name: "LOG_LEVEL", Value: "info"},
+						{Name: "REGION", Value: "us"},
+					},
+				},
+			},
+		},
+	}
+	functionSpec := &v1alpha1.FunctionSpec{
+		Name:      "fn",
+		Namespace: "custom",
+		Messaging: v1alpha1.Messaging{Pulsar: &v1alpha1.PulsarMessaging{PulsarConfig: "other-config"}},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+		},
+		Pod: v1alpha1.PodPolicy{
+			Labels:      map[string]string{"tier": "critical"},
+			Tolerations: []corev1.Toleration{{Key: "gpu"}},
+			Env:         []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "EXTRA", Value: "1"}},
+		},
+	}
+
+	function := MakeFunctionComponent("mesh-fn", mesh, functionSpec)
+	assert.Equal(t, function.Spec.Tenant, "team")
+	assert.Equal(t, function.Spec.Namespace, "custom")
+	assert.Equal(t, function.Spec.Image, "streamnative/pulsar-functions-java-runner:2.10.0")
+	assert.Equal(t, function.Spec.Pulsar.PulsarConfig, "other-config")
+	assert.Equal(t, function.Spec.Pulsar.AuthSecret, "pulsar-auth")
+	assert.Equal(t, function.Spec.Resources.Requests.Cpu().String(), "2")
+	assert.Equal(t, function.Spec.Resources.Requests.Memory().String(), "1G")
+	assert.DeepEqual(t, function.Spec.Pod.Labels, map[string]string{"team": "data", "tier": "critical"})
+	assert.DeepEqual(t, function.Spec.Pod.Tolerations,
+		[]corev1.Toleration{{Key: "dedicated", Value: "functions"}, {Key: "gpu"}})
+	assert.DeepEqual(t, function.Spec.Pod.Env, []corev1.EnvVar{
+		{Name: "LOG_LEVEL", Value: "debug"},
+		{Name: "REGION", Value: "us"},
+		{Name: "EXTRA", Value: "1"},
+	})
+
+	// the spec of the mesh is not modified
+	assert.Equal(t, functionSpec.Tenant, "")
+	assert.DeepEqual(t, functionSpec.Pod.Labels, map[string]string{"tier": "critical"})
+	assert.Equal(t, functionSpec.Pulsar.AuthSecret, "")
+}
@@ -18,12 +18,16 @@
 package spec
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
 	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
 	"gotest.tools/assert"
+	autov2beta2 "k8s.io/api/autoscaling/v2beta2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 func TestCreateFunctionDetailsForStatefulFunction(t *testing.T) {
@@ -37,15 +41,166 @@ func TestCreateFunctionDetailsForStatefulFunction(t *testing.T) {
 		"start command should contain bk://localhost:4181")
 }
 
-func makeFunctionSample(functionName string) *v1alpha1.Function {
+func TestCreateFunctionDetailsForWindowFunction(t *testing.T) {
+	fnc := makeFunctionSample("test")
+	windowLength := int32(10)
+	fnc.Spec.WindowConfig = &v1alpha1.WindowConfig{
+		WindowLengthCount:    &windowLength,
+		SlidingIntervalCount: &windowLength,
+	}
+	fnc.Spec.FuncConfig = &v1alpha1.Config{Data: map[string]interface{}{"foo": "bar"}}
+
+	details := convertFunctionDetails(fnc)
+	assert.Equal(t, details.ClassName, WindowFunctionExecutorClass)
+	assert.Equal(t, details.AutoAck, false)
+
+	userConfig := map[string]interface{}{}
+	err := json.Unmarshal([]byte(details.UserConfig), &userConfig)
+	assert.NilError(t, err)
+	assert.Equal(t, userConfig["foo"], "bar")
+	windowConfig, ok := userConfig[WindowConfigKey].(map[string]interface{})
+	assert.Assert(t, ok, "user config should contain the window configs")
+	assert.Equal(t, windowConfig["windowLengthCount"], float64(10))
+	assert.Equal(t, windowConfig["slidingIntervalCount"], float64(10))
+	assert.Equal(t, windowConfig["actualWindowFunctionClassName"], fnc.Spec.ClassName)
+
+	configMap := MakeFunctionDetailsConfigMap(fnc)
+	assert.Assert(t, strings.Contains(configMap.Data[FunctionDetailsFile], WindowFunctionExecutorClass),
+		"function details should contain the window function executor")
+}
+
+func TestCreateFunctionDetailsForPythonWindowFunction(t *testing.T) {
+	fnc := makePythonFunctionSample(nil)
+	windowLength := int64(1000)
+	fnc.Spec.WindowConfig = &v1alpha1.WindowConfig{WindowLengthDurationMs: &windowLength}
+
+	details := convertFunctionDetails(fnc)
+	assert.Equal(t, details.ClassName, PythonWindowFunctionExecutorClass)
+	assert.Equal(t, details.AutoAck, false)
+	assert.Assert(t, strings.Contains(details.UserConfig,
+		`"actualWindowFunctionClassName":"`+fnc.Spec.ClassName+`"`))
+
+	// the executor is shipped with the details and importable by the instance
+	configMap := MakeFunctionDetailsConfigMap(fnc)
+	assert.Equal(t, configMap.Data[PythonWindowFunctionExecutorFile], pythonWindowFunctionExecutor)
+	assert.Assert(t, strings.Contains(pythonInstanceLauncher, `sys.path.append("/pulsar/function-details/")`))
+
+	fnc.Spec.WindowConfig = nil
+	_, ok := MakeFunctionDetailsConfigMap(fnc).Data[PythonWindowFunctionExecutorFile]
+	assert.Assert(t, !ok)
+}
+
+func TestMakeFunctionHPAWithBacklogRule(t *testing.T) {
+	fnc := makeFunctionSample("test")
+	fnc.Spec.Pod.BuiltinAutoscaler = []v1alpha1.BuiltinHPARule{
+		v1alpha1.AverageUtilizationCPUPercent80,
+		v1alpha1.AverageBacklogPerReplica1000,
+	}
+
+	hpa := MakeFunctionHPA(fnc)
+	assert.Equal(t, len(hpa.Spec.Metrics), 2)
+	assert.Equal(t, hpa.Spec.Metrics[0].Type, autov2beta2.ResourceMetricSourceType)
+
+	backlog := hpa.Spec.Metrics[1]
+	assert.Equal(t, backlog.Type, autov2beta2.ExternalMetricSourceType)
+	assert.Equal(t, backlog.External.Metric.Name, BacklogMetricName)
+	assert.DeepEqual(t, backlog.External.Metric.Selector.MatchLabels, MakeFunctionLabels(fnc))
+	assert.Equal(t, backlog.External.Target.Type, autov2beta2.AverageValueMetricType)
+	assert.Equal(t, backlog.External.Target.AverageValue.Value(), int64(1000))
+}
+
+func TestMakeFunctionStatefulSetScaledToZero(t *testing.T) {
+	fnc := makeFunctionSample("test")
+	assert.Equal(t, *MakeFunctionStatefulSet(fnc).Spec.Replicas, int32(1))
+
+	fnc.Status.Conditions = []v1alpha2.Condition{
+		v1alpha2.CreateCondition(v1alpha2.ScaledToZero, metav1.ConditionTrue, v1alpha2.ReasonIdle, "", 1),
+	}
+	assert.Equal(t, *MakeFunctionStatefulSet(fnc).Spec.Replicas, int32(0))
+	assert.Equal(t, *fnc.Spec.Replicas, int32(1))
+}
+
+func TestMakeFunctionStatefulSetSuspended(t *testing.T) {
+	fnc := makeFunctionSample("test")
+	fnc.Spec.Suspend = true
+	assert.Equal(t, *MakeFunctionStatefulSet(fnc).Spec.Replicas, int32(0))
+
+	mesh := &v1alpha2.FunctionMesh{
+		ObjectMeta: *makeSampleObjectMeta("mesh"),
+		Spec: v1alpha1.FunctionMeshSpec{
+			Functions: []v1alpha1.FunctionSpec{makeFunctionSample("test").Spec},
+		},
+	}
+	function := MakeFunctionComponent("mesh-test", mesh, &mesh.Spec.Functions[0])
+	assert.Equal(t, function.Spec.Suspend, false)
+
+	mesh.Spec.Suspend = true
+	function = MakeFunctionComponent("mesh-test", mesh, &mesh.Spec.Functions[0])
+	assert.Equal(t, function.Spec.Suspend, true)
+	assert.Equal(t, mesh.Spec.Functions[0].Suspend, false)
+}
+
+func TestMakeFunctionMonitor(t *testing.T) {
+	Configs = DefaultConfigs()
+	fnc := makeFunctionSample("test")
+	monitor, err := MakeFunctionMonitor(fnc)
+	assert.NilError(t, err)
+	assert.Assert(t, monitor == nil)
+
+	fnc.Spec.Pod.Monitor = &v1alpha1.MonitorPolicy{
+		Interval: "10s",
+		Labels:   map[string]string{"release": "prometheus"},
+		Relabelings: []v1alpha1.RelabelConfig{
+			{SourceLabels: []string{"__meta_kubernetes_pod_name"}, TargetLabel: "instance", Action: "replace"},
+		},
+	}
+	monitor, err = MakeFunctionMonitor(fnc)
+	assert.NilError(t, err)
+	assert.Equal(t, monitor.GetKind(), string(v1alpha1.PodMonitor))
+	assert.Equal(t, monitor.GetName(), MakeFunctionObjectMeta(fnc).Name)
+	assert.Equal(t, monitor.GetLabels()["release"], "prometheus")
+	assert.Equal(t, len(monitor.GetOwnerReferences()), 1)
+
+	endpoints, _, _ := unstructured.NestedSlice(monitor.Object, "spec", "podMetricsEndpoints")
+	assert.Equal(t, len(endpoints), 1)
+	endpoint := endpoints[0].(map[string]interface{})
+	assert.Equal(t, endpoint["port"], MetricsPort.Name)
+	assert.Equal(t, endpoint["interval"], "10s")
+	assert.Equal(t, len(endpoint["relabelings"].([]interface{})), 1)
+	selector, _, _ := unstructured.NestedStringMap(monitor.Object, "spec", "selector", "matchLabels")
+	assert.DeepEqual(t, selector, MakeFunctionLabels(fnc))
+
+	fnc.Spec.Pod.Monitor.Kind = v1alpha1.ServiceMonitor
+	monitor, err = MakeFunctionMonitor(fnc)
+	assert.NilError(t, err)
+	assert.Equal(t, monitor.GetKind(), string(v1alpha1.ServiceMonitor))
+	endpoints, _, _ = unstructured.NestedSlice(monitor.Object, "spec", "endpoints")
+	assert.Equal(t, len(endpoints), 1)
+
+	disabled := false
+	fnc.Spec.Pod.Monitor.Enabled = &disabled
+	monitor, err = MakeFunctionMonitor(fnc)
+	assert.NilError(t, err)
+	assert.Assert(t, monitor == nil)
+
+	// the controller configs enable the monitors for all the components by default
+	Configs.Monitor = MonitorConfigs{Enabled: true, Kind: string(v1alpha1.ServiceMonitor), Interval: "30s"}
+	defer func() { Configs = DefaultConfigs() }()
+	fnc.Spec.Pod.Monitor = nil
+	monitor, err = MakeFunctionMonitor(fnc)
+	assert.NilError(t, err)
+	assert.Equal(t, monitor.GetKind(), string(v1alpha1.ServiceMonitor))
+}
+
+func makeFunctionSample(functionName string) *v1alpha2.Function {
 	maxPending := int32(1000)
 	replicas := int32(1)
 	maxReplicas := int32(5)
 	trueVal := true
-	return &v1alpha1.Function{
+	return &v1alpha2.Function{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Function",
-			APIVersion: "compute.functionmesh.io/v1alpha1",
+			APIVersion: "compute.functionmesh.io/v1alpha2",
 		},
 		ObjectMeta: *makeSampleObjectMeta(functionName),
 		Spec: v1alpha1.FunctionSpec{
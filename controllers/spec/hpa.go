@@ -21,9 +21,14 @@ import (
 	"github.com/streamnative/function-mesh/api/v1alpha1"
 	autov2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// BacklogMetricName is the name of the external metric which reports the subscription backlog
+// of the component's input topics
+const BacklogMetricName = "pulsar_subscription_backlog"
+
 type BuiltinAutoScaler interface {
 	Metrics() []autov2beta2.MetricSpec
 }
@@ -44,6 +49,31 @@ type HPARuleAverageUtilizationResourceMemoryPercent struct {
 	memoryPercentage int32
 }
 
+type HPARuleAverageBacklogPerReplica struct {
+	backlog  int64
+	selector map[string]string
+}
+
+func (H *HPARuleAverageBacklogPerReplica) Metrics() []autov2beta2.MetricSpec {
+	return []autov2beta2.MetricSpec{
+		{
+			Type: autov2beta2.ExternalMetricSourceType,
+			External: &autov2beta2.ExternalMetricSource{
+				Metric: autov2beta2.MetricIdentifier{
+					Name: BacklogMetricName,
+					Selector: &metav1.LabelSelector{
+						MatchLabels: H.selector,
+					},
+				},
+				Target: autov2beta2.MetricTarget{
+					Type:         autov2beta2.AverageValueMetricType,
+					AverageValue: resource.NewQuantity(H.backlog, resource.DecimalSI),
+				},
+			},
+		},
+	}
+}
+
 func (H *HPARuleAverageUtilizationResourceMemoryPercent) Metrics() []autov2beta2.MetricSpec {
 	return []autov2beta2.MetricSpec{
 		{
@@ -86,7 +116,16 @@ func NewHPARuleAverageUtilizationMemoryPercent(memoryPercentage int32) BuiltinAu
 	}
 }
 
-func GetBuiltinAutoScaler(builtinRule v1alpha1.BuiltinHPARule) BuiltinAutoScaler {
+// NewHPARuleAverageBacklogPerReplica scales the component so that each replica has the given backlog on average,
+// the selector identifies the component in the external metrics API
+func NewHPARuleAverageBacklogPerReplica(backlog int64, selector map[string]string) BuiltinAutoScaler {
+	return &HPARuleAverageBacklogPerReplica{
+		backlog:  backlog,
+		selector: selector,
+	}
+}
+
+func GetBuiltinAutoScaler(builtinRule v1alpha1.BuiltinHPARule, selector map[string]string) BuiltinAutoScaler {
 	switch builtinRule {
 	case v1alpha1.AverageUtilizationCPUPercent80:
 		return NewHPARuleAverageUtilizationCPUPercent(80)
@@ -100,6 +139,12 @@ func GetBuiltinAutoScaler(builtinRule v1alpha1.BuiltinHPARule) BuiltinAutoScaler
 		return NewHPARuleAverageUtilizationMemoryPercent(50)
 	case v1alpha1.AverageUtilizationMemoryPercent20:
 		return NewHPARuleAverageUtilizationMemoryPercent(20)
+	case v1alpha1.AverageBacklogPerReplica100:
+		return NewHPARuleAverageBacklogPerReplica(100, selector)
+	case v1alpha1.AverageBacklogPerReplica1000:
+		return NewHPARuleAverageBacklogPerReplica(1000, selector)
+	case v1alpha1.AverageBacklogPerReplica10000:
+		return NewHPARuleAverageBacklogPerReplica(10000, selector)
 	default:
 		return nil
 	}
@@ -126,10 +171,10 @@ func makeDefaultHPA(objectMeta *metav1.ObjectMeta, minReplicas, maxReplicas int3
 	}
 }
 
-func MakeMetricsFromBuiltinHPARules(builtinRules []v1alpha1.BuiltinHPARule) []autov2beta2.MetricSpec {
+func MakeMetricsFromBuiltinHPARules(builtinRules []v1alpha1.BuiltinHPARule, selector map[string]string) []autov2beta2.MetricSpec {
 	metrics := []autov2beta2.MetricSpec{}
 	for _, r := range builtinRules {
-		s := GetBuiltinAutoScaler(r)
+		s := GetBuiltinAutoScaler(r, selector)
 		if s != nil {
 			metrics = append(metrics, s.Metrics()...)
 		}
@@ -137,8 +182,8 @@ func MakeMetricsFromBuiltinHPARules(builtinRules []v1alpha1.BuiltinHPARule) []au
 	return metrics
 }
 
-func makeBuiltinHPA(objectMeta *metav1.ObjectMeta, minReplicas, maxReplicas int32, targetRef autov2beta2.CrossVersionObjectReference, builtinRules []v1alpha1.BuiltinHPARule) *autov2beta2.HorizontalPodAutoscaler {
-	metrics := MakeMetricsFromBuiltinHPARules(builtinRules)
+func makeBuiltinHPA(objectMeta *metav1.ObjectMeta, minReplicas, maxReplicas int32, targetRef autov2beta2.CrossVersionObjectReference, builtinRules []v1alpha1.BuiltinHPARule, selector map[string]string) *autov2beta2.HorizontalPodAutoscaler {
+	metrics := MakeMetricsFromBuiltinHPARules(builtinRules, selector)
 	return &autov2beta2.HorizontalPodAutoscaler{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "autoscaling/v2beta2",
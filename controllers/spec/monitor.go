@@ -0,0 +1,138 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const MetricsPath = "/metrics"
+
+// MonitoringGroupVersion is the API group version of the monitors of the Prometheus Operator,
+// the monitors are handled as unstructured objects to avoid depending on the Prometheus Operator
+var MonitoringGroupVersion = schema.GroupVersion{Group: "monitoring.coreos.com", Version: "v1"}
+
+// monitorEndpoint is the subset of the PodMetricsEndpoint and Endpoint of the Prometheus Operator used by the monitors
+type monitorEndpoint struct {
+	Port        string                   `json:"port"`
+	Path        string                   `json:"path,omitempty"`
+	Interval    string                   `json:"interval,omitempty"`
+	Relabelings []v1alpha1.RelabelConfig `json:"relabelings,omitempty"`
+}
+
+type podMonitorSpec struct {
+	Selector            metav1.LabelSelector `json:"selector"`
+	PodMetricsEndpoints []monitorEndpoint    `json:"podMetricsEndpoints"`
+}
+
+type serviceMonitorSpec struct {
+	Selector  metav1.LabelSelector `json:"selector"`
+	Endpoints []monitorEndpoint    `json:"endpoints"`
+}
+
+func MakeFunctionMonitor(function *v1alpha2.Function) (*unstructured.Unstructured, error) {
+	return makeMonitor(MakeFunctionObjectMeta(function), MakeFunctionLabels(function), function.Spec.Pod.Monitor)
+}
+
+func MakeSourceMonitor(source *v1alpha2.Source) (*unstructured.Unstructured, error) {
+	return makeMonitor(MakeSourceObjectMeta(source), MakeSourceLabels(source), source.Spec.Pod.Monitor)
+}
+
+func MakeSinkMonitor(sink *v1alpha2.Sink) (*unstructured.Unstructured, error) {
+	return makeMonitor(MakeSinkObjectMeta(sink), MakeSinkLabels(sink), sink.Spec.Pod.Monitor)
+}
+
+// IsMonitorEnabled returns true if a monitor should be created for the component
+func IsMonitorEnabled(policy *v1alpha1.MonitorPolicy) bool {
+	if policy == nil {
+		return Configs.Monitor.Enabled
+	}
+	return policy.Enabled == nil || *policy.Enabled
+}
+
+// MakeMonitorObject returns an empty monitor of the given kind, for reading and deleting the monitors
+func MakeMonitorObject(kind v1alpha1.MonitorKind) *unstructured.Unstructured {
+	monitor := &unstructured.Unstructured{}
+	monitor.SetGroupVersionKind(MonitoringGroupVersion.WithKind(string(kind)))
+	return monitor
+}
+
+// makeMonitor returns the monitor scraping the metrics port of the component,
+// or nil if the monitor is not enabled
+func makeMonitor(objectMeta *metav1.ObjectMeta, selector map[string]string,
+	policy *v1alpha1.MonitorPolicy) (*unstructured.Unstructured, error) {
+	if !IsMonitorEnabled(policy) {
+		return nil, nil
+	}
+
+	kind := v1alpha1.MonitorKind(Configs.Monitor.Kind)
+	interval := Configs.Monitor.Interval
+	var labels map[string]string
+	var relabelings []v1alpha1.RelabelConfig
+	if policy != nil {
+		if policy.Kind != "" {
+			kind = policy.Kind
+		}
+		if policy.Interval != "" {
+			interval = policy.Interval
+		}
+		labels = policy.Labels
+		relabelings = policy.Relabelings
+	}
+	if kind == "" {
+		kind = v1alpha1.PodMonitor
+	}
+
+	endpoints := []monitorEndpoint{
+		{
+			Port:        MetricsPort.Name,
+			Path:        MetricsPath,
+			Interval:    interval,
+			Relabelings: relabelings,
+		},
+	}
+	var spec interface{}
+	if kind == v1alpha1.ServiceMonitor {
+		spec = &serviceMonitorSpec{
+			Selector:  metav1.LabelSelector{MatchLabels: selector},
+			Endpoints: endpoints,
+		}
+	} else {
+		spec = &podMonitorSpec{
+			Selector:            metav1.LabelSelector{MatchLabels: selector},
+			PodMetricsEndpoints: endpoints,
+		}
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	monitor := MakeMonitorObject(kind)
+	monitor.SetName(objectMeta.Name)
+	monitor.SetNamespace(objectMeta.Namespace)
+	monitor.SetLabels(mergeLabels(Configs.Monitor.Labels, labels, objectMeta.Labels))
+	monitor.SetOwnerReferences(objectMeta.OwnerReferences)
+	monitor.Object["spec"] = content
+	return monitor, nil
+}
@@ -0,0 +1,177 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The package of a component is downloaded by an init container into a volume shared with the instance,
+// so that a failed download or a package not matching its checksum does not crash loop the instance.
+
+const (
+	PackageDownloadContainerName = "package-download"
+	PackageVolumeName            = "package"
+	PackageDownloadDirectory     = "/pulsar/download/"
+
+	// PackageChecksumMismatchExitCode is the exit code of the init container when the package does not match its checksum
+	PackageChecksumMismatchExitCode = 3
+
+	DefaultPackageDownloadRetries = 3
+	DefaultPackageDownloadBackoff = 5 * time.Second
+)
+
+// packageDownload is the package of a component downloaded by the init container
+type packageDownload struct {
+	location string
+	file     string
+	checksum string
+}
+
+// makePackageDownload returns the package downloaded for the runtime, the runtimes are picked
+// in the same order as the commands of the components. It is nil when nothing is downloaded.
+func makePackageDownload(runtime v1alpha1.Runtime) *packageDownload {
+	var download *packageDownload
+	if runtime.Java != nil {
+		if runtime.Java.Jar != "" {
+			download = &packageDownload{runtime.Java.JarLocation, runtime.Java.Jar, runtime.Java.Checksum}
+		}
+	} else if runtime.Python != nil {
+		if runtime.Python.Py != "" {
+			download = &packageDownload{runtime.Python.PyLocation, runtime.Python.Py, runtime.Python.Checksum}
+		}
+	} else if runtime.Golang != nil {
+		if runtime.Golang.Go != "" {
+			download = &packageDownload{runtime.Golang.GoLocation, runtime.Golang.Go, runtime.Golang.Checksum}
+		}
+	}
+	if download == nil || download.location == "" || v1alpha1.IsFilePackageLocation(download.location) {
+		return nil
+	}
+	return download
+}
+
+// HasPackageDownload returns whether the package of the runtime is downloaded by an init container
+func HasPackageDownload(runtime v1alpha1.Runtime) bool {
+	return makePackageDownload(runtime) != nil
+}
+
+// MakePackageDownloadPath returns the path of the downloaded package in the volume shared with the instance
+func MakePackageDownloadPath(packageFile string) string {
+	return PackageDownloadDirectory + path.Base(packageFile)
+}
+
+// makePackagePath returns the path of the package read by the instance
+func makePackagePath(location, packageFile string) string {
+	switch {
+	case location == "":
+		return packageFile
+	case v1alpha1.IsFilePackageLocation(location):
+		// a package in a file:// location is read in place
+		if filePath := v1alpha1.FilePackagePath(location); filePath != "" {
+			return filePath
+		}
+		return packageFile
+	default:
+		return MakePackageDownloadPath(packageFile)
+	}
+}
+
+// makePackageDownloadContainer returns the init container downloading the package, it runs with the image,
+// the environment and the volumes of the instance container to reach Pulsar in the same way
+func makePackageDownloadContainer(download *packageDownload, container *corev1.Container,
+	authProvided, tlsProvided bool, tlsConfig TLSConfig) *corev1.Container {
+	if download == nil {
+		return nil
+	}
+	return &corev1.Container{
+		Name:                     PackageDownloadContainerName,
+		Image:                    container.Image,
+		Command:                  makePackageDownloadCommand(download, authProvided, tlsProvided, tlsConfig),
+		Env:                      container.Env,
+		EnvFrom:                  container.EnvFrom,
+		Resources:                container.Resources,
+		ImagePullPolicy:          container.ImagePullPolicy,
+		VolumeMounts:             container.VolumeMounts,
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}
+}
+
+// makePackageDownloadCommand retries the download with a growing backoff, then verifies the checksum of the package,
+// the failures are written into the termination message of the init container
+func makePackageDownloadCommand(download *packageDownload, authProvided, tlsProvided bool,
+	tlsConfig TLSConfig) []string {
+	packagePath := MakePackageDownloadPath(download.file)
+	retries := Configs.PackageDownload.Retries
+	if retries < 1 {
+		retries = 1
+	}
+	backoff := int64(Configs.PackageDownload.Backoff / time.Second)
+
+	command := fmt.Sprintf("attempt=1 && until %s; do "+
+		"if [ $attempt -ge %d ]; then echo \"failed to download package %s after %d attempts\" > %s; exit 1; fi; "+
+		"sleep $((attempt * %d)); attempt=$((attempt + 1)); done",
+		strings.Join(getDownloadCommand(download.location, packagePath, authProvided, tlsProvided, tlsConfig), " "),
+		retries, download.location, retries, corev1.TerminationMessagePathDefault, backoff)
+	if download.checksum != "" {
+		checksum := strings.TrimPrefix(download.checksum, v1alpha1.PackageChecksumSHA256)
+		command += fmt.Sprintf(" && if [ \"$(sha256sum %s | cut -d ' ' -f 1)\" != \"%s\" ]; then "+
+			"echo \"package %s does not match checksum %s\" > %s; exit %d; fi",
+			packagePath, checksum, download.location, download.checksum, corev1.TerminationMessagePathDefault,
+			PackageChecksumMismatchExitCode)
+	}
+	return []string{"sh", "-c", command}
+}
+
+// withPackageDownload returns the pod policy with the init container downloading the package
+// before the init containers of the component
+func withPackageDownload(policy v1alpha1.PodPolicy, container *corev1.Container) v1alpha1.PodPolicy {
+	if container == nil {
+		return policy
+	}
+	policy.InitContainers = append([]corev1.Container{*container}, policy.InitContainers...)
+	return policy
+}
+
+// appendPackageVolume adds the volume shared by the init container and the instance
+func appendPackageVolume(volumes []corev1.Volume, runtime v1alpha1.Runtime) []corev1.Volume {
+	if !HasPackageDownload(runtime) {
+		return volumes
+	}
+	return append(volumes, corev1.Volume{
+		Name:         PackageVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+}
+
+// appendPackageVolumeMount mounts the volume shared by the init container and the instance
+func appendPackageVolumeMount(mounts []corev1.VolumeMount, runtime v1alpha1.Runtime) []corev1.VolumeMount {
+	if !HasPackageDownload(runtime) {
+		return mounts
+	}
+	return append(mounts, corev1.VolumeMount{
+		Name:      PackageVolumeName,
+		MountPath: PackageDownloadDirectory,
+	})
+}
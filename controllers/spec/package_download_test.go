@@ -0,0 +1,59 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestMakeFunctionStatefulSetWithPackageDownload(t *testing.T) {
+	fnc := makeFunctionSample("test")
+	fnc.Spec.Java.JarLocation = "function://public/default/test@1.0"
+	fnc.Spec.Java.Checksum = "sha256:" + strings.Repeat("ab", 32)
+
+	statefulSet := MakeFunctionStatefulSet(fnc)
+	podSpec := statefulSet.Spec.Template.Spec
+	assert.Equal(t, len(podSpec.InitContainers), 1)
+	download := podSpec.InitContainers[0]
+	assert.Equal(t, download.Name, PackageDownloadContainerName)
+	assert.Equal(t, download.Image, podSpec.Containers[0].Image)
+	assert.Assert(t, strings.Contains(download.Command[2],
+		"packages download function://public/default/test@1.0 --path /pulsar/download/pulsar-functions-api-examples.jar"))
+	assert.Assert(t, strings.Contains(download.Command[2], "-ge 3"))
+	assert.Assert(t, strings.Contains(download.Command[2], strings.Repeat("ab", 32)))
+
+	// the instance reads the package from the volume shared with the init container
+	command := podSpec.Containers[0].Command[2]
+	assert.Assert(t, !strings.Contains(command, PulsarAdminExecutableFile))
+	assert.Assert(t, strings.Contains(command, "--jar /pulsar/download/pulsar-functions-api-examples.jar"))
+	assert.Equal(t, podSpec.Volumes[len(podSpec.Volumes)-1].Name, PackageVolumeName)
+	mounts := podSpec.Containers[0].VolumeMounts
+	assert.Equal(t, mounts[len(mounts)-1].MountPath, PackageDownloadDirectory)
+
+	// nothing is downloaded for the packages read in place
+	fnc.Spec.Java.JarLocation = ""
+	fnc.Spec.Java.Checksum = ""
+	podSpec = MakeFunctionStatefulSet(fnc).Spec.Template.Spec
+	assert.Equal(t, len(podSpec.InitContainers), 0)
+	for _, volume := range podSpec.Volumes {
+		assert.Assert(t, volume.Name != PackageVolumeName)
+	}
+}
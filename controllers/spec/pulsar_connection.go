@@ -0,0 +1,97 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The settings of a PulsarConnection are copied into the namespace of each component referring to it,
+// as a ConfigMap with the service URLs, a Secret with the authentication params and a Secret with the trust certs,
+// so that the instances read them the same way as the ConfigMap and the Secrets set in the component.
+
+// MakePulsarConnectionObjectName returns the name of the ConfigMap and the auth Secret copied for the component
+func MakePulsarConnectionObjectName(jobName string) string {
+	return jobName + "-pulsar-connection"
+}
+
+// MakePulsarConnectionTLSSecretName returns the name of the Secret with the trust certs copied for the component
+func MakePulsarConnectionTLSSecretName(jobName string) string {
+	return jobName + "-pulsar-connection-tls"
+}
+
+// MakePulsarConnectionMessaging returns the messaging settings of a component referring to the connection,
+// the settings refer to the copies of the connection in the namespace of the component
+func MakePulsarConnectionMessaging(jobName string, ref *v1alpha1.PulsarConnectionRef,
+	connection *v1alpha2.PulsarConnectionSpec) *v1alpha1.PulsarMessaging {
+	messaging := &v1alpha1.PulsarMessaging{
+		PulsarConfig:  MakePulsarConnectionObjectName(jobName),
+		ConnectionRef: ref.DeepCopy(),
+	}
+	if connection.Auth != nil {
+		messaging.AuthSecret = MakePulsarConnectionObjectName(jobName)
+	}
+	if tls := connection.TLS; tls != nil {
+		messaging.TLSConfig = &v1alpha1.PulsarTLSConfig{
+			TLSConfig: v1alpha1.TLSConfig{
+				Enabled:              tls.Enabled,
+				AllowInsecure:        tls.AllowInsecure,
+				HostnameVerification: tls.HostnameVerification,
+			},
+		}
+		if tls.CertSecretRef != nil && tls.CertSecretKey != "" {
+			messaging.TLSConfig.CertSecretName = MakePulsarConnectionTLSSecretName(jobName)
+			messaging.TLSConfig.CertSecretKey = tls.CertSecretKey
+		}
+	}
+	return messaging
+}
+
+// MakePulsarConnectionConfigMap returns the ConfigMap with the service URLs of the connection,
+// the object meta is the one of the statefulset of the component
+func MakePulsarConnectionConfigMap(objectMeta *metav1.ObjectMeta,
+	connection *v1alpha2.PulsarConnectionSpec) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: makePulsarConnectionObjectMeta(objectMeta, MakePulsarConnectionObjectName(objectMeta.Name)),
+		Data: map[string]string{
+			"webServiceURL":    connection.WebServiceURL,
+			"brokerServiceURL": connection.BrokerServiceURL,
+		},
+	}
+}
+
+// MakePulsarConnectionSecret returns a Secret with the data copied from a Secret referred by the connection
+func MakePulsarConnectionSecret(objectMeta *metav1.ObjectMeta, name string, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: makePulsarConnectionObjectMeta(objectMeta, name),
+		Type:       corev1.SecretTypeOpaque,
+		Data:       data,
+	}
+}
+
+func makePulsarConnectionObjectMeta(objectMeta *metav1.ObjectMeta, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:            name,
+		Namespace:       objectMeta.Namespace,
+		Labels:          objectMeta.Labels,
+		OwnerReferences: objectMeta.OwnerReferences,
+	}
+}
@@ -0,0 +1,64 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestMakePulsarConnectionMessaging(t *testing.T) {
+	fnc := makeFunctionSample("test")
+	objectMeta := MakeFunctionObjectMeta(fnc)
+	ref := &v1alpha1.PulsarConnectionRef{Kind: v1alpha1.PulsarConnectionKindCluster, Name: "shared"}
+	connection := &v1alpha2.PulsarConnectionSpec{
+		WebServiceURL:    "http://pulsar-broker:8080",
+		BrokerServiceURL: "pulsar://pulsar-broker:6650",
+	}
+
+	messaging := MakePulsarConnectionMessaging(objectMeta.Name, ref, connection)
+	assert.Equal(t, messaging.PulsarConfig, MakePulsarConnectionObjectName(objectMeta.Name))
+	assert.Equal(t, messaging.AuthSecret, "")
+	assert.Assert(t, messaging.TLSConfig == nil)
+	assert.DeepEqual(t, messaging.ConnectionRef, ref)
+
+	connection.Auth = &v1alpha2.PulsarConnectionAuth{
+		SecretRef: corev1.SecretReference{Name: "pulsar-auth", Namespace: "pulsar"},
+	}
+	connection.TLS = &v1alpha2.PulsarConnectionTLS{
+		Enabled:       true,
+		CertSecretRef: &corev1.SecretReference{Name: "pulsar-ca", Namespace: "pulsar"},
+		CertSecretKey: "ca.crt",
+	}
+	messaging = MakePulsarConnectionMessaging(objectMeta.Name, ref, connection)
+	assert.Equal(t, messaging.AuthSecret, MakePulsarConnectionObjectName(objectMeta.Name))
+	assert.Equal(t, messaging.TLSConfig.Enabled, true)
+	assert.Equal(t, messaging.TLSConfig.CertSecretName, MakePulsarConnectionTLSSecretName(objectMeta.Name))
+	assert.Equal(t, messaging.TLSConfig.CertSecretKey, "ca.crt")
+
+	configMap := MakePulsarConnectionConfigMap(objectMeta, connection)
+	assert.Equal(t, configMap.Name, messaging.PulsarConfig)
+	assert.Equal(t, configMap.Namespace, fnc.Namespace)
+	assert.Equal(t, configMap.Data["webServiceURL"], connection.WebServiceURL)
+	assert.Equal(t, configMap.Data["brokerServiceURL"], connection.BrokerServiceURL)
+	assert.Equal(t, len(configMap.OwnerReferences), 1)
+}
@@ -0,0 +1,231 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The dependencies of a Python package are installed with pip by an init container into a volume shared with
+// the instance, so that the restarts of the instance do not download them again. The instance can still install
+// the requirements bundled in a zip package on start, with the OnStart strategy.
+
+const (
+	PythonDependenciesContainerName = "python-dependencies"
+	PythonDependenciesVolumeName    = "python-dependencies"
+	PythonDependenciesDirectory     = "/pulsar/python-dependencies/"
+	PythonRequirementsVolumeName    = "python-requirements"
+	PythonRequirementsDirectory     = "/pulsar/python-requirements/"
+	PythonRequirementsFile          = "requirements.txt"
+	PythonPackageExtractDirectory   = "/tmp/python-package/"
+	PythonIndexNetrcPath            = "/tmp/python-index.netrc"
+
+	EnvPythonPath          = "PYTHONPATH"
+	EnvPythonIndexUsername = "PYTHON_INDEX_USERNAME"
+	EnvPythonIndexPassword = "PYTHON_INDEX_PASSWORD"
+)
+
+func getPythonDependencies(runtime v1alpha1.Runtime) *v1alpha1.PythonDependencies {
+	if runtime.Java != nil || runtime.Python == nil {
+		return nil
+	}
+	return runtime.Python.Dependencies
+}
+
+// installsPythonDependencies returns whether the dependencies are installed by the init container
+func installsPythonDependencies(dependencies *v1alpha1.PythonDependencies) bool {
+	return dependencies != nil && dependencies.InstallStrategy != v1alpha1.InstallOnStart
+}
+
+// HasPythonDependenciesInstall returns whether the dependencies of the runtime are installed by an init container
+func HasPythonDependenciesInstall(runtime v1alpha1.Runtime) bool {
+	return installsPythonDependencies(getPythonDependencies(runtime))
+}
+
+// makePythonDependenciesContainer returns the init container installing the dependencies, it runs with the image,
+// the environment and the volumes of the instance container, after the package is downloaded
+func makePythonDependenciesContainer(runtime v1alpha1.Runtime, container *corev1.Container) *corev1.Container {
+	dependencies := getPythonDependencies(runtime)
+	if !installsPythonDependencies(dependencies) {
+		return nil
+	}
+	packagePath := makePackagePath(runtime.Python.PyLocation, runtime.Python.Py)
+	return &corev1.Container{
+		Name:                     PythonDependenciesContainerName,
+		Image:                    container.Image,
+		Command:                  []string{"sh", "-c", makePythonDependenciesCommand(dependencies, packagePath)},
+		Env:                      container.Env,
+		EnvFrom:                  container.EnvFrom,
+		Resources:                container.Resources,
+		ImagePullPolicy:          container.ImagePullPolicy,
+		VolumeMounts:             container.VolumeMounts,
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}
+}
+
+// makePythonDependenciesCommand installs the requirements, or the wheel package with its dependencies
+// when there are no requirements
+func makePythonDependenciesCommand(dependencies *v1alpha1.PythonDependencies, packagePath string) string {
+	args := []string{"pip", "install", "--no-cache-dir", "--target", PythonDependenciesDirectory}
+	if dependencies.IndexURL != "" {
+		args = append(args, "--index-url")
+		args = append(args, quoteShellArgs([]string{dependencies.IndexURL})...)
+	}
+	command := ""
+	switch {
+	case len(dependencies.Requirements) > 0:
+		args = append(args, quoteShellArgs(dependencies.Requirements)...)
+	case dependencies.RequirementsConfigMap != nil:
+		args = append(args, "-r", PythonRequirementsDirectory+PythonRequirementsFile)
+	case dependencies.RequirementsFile != "":
+		command = fmt.Sprintf("python -m zipfile -e %s %s && ", packagePath, PythonPackageExtractDirectory)
+		args = append(args, "-r")
+		args = append(args, quoteShellArgs([]string{PythonPackageExtractDirectory + dependencies.RequirementsFile})...)
+	default:
+		args = append(args, packagePath)
+	}
+	return makePythonIndexNetrcCommand(dependencies) + command + strings.Join(args, " ")
+}
+
+// makePythonIndexNetrcCommand writes the credentials of the index into a netrc file read by pip,
+// so that the credentials are not part of the url shown in the logs
+func makePythonIndexNetrcCommand(dependencies *v1alpha1.PythonDependencies) string {
+	if dependencies == nil || dependencies.IndexSecret == "" {
+		return ""
+	}
+	indexURL, err := url.Parse(dependencies.IndexURL)
+	if err != nil {
+		// validated in admission web hook
+		return ""
+	}
+	return fmt.Sprintf("printf 'machine %%s login %%s password %%s\\n' %s \"${%s}\" \"${%s}\" > %s && export NETRC=%s && ",
+		quoteShellArgs([]string{indexURL.Hostname()})[0], EnvPythonIndexUsername, EnvPythonIndexPassword,
+		PythonIndexNetrcPath, PythonIndexNetrcPath)
+}
+
+// makePythonIndexEnv returns the environment variables with the credentials of the index
+func makePythonIndexEnv(dependencies *v1alpha1.PythonDependencies) []corev1.EnvVar {
+	if dependencies == nil || dependencies.IndexSecret == "" {
+		return nil
+	}
+	return []corev1.EnvVar{
+		makeSecretKeyEnv(EnvPythonIndexUsername, dependencies.IndexSecret, v1alpha1.PythonIndexUsernameKey),
+		makeSecretKeyEnv(EnvPythonIndexPassword, dependencies.IndexSecret, v1alpha1.PythonIndexPasswordKey),
+	}
+}
+
+func makeSecretKeyEnv(name, secret, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+// makePythonDependenciesEnv returns the environment of the instance with the credentials of the index,
+// the instance installing the dependencies on start authenticates to the index with them
+func makePythonDependenciesEnv(runtime v1alpha1.Runtime) []corev1.EnvVar {
+	return makePythonIndexEnv(getPythonDependencies(runtime))
+}
+
+// makePythonPathCommand adds the installed dependencies to the PYTHONPATH of the instance,
+// the PYTHONPATH of the image or the pod policy is kept after them
+func makePythonPathCommand(dependencies *v1alpha1.PythonDependencies) string {
+	if !installsPythonDependencies(dependencies) {
+		return ""
+	}
+	return fmt.Sprintf("export %s=%s${%s:+:${%s}} && ", EnvPythonPath, PythonDependenciesDirectory,
+		EnvPythonPath, EnvPythonPath)
+}
+
+// getPythonDependenciesArgs returns the args of the instance about the dependencies of the package
+func getPythonDependenciesArgs(dependencies *v1alpha1.PythonDependencies) []string {
+	if installsPythonDependencies(dependencies) {
+		return []string{"--install_usercode_dependencies", "false"}
+	}
+	args := []string{"--install_usercode_dependencies", "true"}
+	if dependencies != nil && dependencies.IndexURL != "" {
+		args = append(args, "--dependency_repository")
+		args = append(args, quoteShellArgs([]string{dependencies.IndexURL})...)
+	}
+	return args
+}
+
+// withPythonDependencies returns the pod policy with the init container installing the dependencies
+// before the init containers of the component
+func withPythonDependencies(policy v1alpha1.PodPolicy, container *corev1.Container) v1alpha1.PodPolicy {
+	if container == nil {
+		return policy
+	}
+	policy.InitContainers = append([]corev1.Container{*container}, policy.InitContainers...)
+	return policy
+}
+
+// appendPythonDependenciesVolumes adds the volume shared by the init container and the instance,
+// and the volume of the requirements read from a ConfigMap
+func appendPythonDependenciesVolumes(volumes []corev1.Volume, runtime v1alpha1.Runtime) []corev1.Volume {
+	dependencies := getPythonDependencies(runtime)
+	if !installsPythonDependencies(dependencies) {
+		return volumes
+	}
+	volumes = append(volumes, corev1.Volume{
+		Name:         PythonDependenciesVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+	if ref := dependencies.RequirementsConfigMap; ref != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: PythonRequirementsVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: ref.LocalObjectReference,
+					Items:                []corev1.KeyToPath{{Key: ref.Key, Path: PythonRequirementsFile}},
+				},
+			},
+		})
+	}
+	return volumes
+}
+
+// appendPythonDependenciesVolumeMounts mounts the volumes of the dependencies and the requirements
+func appendPythonDependenciesVolumeMounts(mounts []corev1.VolumeMount, runtime v1alpha1.Runtime) []corev1.VolumeMount {
+	dependencies := getPythonDependencies(runtime)
+	if !installsPythonDependencies(dependencies) {
+		return mounts
+	}
+	mounts = append(mounts, corev1.VolumeMount{
+		Name:      PythonDependenciesVolumeName,
+		MountPath: PythonDependenciesDirectory,
+	})
+	if dependencies.RequirementsConfigMap != nil {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      PythonRequirementsVolumeName,
+			MountPath: PythonRequirementsDirectory,
+			ReadOnly:  true,
+		})
+	}
+	return mounts
+}
@@ -0,0 +1,147 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func makePythonFunctionSample(dependencies *v1alpha1.PythonDependencies) *v1alpha2.Function {
+	fnc := makeFunctionSample(TestFunctionName)
+	fnc.Spec.Java = nil
+	fnc.Spec.Python = &v1alpha1.PythonRuntime{
+		Py:           "exclamation.zip",
+		PyLocation:   "function://public/default/exclamation@1.0",
+		Dependencies: dependencies,
+	}
+	return fnc
+}
+
+func TestMakePythonDependenciesContainer(t *testing.T) {
+	fnc := makePythonFunctionSample(&v1alpha1.PythonDependencies{
+		Requirements: []string{"requests==2.28.1"},
+		IndexURL:     "https://pypi.internal/simple",
+		IndexSecret:  "pypi-credentials",
+	})
+	statefulSet := MakeFunctionStatefulSet(fnc)
+	podSpec := statefulSet.Spec.Template.Spec
+
+	// the dependencies are installed once the package is downloaded
+	assert.Equal(t, len(podSpec.InitContainers), 2)
+	assert.Equal(t, podSpec.InitContainers[0].Name, PackageDownloadContainerName)
+	install := podSpec.InitContainers[1]
+	assert.Equal(t, install.Name, PythonDependenciesContainerName)
+	assert.Equal(t, install.Command[2], "printf 'machine %s login %s password %s\\n' 'pypi.internal' "+
+		"\"${PYTHON_INDEX_USERNAME}\" \"${PYTHON_INDEX_PASSWORD}\" > /tmp/python-index.netrc && "+
+		"export NETRC=/tmp/python-index.netrc && "+
+		"pip install --no-cache-dir --target /pulsar/python-dependencies/ "+
+		"--index-url 'https://pypi.internal/simple' 'requests==2.28.1'")
+
+	instance := podSpec.Containers[len(podSpec.Containers)-1]
+	assert.Assert(t, strings.Contains(instance.Command[2], "--install_usercode_dependencies false"))
+	assert.Assert(t, !strings.Contains(instance.Command[2], "NETRC"))
+	// the PYTHONPATH of the image or the pod policy is kept
+	assert.Assert(t, strings.Contains(instance.Command[2],
+		"export PYTHONPATH=/pulsar/python-dependencies/${PYTHONPATH:+:${PYTHONPATH}} && "))
+	assert.Assert(t, !hasEnv(instance.Env, EnvPythonPath, PythonDependenciesDirectory))
+	assert.Assert(t, hasVolumeMount(instance.VolumeMounts, PythonDependenciesVolumeName))
+	assert.Assert(t, hasVolumeMount(install.VolumeMounts, PythonDependenciesVolumeName))
+
+	_, secrets := MakeFunctionReferences(fnc)
+	assert.DeepEqual(t, secrets, []string{"pypi-credentials"})
+}
+
+func TestMakePythonDependenciesFromConfigMap(t *testing.T) {
+	fnc := makePythonFunctionSample(&v1alpha1.PythonDependencies{
+		RequirementsConfigMap: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "requirements"},
+			Key:                  "requirements.txt",
+		},
+	})
+	podSpec := MakeFunctionStatefulSet(fnc).Spec.Template.Spec
+	assert.Assert(t, strings.HasSuffix(podSpec.InitContainers[1].Command[2],
+		"-r /pulsar/python-requirements/requirements.txt"))
+	var found bool
+	for _, volume := range podSpec.Volumes {
+		if volume.Name == PythonRequirementsVolumeName {
+			assert.Equal(t, volume.ConfigMap.Name, "requirements")
+			found = true
+		}
+	}
+	assert.Assert(t, found, "the requirements should be mounted into the pods")
+
+	configMaps, _ := MakeFunctionReferences(fnc)
+	assert.DeepEqual(t, configMaps, []string{"requirements", fnc.Spec.Pulsar.PulsarConfig})
+
+	fnc.Spec.Python.Dependencies = &v1alpha1.PythonDependencies{RequirementsFile: "deps/requirements.txt"}
+	command := makePythonDependenciesContainer(fnc.Spec.Runtime, MakeFunctionContainer(fnc)).Command[2]
+	assert.Equal(t, command, "python -m zipfile -e /pulsar/download/exclamation.zip /tmp/python-package/ && "+
+		"pip install --no-cache-dir --target /pulsar/python-dependencies/ -r '/tmp/python-package/deps/requirements.txt'")
+
+	// the dependencies of a wheel are installed with the wheel
+	fnc.Spec.Python.Py = "exclamation.whl"
+	fnc.Spec.Python.Dependencies = &v1alpha1.PythonDependencies{}
+	command = makePythonDependenciesContainer(fnc.Spec.Runtime, MakeFunctionContainer(fnc)).Command[2]
+	assert.Assert(t, strings.HasSuffix(command, "--target /pulsar/python-dependencies/ /pulsar/download/exclamation.whl"))
+}
+
+func TestMakePythonDependenciesInstalledOnStart(t *testing.T) {
+	fnc := makePythonFunctionSample(&v1alpha1.PythonDependencies{
+		IndexURL:        "https://pypi.internal/simple",
+		IndexSecret:     "pypi-credentials",
+		InstallStrategy: v1alpha1.InstallOnStart,
+	})
+	podSpec := MakeFunctionStatefulSet(fnc).Spec.Template.Spec
+	assert.Equal(t, len(podSpec.InitContainers), 1)
+	instance := podSpec.Containers[len(podSpec.Containers)-1]
+	assert.Assert(t, strings.Contains(instance.Command[2],
+		"--install_usercode_dependencies true --dependency_repository 'https://pypi.internal/simple'"))
+	assert.Assert(t, strings.Contains(instance.Command[2], "export NETRC=/tmp/python-index.netrc"))
+	assert.Assert(t, hasEnv(instance.Env, EnvPythonIndexUsername, ""))
+	assert.Assert(t, !strings.Contains(instance.Command[2], "PYTHONPATH"))
+
+	// the instance keeps installing the requirements of the package without dependencies
+	fnc.Spec.Python.Dependencies = nil
+	commands := makeFunctionCommand(fnc)
+	assert.Assert(t, strings.Contains(commands[2], "--install_usercode_dependencies true"))
+	assert.Assert(t, !strings.Contains(commands[2], "--dependency_repository"))
+}
+
+func hasEnv(envs []corev1.EnvVar, name, value string) bool {
+	for _, env := range envs {
+		if env.Name == name && env.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func hasVolumeMount(mounts []corev1.VolumeMount, name string) bool {
+	for _, mount := range mounts {
+		if mount.Name == name {
+			return true
+		}
+	}
+	return false
+}
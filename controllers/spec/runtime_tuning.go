@@ -0,0 +1,101 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	DefaultMaxBufferedTuples           int32 = 100
+	DefaultExpectedHealthCheckInterval int32 = -1 // turn off the builtin health check to avoid instance exit
+	DefaultJVMHeapPercentage           int32 = 75
+)
+
+// runtimeTuning is the tuning of the instances of a component with the defaults of the controller applied
+type runtimeTuning struct {
+	maxBufferedTuples           int32
+	expectedHealthCheckInterval int32
+	jvmHeapPercentage           int32
+	jvmOptions                  []string
+	pythonInterpreterFlags      []string
+}
+
+func makeRuntimeTuning(tuning *v1alpha1.RuntimeTuning) runtimeTuning {
+	defaults := Configs.RuntimeTuning
+	resolved := runtimeTuning{
+		maxBufferedTuples:           defaults.MaxBufferedTuples,
+		expectedHealthCheckInterval: defaults.ExpectedHealthCheckIntervalSeconds,
+		jvmHeapPercentage:           defaults.JVMHeapPercentage,
+		jvmOptions:                  defaults.JVMOptions,
+		pythonInterpreterFlags:      defaults.PythonInterpreterFlags,
+	}
+	if tuning == nil {
+		return resolved
+	}
+	if tuning.MaxBufferedTuples != nil {
+		resolved.maxBufferedTuples = *tuning.MaxBufferedTuples
+	}
+	if tuning.ExpectedHealthCheckIntervalSeconds != nil {
+		resolved.expectedHealthCheckInterval = *tuning.ExpectedHealthCheckIntervalSeconds
+	}
+	if tuning.JVM != nil {
+		if tuning.JVM.HeapPercentage != nil {
+			resolved.jvmHeapPercentage = *tuning.JVM.HeapPercentage
+		}
+		if tuning.JVM.Options != nil {
+			resolved.jvmOptions = tuning.JVM.Options
+		}
+	}
+	if tuning.Python != nil && tuning.Python.InterpreterFlags != nil {
+		resolved.pythonInterpreterFlags = tuning.Python.InterpreterFlags
+	}
+	return resolved
+}
+
+// makeJavaHeapSize returns the maximum heap of a Java instance as a percentage of the memory limit of the container,
+// the memory request is used when there is no limit, and no heap is set when there is neither
+func makeJavaHeapSize(resources corev1.ResourceRequirements, tuning *v1alpha1.RuntimeTuning) string {
+	memory := resources.Limits.Memory()
+	if memory.IsZero() {
+		memory = resources.Requests.Memory()
+	}
+	percentage := int64(makeRuntimeTuning(tuning).jvmHeapPercentage)
+	if percentage <= 0 || percentage > 100 {
+		percentage = int64(DefaultJVMHeapPercentage)
+	}
+	// the heap is rounded down to mebibytes, the suffixes of the JVM are binary
+	heap := memory.Value() * percentage / 100 / (1 << 20)
+	if heap <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%dm", heap)
+}
+
+// quoteShellArgs quotes the args set by the users, so that they are neither split nor expanded by the shell
+func quoteShellArgs(args []string) []string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		quoted = append(quoted, "'"+strings.ReplaceAll(arg, "'", `'\''`)+"'")
+	}
+	return quoted
+}
@@ -0,0 +1,87 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestMakeJavaHeapSize(t *testing.T) {
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+		Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	// the heap leaves room for the memory out of the heap
+	assert.Equal(t, makeJavaHeapSize(resources, nil), "768m")
+
+	percentage := int32(50)
+	tuning := &v1alpha1.RuntimeTuning{JVM: &v1alpha1.JVMTuning{HeapPercentage: &percentage}}
+	assert.Equal(t, makeJavaHeapSize(resources, tuning), "512m")
+
+	resources.Limits = nil
+	assert.Equal(t, makeJavaHeapSize(resources, nil), "384m")
+
+	resources.Requests = nil
+	assert.Equal(t, makeJavaHeapSize(resources, nil), "")
+}
+
+func TestMakeFunctionCommandWithRuntimeTuning(t *testing.T) {
+	fnc := makeFunctionSample(TestFunctionName)
+	commands := makeFunctionCommand(fnc)
+	assert.Assert(t, strings.Contains(commands[2], "--max_buffered_tuples 100"))
+	assert.Assert(t, strings.Contains(commands[2], "--expected_healthcheck_interval -1"))
+
+	Configs.RuntimeTuning.JVMOptions = []string{"-XX:+UseG1GC"}
+	defer func() { Configs = DefaultConfigs() }()
+	commands = makeFunctionCommand(fnc)
+	assert.Assert(t, strings.Contains(commands[2], "'-XX:+UseG1GC' org.apache.pulsar.functions.instance.JavaInstanceMain"))
+
+	maxBufferedTuples := int32(1024)
+	interval := int32(10)
+	fnc.Spec.Tuning = &v1alpha1.RuntimeTuning{
+		MaxBufferedTuples:                  &maxBufferedTuples,
+		ExpectedHealthCheckIntervalSeconds: &interval,
+		JVM: &v1alpha1.JVMTuning{
+			Options: []string{"-XX:+UseZGC", "-Dgreeting=it's $HOME"},
+		},
+	}
+	commands = makeFunctionCommand(fnc)
+	assert.Assert(t, strings.Contains(commands[2], "--max_buffered_tuples 1024"))
+	assert.Assert(t, strings.Contains(commands[2], "--expected_healthcheck_interval 10"))
+	// the options of the component replace the default options
+	assert.Assert(t, !strings.Contains(commands[2], "-XX:+UseG1GC"))
+	assert.Assert(t, strings.Contains(commands[2], `'-XX:+UseZGC' '-Dgreeting=it'\''s $HOME'`))
+}
+
+func TestMakeGoFunctionConfWithRuntimeTuning(t *testing.T) {
+	fnc := makeGoFunctionSample(TestFunctionName)
+	conf := convertGoFunctionConfs(fnc)
+	assert.Equal(t, conf.MaxBufTuples, 100)
+	assert.Equal(t, conf.ExpectedHealthCheckInterval, int32(-1))
+
+	maxBufferedTuples := int32(10)
+	fnc.Spec.Tuning = &v1alpha1.RuntimeTuning{MaxBufferedTuples: &maxBufferedTuples}
+	conf = convertGoFunctionConfs(fnc)
+	assert.Equal(t, conf.MaxBufTuples, 10)
+}
@@ -20,13 +20,14 @@ package spec
 import (
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
 	appsv1 "k8s.io/api/apps/v1"
 	autov2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func MakeSinkHPA(sink *v1alpha1.Sink) *autov2beta2.HorizontalPodAutoscaler {
+func MakeSinkHPA(sink *v1alpha2.Sink) *autov2beta2.HorizontalPodAutoscaler {
 	objectMeta := MakeSinkObjectMeta(sink)
 	targetRef := autov2beta2.CrossVersionObjectReference{
 		Kind:       sink.Kind,
@@ -35,31 +36,48 @@ func MakeSinkHPA(sink *v1alpha1.Sink) *autov2beta2.HorizontalPodAutoscaler {
 	}
 	if isBuiltinHPAEnabled(sink.Spec.Replicas, sink.Spec.MaxReplicas, sink.Spec.Pod) {
 		return makeBuiltinHPA(objectMeta, *sink.Spec.Replicas, *sink.Spec.MaxReplicas, targetRef,
-			sink.Spec.Pod.BuiltinAutoscaler)
+			sink.Spec.Pod.BuiltinAutoscaler, MakeSinkLabels(sink))
 	} else if !isDefaultHPAEnabled(sink.Spec.Replicas, sink.Spec.MaxReplicas, sink.Spec.Pod) {
 		return makeHPA(objectMeta, *sink.Spec.Replicas, *sink.Spec.MaxReplicas, sink.Spec.Pod, targetRef)
 	}
 	return makeDefaultHPA(objectMeta, *sink.Spec.Replicas, *sink.Spec.MaxReplicas, targetRef)
 }
 
-func MakeSinkService(sink *v1alpha1.Sink) *corev1.Service {
+func MakeSinkService(sink *v1alpha2.Sink) *corev1.Service {
 	labels := MakeSinkLabels(sink)
 	objectMeta := MakeSinkObjectMeta(sink)
 	return MakeService(objectMeta, labels)
 }
 
-func MakeSinkStatefulSet(sink *v1alpha1.Sink) *appsv1.StatefulSet {
+func MakeSinkStatefulSet(sink *v1alpha2.Sink) *appsv1.StatefulSet {
 	objectMeta := MakeSinkObjectMeta(sink)
-	return MakeStatefulSet(objectMeta, sink.Spec.Replicas, MakeSinkContainer(sink),
-		makeSinkVolumes(sink), MakeSinkLabels(sink), sink.Spec.Pod)
+	container := MakeSinkContainer(sink)
+	downloadContainer := makePackageDownloadContainer(makePackageDownload(sink.Spec.Runtime), container,
+		sink.Spec.Pulsar.AuthSecret != "", sink.Spec.Pulsar.TLSSecret != "", sink.Spec.Pulsar.TLSConfig)
+	policy := withDetailsHash(withPackageDownload(sink.Spec.Pod, downloadContainer), MakeSinkDetailsConfigMap(sink))
+	return MakeStatefulSet(objectMeta, makeDesiredReplicas(sink.Spec.Replicas, sink.Spec.Suspend, sink.Status.Conditions),
+		container, makeSinkVolumes(sink), MakeSinkLabels(sink), policy)
 }
 
-func MakeSinkServiceName(sink *v1alpha1.Sink) string {
+// MakeSinkDetailsConfigMap returns the ConfigMap with the details read by the instances of the sink
+func MakeSinkDetailsConfigMap(sink *v1alpha2.Sink) *corev1.ConfigMap {
+	return makeDetailsConfigMap(MakeSinkObjectMeta(sink), map[string]string{
+		FunctionDetailsFile: makeFunctionDetailsFile(generateSinkDetailsInJSON(sink)),
+	})
+}
+
+func MakeSinkServiceName(sink *v1alpha2.Sink) string {
 	objectMeta := MakeSinkObjectMeta(sink)
 	return MakeHeadlessServiceName(objectMeta.Name)
 }
 
-func MakeSinkObjectMeta(sink *v1alpha1.Sink) *metav1.ObjectMeta {
+// MakeSinkReferences returns the names of the ConfigMaps and the Secrets read by the pods of the sink
+func MakeSinkReferences(sink *v1alpha2.Sink) (configMaps []string, secrets []string) {
+	return makeReferences(MakeSinkObjectMeta(sink).Name, sink.Spec.Pulsar, sink.Spec.SecretsMap,
+		sink.Spec.Runtime)
+}
+
+func MakeSinkObjectMeta(sink *v1alpha2.Sink) *metav1.ObjectMeta {
 	return &metav1.ObjectMeta{
 		Name:      makeJobName(sink.Name, v1alpha1.SinkComponent),
 		Namespace: sink.Namespace,
@@ -70,7 +88,7 @@ func MakeSinkObjectMeta(sink *v1alpha1.Sink) *metav1.ObjectMeta {
 	}
 }
 
-func MakeSinkContainer(sink *v1alpha1.Sink) *corev1.Container {
+func MakeSinkContainer(sink *v1alpha2.Sink) *corev1.Container {
 	imagePullPolicy := sink.Spec.ImagePullPolicy
 	if imagePullPolicy == "" {
 		imagePullPolicy = corev1.PullIfNotPresent
@@ -90,7 +108,7 @@ func MakeSinkContainer(sink *v1alpha1.Sink) *corev1.Container {
 	}
 }
 
-func MakeSinkLabels(sink *v1alpha1.Sink) map[string]string {
+func MakeSinkLabels(sink *v1alpha2.Sink) map[string]string {
 	labels := make(map[string]string)
 	labels["component"] = ComponentSink
 	labels["name"] = sink.Name
@@ -99,36 +117,39 @@ func MakeSinkLabels(sink *v1alpha1.Sink) map[string]string {
 	return labels
 }
 
-func makeSinkVolumes(sink *v1alpha1.Sink) []corev1.Volume {
-	return generatePodVolumes(
+func makeSinkVolumes(sink *v1alpha2.Sink) []corev1.Volume {
+	volumes := generatePodVolumes(
 		sink.Spec.Pod.Volumes,
 		nil,
 		sink.Spec.Input.SourceSpecs,
 		sink.Spec.Pulsar.TLSConfig,
 		getRuntimeLogConfigNames(sink.Spec.Java, sink.Spec.Python, sink.Spec.Golang))
+	volumes = appendDetailsVolume(volumes, MakeSinkObjectMeta(sink).Name)
+	return appendPackageVolume(volumes, sink.Spec.Runtime)
 }
 
-func makeSinkVolumeMounts(sink *v1alpha1.Sink) []corev1.VolumeMount {
-	return generateContainerVolumeMounts(
+func makeSinkVolumeMounts(sink *v1alpha2.Sink) []corev1.VolumeMount {
+	mounts := generateContainerVolumeMounts(
 		sink.Spec.VolumeMounts,
 		nil,
 		sink.Spec.Input.SourceSpecs,
 		sink.Spec.Pulsar.TLSConfig,
 		getRuntimeLogConfigNames(sink.Spec.Java, sink.Spec.Python, sink.Spec.Golang))
+	return appendPackageVolumeMount(appendDetailsVolumeMount(mounts), sink.Spec.Runtime)
 }
 
-func MakeSinkCommand(sink *v1alpha1.Sink) []string {
+func MakeSinkCommand(sink *v1alpha2.Sink) []string {
 	spec := sink.Spec
 	return MakeJavaFunctionCommand(spec.Java.JarLocation, spec.Java.Jar,
 		spec.Name, spec.ClusterName,
 		generateJavaLogConfigCommand(sink.Spec.Java),
 		parseJavaLogLevel(sink.Spec.Java),
-		generateSinkDetailsInJSON(sink),
-		getDecimalSIMemory(spec.Resources.Requests.Memory()), spec.Java.ExtraDependenciesDir, string(sink.UID),
-		spec.Pulsar.AuthSecret != "", spec.Pulsar.TLSSecret != "", spec.SecretsMap, nil, spec.Pulsar.TLSConfig)
+		makeFunctionDetailsArg(),
+		makeJavaHeapSize(spec.Resources, spec.Tuning), spec.Java.ExtraDependenciesDir, string(sink.UID),
+		spec.Pulsar.AuthSecret != "", spec.Pulsar.TLSSecret != "", spec.SecretsMap, nil, spec.Pulsar.TLSConfig, spec.Tuning)
 }
 
-func generateSinkDetailsInJSON(sink *v1alpha1.Sink) string {
+func generateSinkDetailsInJSON(sink *v1alpha2.Sink) string {
 	sourceDetails := convertSinkDetails(sink)
 	marshaler := &jsonpb.Marshaler{}
 	json, error := marshaler.MarshalToString(sourceDetails)
@@ -20,13 +20,14 @@ package spec
 import (
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
 	appsv1 "k8s.io/api/apps/v1"
 	autov2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func MakeSourceHPA(source *v1alpha1.Source) *autov2beta2.HorizontalPodAutoscaler {
+func MakeSourceHPA(source *v1alpha2.Source) *autov2beta2.HorizontalPodAutoscaler {
 	objectMeta := MakeSourceObjectMeta(source)
 	targetRef := autov2beta2.CrossVersionObjectReference{
 		Kind:       source.Kind,
@@ -35,37 +36,54 @@ func MakeSourceHPA(source *v1alpha1.Source) *autov2beta2.HorizontalPodAutoscaler
 	}
 	if isBuiltinHPAEnabled(source.Spec.Replicas, source.Spec.MaxReplicas, source.Spec.Pod) {
 		return makeBuiltinHPA(objectMeta, *source.Spec.Replicas, *source.Spec.MaxReplicas, targetRef,
-			source.Spec.Pod.BuiltinAutoscaler)
+			source.Spec.Pod.BuiltinAutoscaler, MakeSourceLabels(source))
 	} else if !isDefaultHPAEnabled(source.Spec.Replicas, source.Spec.MaxReplicas, source.Spec.Pod) {
 		return makeHPA(objectMeta, *source.Spec.Replicas, *source.Spec.MaxReplicas, source.Spec.Pod, targetRef)
 	}
 	return makeDefaultHPA(objectMeta, *source.Spec.Replicas, *source.Spec.MaxReplicas, targetRef)
 }
 
-func MakeSourceService(source *v1alpha1.Source) *corev1.Service {
-	labels := makeSourceLabels(source)
+func MakeSourceService(source *v1alpha2.Source) *corev1.Service {
+	labels := MakeSourceLabels(source)
 	objectMeta := MakeSourceObjectMeta(source)
 	return MakeService(objectMeta, labels)
 }
 
-func MakeSourceStatefulSet(source *v1alpha1.Source) *appsv1.StatefulSet {
+func MakeSourceStatefulSet(source *v1alpha2.Source) *appsv1.StatefulSet {
 	objectMeta := MakeSourceObjectMeta(source)
-	return MakeStatefulSet(objectMeta, source.Spec.Replicas, MakeSourceContainer(source),
-		makeSourceVolumes(source), makeSourceLabels(source), source.Spec.Pod)
+	container := MakeSourceContainer(source)
+	downloadContainer := makePackageDownloadContainer(makePackageDownload(source.Spec.Runtime), container,
+		source.Spec.Pulsar.AuthSecret != "", source.Spec.Pulsar.TLSSecret != "", source.Spec.Pulsar.TLSConfig)
+	policy := withDetailsHash(withPackageDownload(source.Spec.Pod, downloadContainer), MakeSourceDetailsConfigMap(source))
+	return MakeStatefulSet(objectMeta, makeDesiredReplicas(source.Spec.Replicas, source.Spec.Suspend, source.Status.Conditions),
+		container, makeSourceVolumes(source), MakeSourceLabels(source), policy)
 }
 
-func MakeSourceObjectMeta(source *v1alpha1.Source) *metav1.ObjectMeta {
+// MakeSourceDetailsConfigMap returns the ConfigMap with the details read by the instances of the source
+func MakeSourceDetailsConfigMap(source *v1alpha2.Source) *corev1.ConfigMap {
+	return makeDetailsConfigMap(MakeSourceObjectMeta(source), map[string]string{
+		FunctionDetailsFile: makeFunctionDetailsFile(generateSourceDetailsInJSON(source)),
+	})
+}
+
+// MakeSourceReferences returns the names of the ConfigMaps and the Secrets read by the pods of the source
+func MakeSourceReferences(source *v1alpha2.Source) (configMaps []string, secrets []string) {
+	return makeReferences(MakeSourceObjectMeta(source).Name, source.Spec.Pulsar, source.Spec.SecretsMap,
+		source.Spec.Runtime)
+}
+
+func MakeSourceObjectMeta(source *v1alpha2.Source) *metav1.ObjectMeta {
 	return &metav1.ObjectMeta{
 		Name:      makeJobName(source.Name, v1alpha1.SourceComponent),
 		Namespace: source.Namespace,
-		Labels:    makeSourceLabels(source),
+		Labels:    MakeSourceLabels(source),
 		OwnerReferences: []metav1.OwnerReference{
 			*metav1.NewControllerRef(source, source.GroupVersionKind()),
 		},
 	}
 }
 
-func MakeSourceContainer(source *v1alpha1.Source) *corev1.Container {
+func MakeSourceContainer(source *v1alpha2.Source) *corev1.Container {
 	imagePullPolicy := source.Spec.ImagePullPolicy
 	if imagePullPolicy == "" {
 		imagePullPolicy = corev1.PullIfNotPresent
@@ -85,7 +103,7 @@ func MakeSourceContainer(source *v1alpha1.Source) *corev1.Container {
 	}
 }
 
-func makeSourceLabels(source *v1alpha1.Source) map[string]string {
+func MakeSourceLabels(source *v1alpha2.Source) map[string]string {
 	labels := make(map[string]string)
 	labels["component"] = ComponentSource
 	labels["name"] = source.Name
@@ -94,36 +112,39 @@ func makeSourceLabels(source *v1alpha1.Source) map[string]string {
 	return labels
 }
 
-func makeSourceVolumes(source *v1alpha1.Source) []corev1.Volume {
-	return generatePodVolumes(
+func makeSourceVolumes(source *v1alpha2.Source) []corev1.Volume {
+	volumes := generatePodVolumes(
 		source.Spec.Pod.Volumes,
 		source.Spec.Output.ProducerConf,
 		nil,
 		source.Spec.Pulsar.TLSConfig,
 		getRuntimeLogConfigNames(source.Spec.Java, source.Spec.Python, source.Spec.Golang))
+	volumes = appendDetailsVolume(volumes, MakeSourceObjectMeta(source).Name)
+	return appendPackageVolume(volumes, source.Spec.Runtime)
 }
 
-func makeSourceVolumeMounts(source *v1alpha1.Source) []corev1.VolumeMount {
-	return generateContainerVolumeMounts(
+func makeSourceVolumeMounts(source *v1alpha2.Source) []corev1.VolumeMount {
+	mounts := generateContainerVolumeMounts(
 		source.Spec.VolumeMounts,
 		source.Spec.Output.ProducerConf,
 		nil,
 		source.Spec.Pulsar.TLSConfig,
 		getRuntimeLogConfigNames(source.Spec.Java, source.Spec.Python, source.Spec.Golang))
+	return appendPackageVolumeMount(appendDetailsVolumeMount(mounts), source.Spec.Runtime)
 }
 
-func makeSourceCommand(source *v1alpha1.Source) []string {
+func makeSourceCommand(source *v1alpha2.Source) []string {
 	spec := source.Spec
 	return MakeJavaFunctionCommand(spec.Java.JarLocation, spec.Java.Jar,
 		spec.Name, spec.ClusterName,
 		generateJavaLogConfigCommand(source.Spec.Java),
 		parseJavaLogLevel(source.Spec.Java),
-		generateSourceDetailsInJSON(source),
-		getDecimalSIMemory(spec.Resources.Requests.Memory()), spec.Java.ExtraDependenciesDir, string(source.UID),
-		spec.Pulsar.AuthSecret != "", spec.Pulsar.TLSSecret != "", spec.SecretsMap, nil, spec.Pulsar.TLSConfig)
+		makeFunctionDetailsArg(),
+		makeJavaHeapSize(spec.Resources, spec.Tuning), spec.Java.ExtraDependenciesDir, string(source.UID),
+		spec.Pulsar.AuthSecret != "", spec.Pulsar.TLSSecret != "", spec.SecretsMap, nil, spec.Pulsar.TLSConfig, spec.Tuning)
 }
 
-func generateSourceDetailsInJSON(source *v1alpha1.Source) string {
+func generateSourceDetailsInJSON(source *v1alpha2.Source) string {
 	sourceDetails := convertSourceDetails(source)
 	marshaler := &jsonpb.Marshaler{}
 	json, error := marshaler.MarshalToString(sourceDetails)
@@ -0,0 +1,179 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MakeMeshTopology returns the topology of the mesh, the nodes are annotated with the readiness
+// of the components observed in the status of the mesh
+func MakeMeshTopology(mesh *v1alpha2.FunctionMesh) *v1alpha2.TopologyStatus {
+	graph := v1alpha1.NewTopicGraph(&mesh.Spec)
+	topology := &v1alpha2.TopologyStatus{}
+	for _, node := range graph.Nodes {
+		inputs := append([]string{}, node.Inputs...)
+		for _, pattern := range node.InputPatterns {
+			inputs = append(inputs, pattern.String())
+		}
+		var successors []string
+		for _, successor := range graph.Successors(node) {
+			successors = append(successors, successor.ID)
+		}
+		topology.Nodes = append(topology.Nodes, v1alpha2.TopologyNode{
+			ID:         node.ID,
+			Kind:       node.Kind,
+			Name:       node.Name,
+			Ready:      MeshComponentReadiness(mesh, node.Kind, node.Name),
+			Inputs:     inputs,
+			Output:     node.Output,
+			Successors: successors,
+		})
+	}
+	topology.DOT = renderTopologyDOT(mesh.Name, topology.Nodes, graph.ExternalInputs())
+	topology.Mermaid = renderTopologyMermaid(topology.Nodes, graph.ExternalInputs())
+	return topology
+}
+
+func MeshComponentReadiness(mesh *v1alpha2.FunctionMesh, kind, name string) metav1.ConditionStatus {
+	var condition v1alpha2.Condition
+	var ok bool
+	var readyType string
+	switch kind {
+	case v1alpha1.FunctionComponent:
+		condition, ok = mesh.Status.FunctionConditions[name]
+		readyType = v1alpha2.FunctionReady
+	case v1alpha1.SourceComponent:
+		condition, ok = mesh.Status.SourceConditions[name]
+		readyType = v1alpha2.SourceReady
+	case v1alpha1.SinkComponent:
+		condition, ok = mesh.Status.SinkConditions[name]
+		readyType = v1alpha2.SinkReady
+	}
+	if !ok || condition.Type != readyType {
+		return metav1.ConditionUnknown
+	}
+	return condition.Status
+}
+
+// topologyTopics returns the topics drawn as their own nodes, the inputs produced outside of the mesh
+// and the outputs not consumed in the mesh
+func topologyTopics(nodes []v1alpha2.TopologyNode, externalInputs []string) []string {
+	topics := append([]string{}, externalInputs...)
+	for _, node := range nodes {
+		if node.Output != "" && len(node.Successors) == 0 {
+			topics = append(topics, node.Output)
+		}
+	}
+	return topics
+}
+
+func renderTopologyDOT(name string, nodes []v1alpha2.TopologyNode, externalInputs []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", name)
+	b.WriteString("  rankdir=LR;\n")
+	for _, node := range nodes {
+		color := "gray"
+		switch node.Ready {
+		case metav1.ConditionTrue:
+			color = "green"
+		case metav1.ConditionFalse:
+			color = "red"
+		}
+		fmt.Fprintf(&b, "  %q [shape=box, color=%s, label=%q];\n", node.ID, color,
+			fmt.Sprintf("%s\nready: %s", node.ID, node.Ready))
+	}
+	for _, topic := range topologyTopics(nodes, externalInputs) {
+		fmt.Fprintf(&b, "  %q [shape=ellipse];\n", topic)
+	}
+	external := toSet(externalInputs)
+	for _, node := range nodes {
+		for _, input := range node.Inputs {
+			if external[input] {
+				fmt.Fprintf(&b, "  %q -> %q;\n", input, node.ID)
+			}
+		}
+		for _, successor := range node.Successors {
+			fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", node.ID, successor, node.Output)
+		}
+		if node.Output != "" && len(node.Successors) == 0 {
+			fmt.Fprintf(&b, "  %q -> %q;\n", node.ID, node.Output)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func renderTopologyMermaid(nodes []v1alpha2.TopologyNode, externalInputs []string) string {
+	// the Mermaid IDs are generated, the names of the components and the topics are only used in the labels
+	ids := map[string]string{}
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, node := range nodes {
+		id := fmt.Sprintf("c%d", i)
+		ids[node.ID] = id
+		class := "unknown"
+		switch node.Ready {
+		case metav1.ConditionTrue:
+			class = "ready"
+		case metav1.ConditionFalse:
+			class = "notready"
+		}
+		fmt.Fprintf(&b, "  %s[\"%s<br/>ready: %s\"]:::%s\n", id, escapeMermaid(node.ID), node.Ready, class)
+	}
+	for i, topic := range topologyTopics(nodes, externalInputs) {
+		id := fmt.Sprintf("t%d", i)
+		ids[topic] = id
+		fmt.Fprintf(&b, "  %s([\"%s\"])\n", id, escapeMermaid(topic))
+	}
+	external := toSet(externalInputs)
+	for _, node := range nodes {
+		for _, input := range node.Inputs {
+			if external[input] {
+				fmt.Fprintf(&b, "  %s --> %s\n", ids[input], ids[node.ID])
+			}
+		}
+		for _, successor := range node.Successors {
+			fmt.Fprintf(&b, "  %s -->|\"%s\"| %s\n", ids[node.ID], escapeMermaid(node.Output), ids[successor])
+		}
+		if node.Output != "" && len(node.Successors) == 0 {
+			fmt.Fprintf(&b, "  %s --> %s\n", ids[node.ID], ids[node.Output])
+		}
+	}
+	b.WriteString("  classDef ready stroke:green\n")
+	b.WriteString("  classDef notready stroke:red\n")
+	b.WriteString("  classDef unknown stroke:gray\n")
+	return b.String()
+}
+
+func escapeMermaid(text string) string {
+	return strings.ReplaceAll(text, "\"", "#quot;")
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
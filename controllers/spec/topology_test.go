@@ -0,0 +1,86 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMakeMeshTopology(t *testing.T) {
+	mesh := &v1alpha2.FunctionMesh{
+		ObjectMeta: metav1.ObjectMeta{Name: "mesh", Namespace: "default"},
+		Spec: v1alpha1.FunctionMeshSpec{
+			Sources: []v1alpha1.SourceSpec{
+				{Name: "src", Output: v1alpha1.OutputConf{Topic: "a"}},
+			},
+			Functions: []v1alpha1.FunctionSpec{
+				{
+					Name:   "fn",
+					Input:  v1alpha1.InputConf{Topics: []string{"a", "ext"}},
+					Output: v1alpha1.OutputConf{Topic: "b"},
+				},
+			},
+			Sinks: []v1alpha1.SinkSpec{
+				{Name: "sink", Input: v1alpha1.InputConf{Topics: []string{"b"}}},
+			},
+		},
+		Status: v1alpha2.FunctionMeshStatus{
+			FunctionConditions: map[string]v1alpha2.Condition{
+				"fn": v1alpha2.CreateCondition(v1alpha2.FunctionReady, metav1.ConditionTrue,
+					v1alpha2.ReasonReconciled, "", 1),
+			},
+			SinkConditions: map[string]v1alpha2.Condition{
+				"sink": v1alpha2.CreateCondition(v1alpha2.SinkReady, metav1.ConditionFalse,
+					v1alpha2.ReasonWaiting, "sink is not ready", 1),
+			},
+		},
+	}
+
+	topology := MakeMeshTopology(mesh)
+	assert.Equal(t, len(topology.Nodes), 3)
+
+	source := topology.Nodes[0]
+	assert.Equal(t, source.ID, "source/src")
+	assert.Equal(t, source.Ready, metav1.ConditionUnknown)
+	assert.Equal(t, source.Output, "persistent://public/default/a")
+	assert.DeepEqual(t, source.Successors, []string{"function/fn"})
+
+	function := topology.Nodes[1]
+	assert.Equal(t, function.Ready, metav1.ConditionTrue)
+	assert.DeepEqual(t, function.Inputs,
+		[]string{"persistent://public/default/a", "persistent://public/default/ext"})
+	assert.DeepEqual(t, function.Successors, []string{"sink/sink"})
+
+	sink := topology.Nodes[2]
+	assert.Equal(t, sink.Ready, metav1.ConditionFalse)
+	assert.Assert(t, len(sink.Successors) == 0)
+
+	assert.Assert(t, strings.Contains(topology.DOT,
+		`"source/src" -> "function/fn" [label="persistent://public/default/a"];`))
+	assert.Assert(t, strings.Contains(topology.DOT,
+		`"persistent://public/default/ext" -> "function/fn";`))
+	assert.Assert(t, strings.Contains(topology.Mermaid, `c0 -->|"persistent://public/default/a"| c1`))
+	assert.Assert(t, strings.Contains(topology.Mermaid, `t0 --> c1`))
+	assert.Assert(t, strings.Contains(topology.Mermaid, `c1["function/fn<br/>ready: True"]:::ready`))
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -29,10 +30,11 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
 	"github.com/streamnative/function-mesh/controllers/proto"
 )
 
-func convertFunctionDetails(function *v1alpha1.Function) *proto.FunctionDetails {
+func convertFunctionDetails(function *v1alpha2.Function) *proto.FunctionDetails {
 	fd := &proto.FunctionDetails{
 		Tenant:               function.Spec.Tenant,
 		Namespace:            function.Spec.Namespace,
@@ -40,7 +42,7 @@ func convertFunctionDetails(function *v1alpha1.Function) *proto.FunctionDetails
 		ClassName:            function.Spec.ClassName,
 		LogTopic:             function.Spec.LogTopic,
 		ProcessingGuarantees: convertProcessingGuarantee(function.Spec.ProcessingGuarantee),
-		UserConfig:           getUserConfig(function.Spec.FuncConfig),
+		UserConfig:           getFunctionUserConfig(function),
 		Runtime:              proto.FunctionDetails_JAVA,
 		AutoAck:              getBoolFromPtrOrDefault(function.Spec.AutoAck, true),
 		Parallelism:          getInt32FromPtrOrDefault(function.Spec.Replicas, 1),
@@ -61,34 +63,71 @@ func convertFunctionDetails(function *v1alpha1.Function) *proto.FunctionDetails
 		fd.SecretsMap = marshalSecretsMap(function.Spec.SecretsMap)
 	}
 
+	if function.Spec.WindowConfig != nil {
+		// the window function executor wraps the user function and is responsible for acking messages
+		fd.ClassName = WindowFunctionExecutorClass
+		if function.Spec.Python != nil {
+			fd.ClassName = PythonWindowFunctionExecutorClass
+		}
+		fd.AutoAck = false
+	}
+
 	return fd
 }
 
-func convertGoFunctionConfs(function *v1alpha1.Function) *GoFunctionConf {
-	return &GoFunctionConf{
-		FuncID:               fmt.Sprintf("${%s}-%s", EnvShardID, string(function.UID)),
-		PulsarServiceURL:     "${brokerServiceURL}",
-		FuncVersion:          "0",
-		MaxBufTuples:         100, //TODO
-		Port:                 int(GRPCPort.ContainerPort),
-		ClusterName:          function.Spec.ClusterName,
-		Tenant:               function.Spec.Tenant,
-		NameSpace:            function.Spec.Namespace,
-		Name:                 function.Spec.Name,
-		LogTopic:             function.Spec.LogTopic,
-		ProcessingGuarantees: int32(convertProcessingGuarantee(function.Spec.ProcessingGuarantee)),
-		//SecretsMap:                  marshalSecretsMap(function.Spec.SecretsMap),
+// windowConfig is the window config read by the window function executor,
+// refer to https://github.com/apache/pulsar/blob/master/pulsar-client-admin-api/src/main/java/org/apache/pulsar/common/functions/WindowConfig.java
+type windowConfig struct {
+	v1alpha1.WindowConfig         `json:",inline"`
+	ActualWindowFunctionClassName string `json:"actualWindowFunctionClassName"`
+}
+
+func getFunctionUserConfig(function *v1alpha2.Function) string {
+	if function.Spec.WindowConfig == nil {
+		return getUserConfig(function.Spec.FuncConfig)
+	}
+	configs := make(map[string]interface{})
+	if function.Spec.FuncConfig != nil {
+		for k, v := range function.Spec.FuncConfig.Data {
+			configs[k] = v
+		}
+	}
+	configs[WindowConfigKey] = &windowConfig{
+		WindowConfig:                  *function.Spec.WindowConfig,
+		ActualWindowFunctionClassName: function.Spec.ClassName,
+	}
+	// validated in admission web hook
+	bytes, _ := json.Marshal(configs)
+	return string(bytes)
+}
+
+func convertGoFunctionConfs(function *v1alpha2.Function) *GoFunctionConf {
+	inputSpecs := generateInputSpec(function.Spec.Input)
+	tuning := makeRuntimeTuning(function.Spec.Tuning)
+	conf := &GoFunctionConf{
+		FuncID:                      fmt.Sprintf("${%s}-%s", EnvShardID, string(function.UID)),
+		PulsarServiceURL:            "${brokerServiceURL}",
+		FuncVersion:                 "0",
+		MaxBufTuples:                int(tuning.maxBufferedTuples),
+		Port:                        int(GRPCPort.ContainerPort),
+		ClusterName:                 function.Spec.ClusterName,
+		Tenant:                      function.Spec.Tenant,
+		NameSpace:                   function.Spec.Namespace,
+		Name:                        function.Spec.Name,
+		LogTopic:                    function.Spec.LogTopic,
+		ProcessingGuarantees:        int32(convertProcessingGuarantee(function.Spec.ProcessingGuarantee)),
+		SecretsMap:                  marshalSecretsMap(function.Spec.SecretsMap),
 		Runtime:                     int32(proto.FunctionDetails_GO),
 		AutoACK:                     getBoolFromPtrOrDefault(function.Spec.AutoAck, true),
 		Parallelism:                 getInt32FromPtrOrDefault(function.Spec.Replicas, 1),
+		SubscriptionType:            int32(getSubscriptionType(function.Spec.RetainOrdering, function.Spec.ProcessingGuarantee)),
 		TimeoutMs:                   uint64(function.Spec.Timeout),
 		SubscriptionName:            function.Spec.SubscriptionName,
 		CleanupSubscription:         function.Spec.CleanupSubscription,
-		SourceSpecTopic:             function.Spec.Input.Topics[0],
-		SourceSchemaType:            "", // TODO: map schema type
-		IsRegexPatternSubscription:  function.Spec.Input.TopicPattern != "",
+		SubscriptionPosition:        int32(convertSubPosition(function.Spec.SubscriptionPosition)),
+		SourceInputSpecs:            marshalGoInputSpecs(inputSpecs),
 		SinkSpecTopic:               function.Spec.Output.Topic,
-		SinkSchemaType:              "", // TODO: map schema type
+		SinkSchemaType:              function.Spec.Output.SinkSchemaType,
 		CPU:                         float64(function.Spec.Resources.Requests.Cpu().Value()),
 		RAM:                         function.Spec.Resources.Requests.Memory().Value(),
 		Disk:                        function.Spec.Resources.Requests.Storage().Value(),
@@ -96,8 +135,51 @@ func convertGoFunctionConfs(function *v1alpha1.Function) *GoFunctionConf {
 		DeadLetterTopic:             function.Spec.DeadLetterTopic,
 		UserConfig:                  getUserConfig(function.Spec.FuncConfig),
 		MetricsPort:                 int(MetricsPort.ContainerPort),
-		ExpectedHealthCheckInterval: -1, // TurnOff BuiltIn HealthCheck to avoid instance exit
+		ExpectedHealthCheckInterval: tuning.expectedHealthCheckInterval,
+	}
+
+	// the runners reading a single input get the first topic, or the pattern when there is no topic
+	if topic := firstGoInputTopic(function.Spec.Input, inputSpecs); topic != "" {
+		spec := inputSpecs[topic]
+		conf.SourceSpecTopic = topic
+		conf.SourceSchemaType = spec.SchemaType
+		conf.IsRegexPatternSubscription = spec.IsRegexPattern
+		if spec.ReceiverQueueSize != nil {
+			conf.ReceiverQueueSize = spec.ReceiverQueueSize.Value
+		}
+	}
+	return conf
+}
+
+func firstGoInputTopic(input v1alpha1.InputConf, inputSpecs map[string]*proto.ConsumerSpec) string {
+	if len(input.Topics) > 0 {
+		return input.Topics[0]
+	}
+	if input.TopicPattern != "" {
+		return input.TopicPattern
+	}
+	topics := make([]string, 0, len(inputSpecs))
+	for topic := range inputSpecs {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	if len(topics) == 0 {
+		return ""
+	}
+	return topics[0]
+}
+
+// marshalGoInputSpecs returns the consumer specs in the json format read by the Go instance
+func marshalGoInputSpecs(inputSpecs map[string]*proto.ConsumerSpec) map[string]string {
+	specs := make(map[string]string, len(inputSpecs))
+	for topic, spec := range inputSpecs {
+		bytes, err := json.Marshal(spec)
+		if err != nil {
+			continue
+		}
+		specs[topic] = string(bytes)
 	}
+	return specs
 }
 
 func generateInputSpec(sourceConf v1alpha1.InputConf) map[string]*proto.ConsumerSpec {
@@ -152,7 +234,7 @@ func generateInputSpec(sourceConf v1alpha1.InputConf) map[string]*proto.Consumer
 	return inputSpecs
 }
 
-func generateFunctionInputSpec(function *v1alpha1.Function) *proto.SourceSpec {
+func generateFunctionInputSpec(function *v1alpha2.Function) *proto.SourceSpec {
 	inputSpecs := generateInputSpec(function.Spec.Input)
 
 	return &proto.SourceSpec{
@@ -170,7 +252,7 @@ func generateFunctionInputSpec(function *v1alpha1.Function) *proto.SourceSpec {
 	}
 }
 
-func generateFunctionOutputSpec(function *v1alpha1.Function) *proto.SinkSpec {
+func generateFunctionOutputSpec(function *v1alpha2.Function) *proto.SinkSpec {
 	sinkSpec := &proto.SinkSpec{
 		ClassName:                    "",
 		Configs:                      "",
@@ -207,7 +289,7 @@ func generateFunctionOutputSpec(function *v1alpha1.Function) *proto.SinkSpec {
 	return sinkSpec
 }
 
-func convertSourceDetails(source *v1alpha1.Source) *proto.FunctionDetails {
+func convertSourceDetails(source *v1alpha2.Source) *proto.FunctionDetails {
 	fd := &proto.FunctionDetails{
 		Tenant:               source.Spec.Tenant,
 		Namespace:            source.Spec.Namespace,
@@ -232,7 +314,7 @@ func convertSourceDetails(source *v1alpha1.Source) *proto.FunctionDetails {
 	return fd
 }
 
-func generateSourceInputSpec(source *v1alpha1.Source) *proto.SourceSpec {
+func generateSourceInputSpec(source *v1alpha2.Source) *proto.SourceSpec {
 	configs := getUserConfig(source.Spec.SourceConfig)
 	return &proto.SourceSpec{
 		ClassName:     source.Spec.ClassName,
@@ -241,7 +323,7 @@ func generateSourceInputSpec(source *v1alpha1.Source) *proto.SourceSpec {
 	}
 }
 
-func generateSourceOutputSpec(source *v1alpha1.Source) *proto.SinkSpec {
+func generateSourceOutputSpec(source *v1alpha2.Source) *proto.SinkSpec {
 	var producerSpec proto.ProducerSpec
 	var cryptoSpec *proto.CryptoSpec
 	if source.Spec.Output.ProducerConf != nil {
@@ -270,7 +352,7 @@ func generateSourceOutputSpec(source *v1alpha1.Source) *proto.SinkSpec {
 	}
 }
 
-func convertSinkDetails(sink *v1alpha1.Sink) *proto.FunctionDetails {
+func convertSinkDetails(sink *v1alpha2.Sink) *proto.FunctionDetails {
 	fd := &proto.FunctionDetails{
 		Tenant:               sink.Spec.Tenant,
 		Namespace:            sink.Spec.Namespace,
@@ -295,7 +377,7 @@ func convertSinkDetails(sink *v1alpha1.Sink) *proto.FunctionDetails {
 	return fd
 }
 
-func generateSinkInputSpec(sink *v1alpha1.Sink) *proto.SourceSpec {
+func generateSinkInputSpec(sink *v1alpha2.Sink) *proto.SourceSpec {
 	inputSpecs := generateInputSpec(sink.Spec.Input)
 
 	return &proto.SourceSpec{
@@ -318,7 +400,7 @@ func getSubscriptionType(retainOrdering bool, processingGuarantee v1alpha1.Proce
 	return proto.SubscriptionType_SHARED
 }
 
-func generateSinkOutputSpec(sink *v1alpha1.Sink) *proto.SinkSpec {
+func generateSinkOutputSpec(sink *v1alpha2.Sink) *proto.SinkSpec {
 	configs := getUserConfig(sink.Spec.SinkConfig)
 	return &proto.SinkSpec{
 		ClassName:     sink.Spec.ClassName,
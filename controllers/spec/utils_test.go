@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/controllers/proto"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -49,3 +50,37 @@ func TestMarshalSecretsMap(t *testing.T) {
 	marshaledSecretsNil := marshalSecretsMap(nil)
 	assert.Equal(t, marshaledSecretsNil, `{}`)
 }
+
+func TestConvertGoFunctionConfs(t *testing.T) {
+	function := makeGoFunctionSample(TestFunctionName)
+	receiverQueueSize := int32(50)
+	function.Spec.Input = v1alpha1.InputConf{
+		Topics:       []string{"persistent://public/default/input-a", "persistent://public/default/input-b"},
+		TopicPattern: "persistent://public/default/input-.*",
+		SourceSpecs: map[string]v1alpha1.ConsumerConfig{
+			"persistent://public/default/input-a": {SchemaType: "JSON", ReceiverQueueSize: &receiverQueueSize},
+		},
+	}
+	function.Spec.Output.SinkSchemaType = "AVRO"
+	function.Spec.RetainOrdering = true
+	function.Spec.SecretsMap = map[string]v1alpha1.SecretRef{"password": {Path: "credentials", Key: "password"}}
+
+	conf := convertGoFunctionConfs(function)
+	assert.Equal(t, conf.SourceSpecTopic, "persistent://public/default/input-a")
+	assert.Equal(t, conf.SourceSchemaType, "JSON")
+	assert.Equal(t, conf.ReceiverQueueSize, int32(50))
+	assert.False(t, conf.IsRegexPatternSubscription)
+	assert.Equal(t, len(conf.SourceInputSpecs), 3)
+	assert.Equal(t, conf.SourceInputSpecs["persistent://public/default/input-.*"], `{"isRegexPattern":true}`)
+	assert.Equal(t, conf.SourceInputSpecs["persistent://public/default/input-a"],
+		`{"schemaType":"JSON","receiverQueueSize":{"value":50}}`)
+	assert.Equal(t, conf.SinkSchemaType, "AVRO")
+	assert.Equal(t, conf.SubscriptionType, int32(proto.SubscriptionType_FAILOVER))
+	assert.Equal(t, conf.SecretsMap, `{"password":{"path":"credentials","key":"password"}}`)
+
+	// a pattern without topics does not panic
+	function.Spec.Input = v1alpha1.InputConf{TopicPattern: "persistent://public/default/input-.*"}
+	conf = convertGoFunctionConfs(function)
+	assert.Equal(t, conf.SourceSpecTopic, "persistent://public/default/input-.*")
+	assert.True(t, conf.IsRegexPatternSubscription)
+}
@@ -0,0 +1,115 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+// The Java instance runs the window functions with the WindowFunctionExecutor of Pulsar, which the Python instance
+// does not have. The Python window function executor below is shipped in the details ConfigMap of the windowed
+// Python functions, it windows the messages by processing time and evaluates the windows as the messages arrive.
+
+const (
+	// PythonWindowFunctionExecutorFile is the module of the Python window function executor in the details directory
+	PythonWindowFunctionExecutorFile = "window_function_executor.py"
+	// PythonWindowFunctionExecutorClass is the class name of the Python window function executor
+	PythonWindowFunctionExecutorClass = "window_function_executor.WindowFunctionExecutor"
+)
+
+// pythonWindowFunctionExecutor wraps the user function set in the window config, the user function is either a class
+// with a process(inputs, context) method or a function taking the inputs of the window. The messages are acked once
+// they are evicted from the windows.
+const pythonWindowFunctionExecutor = `import importlib
+import inspect
+import json
+import time
+
+WINDOW_CONFIG_KEY = "__WINDOWCONFIGS__"
+
+
+class WindowFunctionExecutor(object):
+    def __init__(self):
+        self.config = None
+        self.function = None
+        # the entries are the arrival time, the input, the message id and the topic of the messages
+        self.entries = []
+        self.count = 0
+        self.next_fire = None
+
+    def setup(self, context):
+        config = context.get_user_config_value(WINDOW_CONFIG_KEY)
+        if isinstance(config, str):
+            config = json.loads(config)
+        self.config = config
+        module_name, _, class_name = config["actualWindowFunctionClassName"].rpartition(".")
+        function = getattr(importlib.import_module(module_name), class_name)
+        self.function = function() if inspect.isclass(function) else function
+
+    def process(self, input, context):
+        if self.function is None:
+            self.setup(context)
+        now = time.time() * 1000
+        self.entries.append((now, input, context.get_message_id(), context.get_current_message_topic_name()))
+        self.count += 1
+        if not self.fires(now):
+            return None
+        result = self.call([entry[1] for entry in self.window(now)], context)
+        self.evict(now, context)
+        return result
+
+    def fires(self, now):
+        sliding_count = self.config.get("slidingIntervalCount")
+        if sliding_count:
+            if self.count < sliding_count:
+                return False
+            self.count = 0
+            return True
+        interval = self.config["slidingIntervalDurationMs"]
+        if self.next_fire is None:
+            self.next_fire = now + interval
+        if now < self.next_fire:
+            return False
+        while self.next_fire <= now:
+            self.next_fire += interval
+        return True
+
+    def window(self, now):
+        length_count = self.config.get("windowLengthCount")
+        if length_count:
+            return self.entries[-length_count:]
+        start = now - self.config["windowLengthDurationMs"]
+        return [entry for entry in self.entries if entry[0] > start]
+
+    def evict(self, now, context):
+        length_count = self.config.get("windowLengthCount")
+        if length_count:
+            # the next window takes the last messages, it only keeps the ones arrived before the next slide
+            keep = max(length_count - (self.config.get("slidingIntervalCount") or 0), 0)
+            evicted = self.entries[:len(self.entries) - keep]
+            self.entries = self.entries[len(self.entries) - keep:]
+        else:
+            start = now - self.config["windowLengthDurationMs"]
+            if self.next_fire is not None and not self.config.get("slidingIntervalCount"):
+                start = self.next_fire - self.config["windowLengthDurationMs"]
+            evicted = [entry for entry in self.entries if entry[0] <= start]
+            self.entries = [entry for entry in self.entries if entry[0] > start]
+        for entry in evicted:
+            context.ack(entry[2], entry[3])
+
+    def call(self, inputs, context):
+        if hasattr(self.function, "process"):
+            return self.function.process(inputs, context)
+        return self.function(inputs)
+`
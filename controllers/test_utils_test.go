@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -59,15 +60,15 @@ func makeSamplePulsarConfig() *v1.ConfigMap {
 	}
 }
 
-func makeFunctionSample(functionName string) *v1alpha1.Function {
+func makeFunctionSample(functionName string) *v1alpha2.Function {
 	maxPending := int32(1000)
 	replicas := int32(1)
 	maxReplicas := int32(5)
 	trueVal := true
-	return &v1alpha1.Function{
+	return &v1alpha2.Function{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Function",
-			APIVersion: "compute.functionmesh.io/v1alpha1",
+			APIVersion: "compute.functionmesh.io/v1alpha2",
 		},
 		ObjectMeta: *makeSampleObjectMeta(functionName),
 		Spec: v1alpha1.FunctionSpec{
@@ -109,7 +110,7 @@ func makeFunctionSample(functionName string) *v1alpha1.Function {
 	}
 }
 
-func makeFunctionSampleWithCryptoEnabled() *v1alpha1.Function {
+func makeFunctionSampleWithCryptoEnabled() *v1alpha2.Function {
 	function := makeFunctionSample(TestFunctionE2EName)
 	function.Spec.Input = v1alpha1.InputConf{
 		Topics: []string{
@@ -144,7 +145,7 @@ func makeFunctionSampleWithCryptoEnabled() *v1alpha1.Function {
 	return function
 }
 
-func makeFunctionSampleWithKeyBasedBatcher() *v1alpha1.Function {
+func makeFunctionSampleWithKeyBasedBatcher() *v1alpha2.Function {
 	function := makeFunctionSample(TestFunctionKeyBatcherName)
 	function.Spec.Output = v1alpha1.OutputConf{
 		Topic: "persistent://public/default/java-function-output-topic",
@@ -155,7 +156,7 @@ func makeFunctionSampleWithKeyBasedBatcher() *v1alpha1.Function {
 	return function
 }
 
-func makeFunctionMeshSample() *v1alpha1.FunctionMesh {
+func makeFunctionMeshSample() *v1alpha2.FunctionMesh {
 	inputTopic := "persistent://public/default/functionmesh-input-topic"
 	middleTopic := "persistent://public/default/mid-topic"
 	outputTopic := "persistent://public/default/functionmesh-output-topic"
@@ -166,10 +167,10 @@ func makeFunctionMeshSample() *v1alpha1.FunctionMesh {
 	overwriteFunctionInputOutput(function1, inputTopic, middleTopic)
 	overwriteFunctionInputOutput(function2, middleTopic, outputTopic)
 
-	return &v1alpha1.FunctionMesh{
+	return &v1alpha2.FunctionMesh{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "FunctionMesh",
-			APIVersion: "compute.functionmesh.io/v1alpha1",
+			APIVersion: "compute.functionmesh.io/v1alpha2",
 		},
 		ObjectMeta: *makeSampleObjectMeta(TestFunctionMeshName),
 		Spec: v1alpha1.FunctionMeshSpec{
@@ -181,7 +182,7 @@ func makeFunctionMeshSample() *v1alpha1.FunctionMesh {
 	}
 }
 
-func makeSinkSample() *v1alpha1.Sink {
+func makeSinkSample() *v1alpha2.Sink {
 	replicas := int32(1)
 	maxReplicas := int32(1)
 	trueVal := true
@@ -192,10 +193,10 @@ func makeSinkSample() *v1alpha1.Sink {
 		"username":         "elastic",
 		"password":         "wJ757TmoXEd941kXm07Z2GW3",
 	})
-	return &v1alpha1.Sink{
+	return &v1alpha2.Sink{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Sink",
-			APIVersion: "compute.functionmesh.io/v1alpha1",
+			APIVersion: "compute.functionmesh.io/v1alpha2",
 		},
 		ObjectMeta: *makeSampleObjectMeta(TestSinkName),
 		Spec: v1alpha1.SinkSpec{
@@ -232,7 +233,7 @@ func makeSinkSample() *v1alpha1.Sink {
 	}
 }
 
-func makeSourceSample() *v1alpha1.Source {
+func makeSourceSample() *v1alpha2.Source {
 	replicas := int32(1)
 	maxReplicas := int32(1)
 	sourceConfig := v1alpha1.NewConfig(map[string]interface{}{
@@ -244,10 +245,10 @@ func makeSourceSample() *v1alpha1.Source {
 		"database.whitelist": "inventory",
 		"pulsar.service.url": "pulsar://test-pulsar-broker.default.svc.cluster.local:6650",
 	})
-	return &v1alpha1.Source{
+	return &v1alpha2.Source{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Sink",
-			APIVersion: "compute.functionmesh.io/v1alpha1",
+			APIVersion: "compute.functionmesh.io/v1alpha2",
 		},
 		ObjectMeta: *makeSampleObjectMeta(TestSourceName),
 		Spec: v1alpha1.SourceSpec{
@@ -284,7 +285,7 @@ func makeSourceSample() *v1alpha1.Source {
 	}
 }
 
-func overwriteFunctionInputOutput(function *v1alpha1.Function, input string, output string) {
+func overwriteFunctionInputOutput(function *v1alpha2.Function, input string, output string) {
 	function.Spec.Input = v1alpha1.InputConf{
 		Topics: []string{
 			input,
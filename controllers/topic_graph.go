@@ -0,0 +1,195 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"github.com/streamnative/pulsarctl/pkg/pulsar"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// topicGraphCheckInterval is the interval of checking the input topics produced outside of the mesh in Pulsar,
+// when the mesh is not changed
+const topicGraphCheckInterval = 5 * time.Minute
+
+// ObserveTopicGraph reports the problems of the topics connecting the components of the mesh in the status.
+// The cycles and the schema mismatches are rejected by the webhook, they are reported here for the meshes
+// created without the webhook. It returns the time until the next check of the input topics produced outside
+// of the mesh, or zero when the mesh has no such topics.
+func (r *FunctionMeshReconciler) ObserveTopicGraph(ctx context.Context, mesh *v1alpha2.FunctionMesh) time.Duration {
+	previous := mesh.Status.TopicGraph
+	if previous != nil && previous.ObservedGeneration == mesh.Generation &&
+		time.Since(previous.LastCheckTime.Time) < topicGraphCheckInterval {
+		return nextTopicGraphCheck(mesh, previous)
+	}
+
+	graph := v1alpha1.NewTopicGraph(&mesh.Spec)
+	status := &v1alpha2.TopicGraphStatus{
+		ObservedGeneration: mesh.Generation,
+		LastCheckTime:      metav1.Now(),
+		UnconsumedOutputs:  graph.UnconsumedOutputs(),
+		Cycles:             graph.Cycles(),
+		SchemaMismatches:   graph.SchemaMismatches(),
+	}
+
+	missing, err := r.findMissingTopics(ctx, mesh, graph.ExternalInputs())
+	if err != nil {
+		r.Log.Error(err, "failed to check the input topics of the mesh", "name", mesh.Name)
+		// keep the last known missing inputs, and check again on the next reconcile
+		status.LastCheckTime = metav1.Time{}
+		if previous != nil {
+			status.MissingInputs = previous.MissingInputs
+		}
+	} else {
+		status.MissingInputs = missing
+	}
+
+	if previous == nil || !equality.Semantic.DeepEqual(problemsOf(previous), problemsOf(status)) {
+		if problems := describeTopicGraphProblems(status); problems != "" {
+			r.Recorder.Event(mesh, corev1.EventTypeWarning, EventReasonTopicGraph, problems)
+		}
+	}
+	mesh.Status.TopicGraph = status
+	return nextTopicGraphCheck(mesh, status)
+}
+
+// nextTopicGraphCheck returns the time remaining before the external input topics of the mesh are checked again,
+// the topics are checked again after a full interval when the last check failed
+func nextTopicGraphCheck(mesh *v1alpha2.FunctionMesh, status *v1alpha2.TopicGraphStatus) time.Duration {
+	if len(v1alpha1.NewTopicGraph(&mesh.Spec).ExternalInputs()) == 0 {
+		return 0
+	}
+	if status.LastCheckTime.IsZero() {
+		return topicGraphCheckInterval
+	}
+	if remaining := topicGraphCheckInterval - time.Since(status.LastCheckTime.Time); remaining > 0 {
+		return remaining
+	}
+	return time.Second
+}
+
+// findMissingTopics returns the topics which do not exist in Pulsar, the topics are checked with the
+// Pulsar configs of the first component of the mesh
+func (r *FunctionMeshReconciler) findMissingTopics(ctx context.Context, mesh *v1alpha2.FunctionMesh,
+	topics []string) ([]string, error) {
+	if len(topics) == 0 {
+		return nil, nil
+	}
+	messaging := meshPulsarMessaging(mesh)
+	if messaging == nil {
+		return nil, fmt.Errorf("no component of the mesh has the pulsar configs")
+	}
+	admin, err := newPulsarAdmin(ctx, r.Client, mesh.Namespace, messaging)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, topic := range topics {
+		exists, err := topicExists(admin, topic)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			missing = append(missing, topic)
+		}
+	}
+	return missing, nil
+}
+
+func meshPulsarMessaging(mesh *v1alpha2.FunctionMesh) *v1alpha1.PulsarMessaging {
+	for _, function := range mesh.Spec.Functions {
+		if function.Pulsar != nil {
+			return function.Pulsar
+		}
+	}
+	for _, source := range mesh.Spec.Sources {
+		if source.Pulsar != nil {
+			return source.Pulsar
+		}
+	}
+	for _, sink := range mesh.Spec.Sinks {
+		if sink.Pulsar != nil {
+			return sink.Pulsar
+		}
+	}
+	if mesh.Spec.Defaults != nil {
+		return mesh.Spec.Defaults.Pulsar
+	}
+	return nil
+}
+
+// topicExists returns true if the topic is a partitioned topic or an existing non-partitioned topic
+func topicExists(admin pulsar.Client, topic string) (bool, error) {
+	topicName, err := utils.GetTopicName(topic)
+	if err != nil {
+		return false, err
+	}
+	metadata, err := admin.Topics().GetMetadata(*topicName)
+	if err != nil {
+		return false, err
+	}
+	if metadata.Partitions > 0 {
+		return true, nil
+	}
+	if _, err = admin.Topics().GetStats(*topicName); err != nil {
+		if isPulsarNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// problemsOf returns a copy of the status without the fields of the check itself
+func problemsOf(status *v1alpha2.TopicGraphStatus) *v1alpha2.TopicGraphStatus {
+	problems := status.DeepCopy()
+	problems.ObservedGeneration = 0
+	problems.LastCheckTime = metav1.Time{}
+	return problems
+}
+
+func describeTopicGraphProblems(status *v1alpha2.TopicGraphStatus) string {
+	var problems []string
+	if len(status.MissingInputs) > 0 {
+		problems = append(problems, fmt.Sprintf("input topics not produced nor existing: %s",
+			strings.Join(status.MissingInputs, ", ")))
+	}
+	if len(status.UnconsumedOutputs) > 0 {
+		problems = append(problems, fmt.Sprintf("output topics not consumed: %s",
+			strings.Join(status.UnconsumedOutputs, ", ")))
+	}
+	for _, cycle := range status.Cycles {
+		problems = append(problems, fmt.Sprintf("cycle: %s", strings.Join(cycle, " -> ")))
+	}
+	for _, mismatch := range status.SchemaMismatches {
+		problems = append(problems, fmt.Sprintf("schema type mismatch on %s: %s produces %s, %s consumes %s",
+			mismatch.Topic, mismatch.Producer, mismatch.ProducerSchemaType,
+			mismatch.Consumer, mismatch.ConsumerSchemaType))
+	}
+	return strings.Join(problems, "; ")
+}
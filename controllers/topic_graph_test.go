@@ -0,0 +1,85 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/streamnative/function-mesh/api/v1alpha1"
+	"github.com/streamnative/function-mesh/api/v1alpha2"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNextTopicGraphCheck(t *testing.T) {
+	closed := &v1alpha2.FunctionMesh{Spec: v1alpha1.FunctionMeshSpec{
+		Sources: []v1alpha1.SourceSpec{{Name: "src", Output: v1alpha1.OutputConf{Topic: "a"}}},
+		Sinks:   []v1alpha1.SinkSpec{{Name: "sink", Input: v1alpha1.InputConf{Topics: []string{"a"}}}},
+	}}
+	external := &v1alpha2.FunctionMesh{Spec: v1alpha1.FunctionMeshSpec{
+		Sinks: []v1alpha1.SinkSpec{{Name: "sink", Input: v1alpha1.InputConf{Topics: []string{"a"}}}},
+	}}
+
+	testCases := []struct {
+		name          string
+		mesh          *v1alpha2.FunctionMesh
+		lastCheckTime metav1.Time
+		min, max      time.Duration
+	}{
+		{
+			name:          "no external inputs",
+			mesh:          closed,
+			lastCheckTime: metav1.Now(),
+		},
+		{
+			name:          "just checked",
+			mesh:          external,
+			lastCheckTime: metav1.Now(),
+			min:           topicGraphCheckInterval - time.Minute,
+			max:           topicGraphCheckInterval,
+		},
+		{
+			name:          "checked before",
+			mesh:          external,
+			lastCheckTime: metav1.NewTime(time.Now().Add(-3 * time.Minute)),
+			min:           topicGraphCheckInterval - 4*time.Minute,
+			max:           topicGraphCheckInterval - 3*time.Minute,
+		},
+		{
+			name:          "check overdue",
+			mesh:          external,
+			lastCheckTime: metav1.NewTime(time.Now().Add(-2 * topicGraphCheckInterval)),
+			min:           time.Second,
+			max:           time.Second,
+		},
+		{
+			name: "check failed",
+			mesh: external,
+			min:  topicGraphCheckInterval,
+			max:  topicGraphCheckInterval,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			next := nextTopicGraphCheck(tc.mesh, &v1alpha2.TopicGraphStatus{LastCheckTime: tc.lastCheckTime})
+			assert.Assert(t, next >= tc.min && next <= tc.max, next)
+		})
+	}
+}
@@ -32,6 +32,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	computev1alpha1 "github.com/streamnative/function-mesh/api/v1alpha1"
+	computev1alpha2 "github.com/streamnative/function-mesh/api/v1alpha2"
 	"github.com/streamnative/function-mesh/controllers"
 	// +kubebuilder:scaffold:imports
 )
@@ -45,16 +46,17 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(computev1alpha1.AddToScheme(scheme))
+	utilruntime.Must(computev1alpha2.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 func main() {
-	var metricsAddr, pprofAddr string
+	var metricsAddr, pprofAddr, externalMetricsAddr string
 	var leaderElectionID string
 	var leaderElectionNamespace string
 	var certDir string
 	var healthProbeAddr string
-	var enableLeaderElection, enablePprof bool
+	var enableLeaderElection, enablePprof, enableExternalMetrics bool
 	var configFile string
 	var namespace string
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
@@ -74,6 +76,11 @@ func main() {
 		"Namespace if specified restricts the manager's cache to watch objects in the desired namespace. Defaults to all namespaces.")
 	flag.BoolVar(&enablePprof, "enable-pprof", false, "Enable pprof for controller manager.")
 	flag.StringVar(&pprofAddr, "pprof-addr", ":8090", "The address the pprof binds to.")
+	flag.BoolVar(&enableExternalMetrics, "enable-external-metrics", false,
+		"Serve the subscription backlog through the external metrics API, "+
+			"required by the backlog based builtin autoscaling rules.")
+	flag.StringVar(&externalMetricsAddr, "external-metrics-addr", ":6443",
+		"The address the external metrics API binds to, it serves the webhook certificate.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
@@ -115,42 +122,72 @@ func main() {
 	// required because of https://github.com/operator-framework/operator-lifecycle-manager/issues/1523
 	if os.Getenv("ENABLE_FUNCTION_MESH_CONTROLLER") != "false" {
 		if err = (&controllers.FunctionMeshReconciler{
-			Client: mgr.GetClient(),
-			Log:    ctrl.Log.WithName("controllers").WithName("FunctionMesh"),
-			Scheme: mgr.GetScheme(),
+			Client:   mgr.GetClient(),
+			Log:      ctrl.Log.WithName("controllers").WithName("FunctionMesh"),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("functionmesh-controller"),
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "FunctionMesh")
 			os.Exit(1)
 		}
 	}
 	if err = (&controllers.FunctionReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Function"),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("Function"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("function-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Function")
 		os.Exit(1)
 	}
 	if err = (&controllers.SourceReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Source"),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("Source"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("source-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Source")
 		os.Exit(1)
 	}
 	if err = (&controllers.SinkReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Sink"),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("Sink"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("sink-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Sink")
 		os.Exit(1)
 	}
+	if err = (&controllers.PulsarConnectionReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("PulsarConnection"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("pulsarconnection-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PulsarConnection")
+		os.Exit(1)
+	}
+	if err = (&controllers.ClusterPulsarConnectionReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("ClusterPulsarConnection"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("clusterpulsarconnection-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterPulsarConnection")
+		os.Exit(1)
+	}
 
-	// enable the webhook service by default
+	if err = controllers.SetupFleetCollector(mgr.GetClient(),
+		ctrl.Log.WithName("metrics").WithName("Fleet")); err != nil {
+		setupLog.Error(err, "unable to register the fleet metrics")
+		os.Exit(1)
+	}
+
+	// enable the webhook service by default, the conversion webhook between v1alpha1 and v1alpha2
+	// is registered together with the webhooks of the hub types.
 	// Disable function-mesh webhook with `ENABLE_WEBHOOKS=false` when we run locally.
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		computev1alpha1.WebhookRejections = controllers.WebhookRejectionRecorder{}
 		if err = (&computev1alpha1.Function{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Function")
 			os.Exit(1)
@@ -163,6 +200,22 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Sink")
 			os.Exit(1)
 		}
+		if err = (&computev1alpha1.FunctionMesh{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "FunctionMesh")
+			os.Exit(1)
+		}
+	}
+	if enableExternalMetrics {
+		if err = (&controllers.BacklogMetricsServer{
+			Client:      mgr.GetClient(),
+			Reader:      mgr.GetAPIReader(),
+			Log:         ctrl.Log.WithName("metrics").WithName("Backlog"),
+			BindAddress: externalMetricsAddr,
+			CertDir:     certDir,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create external metrics server")
+			os.Exit(1)
+		}
 	}
 	// +kubebuilder:scaffold:builder
 